@@ -13,6 +13,7 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/mqrc81/IDPA-Jahreszahlen/backend/database"
+	"github.com/mqrc81/IDPA-Jahreszahlen/backend/storage"
 	"github.com/mqrc81/IDPA-Jahreszahlen/backend/web"
 )
 
@@ -43,6 +44,13 @@ func main() {
 		log.Fatalf("error initializing new database store: %v", err)
 	}
 
+	// Initialize blob store for uploaded files, which get stored locally and
+	// served as static files
+	blobs, err := storage.NewLocalStore(web.UploadPath, "/"+web.UploadPath)
+	if err != nil {
+		log.Fatalf("error initializing new blob store: %v", err)
+	}
+
 	// Initialize session manager
 	sessions, err := web.NewSessionManager(dataSourceName)
 	if err != nil {
@@ -55,8 +63,12 @@ func main() {
 		log.Fatalf("error generating csrf-key: %v", err)
 	}
 
+	// Purge topics, events and users in the trash after the retention period
+	// in the background
+	go web.RunTrashPurge(store, blobs)
+
 	// Initialize HTTP-handlers, including router and middleware
-	handler := web.NewHandler(store, sessions, csrfKey)
+	handler := web.NewHandler(store, blobs, sessions, csrfKey)
 
 	// Listen on the TCP network address and call Serve with handler to handle
 	// requests on incoming connections
@@ -0,0 +1,73 @@
+// The database store evolving around achievements of users, with all
+// necessary methods that access the database.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// AchievementStore is the MySQL database access object.
+type AchievementStore struct {
+	*sqlx.DB
+}
+
+// GetAchievementsByUser gets all achievements of a user, sorted by unlock
+// date.
+func (store *AchievementStore) GetAchievementsByUser(userID int) ([]x.Achievement, error) {
+	var achievements []x.Achievement
+
+	query := `
+		SELECT *
+		FROM achievements
+		WHERE user_id = ?
+		ORDER BY date
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&achievements, query, userID); err != nil {
+		return []x.Achievement{}, fmt.Errorf("error getting achievements of user: %w", err)
+	}
+
+	return achievements, nil
+}
+
+// CreateAchievements creates multiple new achievements at once, which either
+// all get created or none of them. Achievements, which a user has already
+// unlocked, keep their original unlock date.
+func (store *AchievementStore) CreateAchievements(achievements []x.Achievement) error {
+
+	query := `
+		INSERT IGNORE INTO achievements(user_id, achievement, date)
+		VALUES (?, ?, ?)
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statements for each achievement
+	for _, achievement := range achievements {
+		if _, err = tx.Exec(query,
+			achievement.UserID,
+			achievement.Key,
+			achievement.Date,
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating achievement: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
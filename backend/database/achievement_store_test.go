@@ -0,0 +1,179 @@
+// Collection of tests for the database access layer of functions evolving
+// around achievements of users.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tAchievement is a mock achievement for testing purposes
+	tAchievement = x.Achievement{
+		UserID: 1,
+		Key:    "perfect_phase3",
+		Date:   time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	// tAchievement2 is a mock achievement for testing purposes
+	tAchievement2 = x.Achievement{
+		UserID: 1,
+		Key:    "first_place",
+		Date:   time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	// nilAchievements is a nil slice of achievements
+	nilAchievements []x.Achievement
+)
+
+// TestGetAchievementsByUser tests getting all achievements of a user.
+func TestGetAchievementsByUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &AchievementStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM achievements WHERE user_id = \\? ORDER BY date"
+
+	table := []string{"user_id", "achievement", "date"}
+
+	// Declare test cases
+	tests := []struct {
+		name             string
+		userID           int
+		mock             func(userID int)
+		wantAchievements []x.Achievement
+		wantError        bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: 1,
+			mock: func(userID int) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tAchievement.UserID, tAchievement.Key, tAchievement.Date).
+					AddRow(tAchievement2.UserID, tAchievement2.Key, tAchievement2.Date)
+
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
+			},
+			wantAchievements: []x.Achievement{tAchievement, tAchievement2},
+			wantError:        false,
+		},
+		{
+			// When the achievements table doesn't exist
+			name:   "#2 ERROR",
+			userID: 1,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("table achievements does not exist"))
+			},
+			wantAchievements: nilAchievements,
+			wantError:        true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			achievements, err := store.GetAchievementsByUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetAchievementsByUser() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(achievements, test.wantAchievements) {
+				t.Errorf("GetAchievementsByUser() = %v, want %v", achievements, test.wantAchievements)
+			}
+		})
+	}
+}
+
+// TestCreateAchievements tests creating multiple new achievements at once.
+func TestCreateAchievements(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &AchievementStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT IGNORE INTO achievements"
+
+	tAchievements := []x.Achievement{tAchievement, tAchievement2}
+
+	// Declare test cases
+	tests := []struct {
+		name         string
+		achievements []x.Achievement
+		mock         func(achievements []x.Achievement)
+		wantError    bool
+	}{
+		{
+			// When everything works as intended
+			name:         "#1 OK",
+			achievements: tAchievements,
+			mock: func(achievements []x.Achievement) {
+				mock.ExpectBegin()
+				for _, achievement := range achievements {
+					mock.ExpectExec(queryMatch).WithArgs(achievement.UserID, achievement.Key, achievement.Date).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+			},
+			wantError: false,
+		},
+		{
+			// When one of the achievements can't be created, which rolls back
+			// the entire transaction
+			name:         "#2 ROLLBACK",
+			achievements: tAchievements,
+			mock: func(achievements []x.Achievement) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).
+					WithArgs(achievements[0].UserID, achievements[0].Key, achievements[0].Date).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(queryMatch).
+					WithArgs(achievements[1].UserID, achievements[1].Key, achievements[1].Date).
+					WillReturnError(errors.New("user does not exist"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+		{
+			// When the transaction can't be started
+			name:         "#3 BEGIN FAILED",
+			achievements: tAchievements,
+			mock: func(achievements []x.Achievement) {
+				mock.ExpectBegin().WillReturnError(errors.New("connection lost"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.achievements)
+
+			err := store.CreateAchievements(test.achievements)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateAchievements() error = %v, want error %v", err, test.wantError)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateAchievements() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
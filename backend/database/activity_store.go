@@ -0,0 +1,60 @@
+// The database store evolving around the daily activity of users, with all
+// necessary methods that access the database.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// ActivityStore is the MySQL database access object.
+type ActivityStore struct {
+	*sqlx.DB
+}
+
+// GetActivitiesByUser gets the activity of a user on all days with any
+// activity, sorted by day descending.
+func (store *ActivityStore) GetActivitiesByUser(userID int) ([]x.Activity, error) {
+	var activities []x.Activity
+
+	query := `
+		SELECT *
+		FROM activities
+		WHERE user_id = ?
+		ORDER BY day DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&activities, query, userID); err != nil {
+		return []x.Activity{}, fmt.Errorf("error getting activities of user: %w", err)
+	}
+
+	return activities, nil
+}
+
+// AddActivity adds quizzes and practice sessions to the activity of a user on
+// a day, which gets created if there isn't any activity on that day yet.
+func (store *ActivityStore) AddActivity(activity *x.Activity) error {
+
+	query := `
+		INSERT INTO activities(user_id, day, quizzes, practices)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE quizzes = quizzes + VALUES(quizzes), practices = practices + VALUES(practices)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		activity.UserID,
+		activity.Day.Format("2006-01-02"),
+		activity.Quizzes,
+		activity.Practices,
+	); err != nil {
+		return fmt.Errorf("error adding activity: %w", err)
+	}
+
+	return nil
+}
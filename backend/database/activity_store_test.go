@@ -0,0 +1,163 @@
+// Collection of tests for the database access layer of functions evolving
+// around the daily activity of users.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tActivity is a mock activity for testing purposes
+	tActivity = x.Activity{
+		UserID:    1,
+		Day:       time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+		Quizzes:   2,
+		Practices: 1,
+	}
+
+	// tActivity2 is a mock activity for testing purposes
+	tActivity2 = x.Activity{
+		UserID:    1,
+		Day:       time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		Quizzes:   1,
+		Practices: 0,
+	}
+
+	// nilActivities is a nil slice of activities
+	nilActivities []x.Activity
+)
+
+// TestGetActivitiesByUser tests getting the daily activity of a user.
+func TestGetActivitiesByUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ActivityStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM activities WHERE user_id = \\? ORDER BY day DESC"
+
+	table := []string{"user_id", "day", "quizzes", "practices"}
+
+	// Declare test cases
+	tests := []struct {
+		name           string
+		userID         int
+		mock           func(userID int)
+		wantActivities []x.Activity
+		wantError      bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: 1,
+			mock: func(userID int) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tActivity.UserID, tActivity.Day, tActivity.Quizzes, tActivity.Practices).
+					AddRow(tActivity2.UserID, tActivity2.Day, tActivity2.Quizzes, tActivity2.Practices)
+
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
+			},
+			wantActivities: []x.Activity{tActivity, tActivity2},
+			wantError:      false,
+		},
+		{
+			// When the activities table doesn't exist
+			name:   "#2 ERROR",
+			userID: 1,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("table activities does not exist"))
+			},
+			wantActivities: nilActivities,
+			wantError:      true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			activities, err := store.GetActivitiesByUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetActivitiesByUser() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(activities, test.wantActivities) {
+				t.Errorf("GetActivitiesByUser() = %v, want %v", activities, test.wantActivities)
+			}
+		})
+	}
+}
+
+// TestAddActivity tests adding quizzes and practice sessions to the activity
+// of a user on a day.
+func TestAddActivity(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ActivityStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO activities(.+) ON DUPLICATE KEY UPDATE"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		activity  x.Activity
+		mock      func(activity x.Activity)
+		wantError bool
+	}{
+		{
+			// When everything works as intended, whereas only the calendar
+			// day is stored
+			name: "#1 OK",
+			activity: x.Activity{
+				UserID:  1,
+				Day:     time.Date(2021, 3, 2, 18, 30, 0, 0, time.UTC),
+				Quizzes: 1,
+			},
+			mock: func(activity x.Activity) {
+				mock.ExpectExec(queryMatch).WithArgs(activity.UserID, "2021-03-02", 1, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When the user doesn't exist
+			name:     "#2 ERROR",
+			activity: tActivity,
+			mock: func(activity x.Activity) {
+				mock.ExpectExec(queryMatch).
+					WithArgs(activity.UserID, "2021-03-02", activity.Quizzes, activity.Practices).
+					WillReturnError(errors.New("user does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.activity)
+
+			err := store.AddActivity(&test.activity)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("AddActivity() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
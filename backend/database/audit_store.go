@@ -0,0 +1,126 @@
+// The database store evolving around the audit log, with all necessary methods
+// that access the database. The audit log is append-only, thus there are no
+// methods to update or delete audit entries.
+
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// AuditStore is the MySQL database access object.
+type AuditStore struct {
+	*sqlx.DB
+}
+
+// GetAuditEntries gets audit entries matching the filter, sorted by date
+// descending.
+func (store *AuditStore) GetAuditEntries(filter x.AuditFilter) ([]x.AuditEntry, error) {
+	var entries []x.AuditEntry
+
+	where, args := auditConditions(filter)
+	query := `
+		SELECT a.*,
+		       COALESCE(u.username, '') AS user_name
+		FROM audit_log a
+		    LEFT JOIN users u ON u.user_id = a.user_id
+		` + where + `
+		ORDER BY a.date DESC, a.audit_id DESC
+		LIMIT ? OFFSET ?
+		`
+	args = append(args, filter.Limit, filter.Offset)
+
+	// Execute prepared statement
+	if err := store.Select(&entries, query, args...); err != nil {
+		return []x.AuditEntry{}, fmt.Errorf("error getting audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountAuditEntries gets amount of audit entries matching the filter.
+func (store *AuditStore) CountAuditEntries(filter x.AuditFilter) (int, error) {
+	var entriesCount int
+
+	where, args := auditConditions(filter)
+	query := `
+		SELECT COUNT(*)
+		FROM audit_log a
+		    LEFT JOIN users u ON u.user_id = a.user_id
+		` + where
+
+	// Execute prepared statement
+	if err := store.Get(&entriesCount, query, args...); err != nil {
+		return 0, fmt.Errorf("error getting number of audit entries: %w", err)
+	}
+
+	return entriesCount, nil
+}
+
+// CreateAuditEntry creates a new audit entry.
+func (store *AuditStore) CreateAuditEntry(entry *x.AuditEntry) error {
+
+	query := `
+		INSERT INTO audit_log(user_id, action, target_type, target_id, snapshot_before, snapshot_after, ip, date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		entry.UserID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.Before,
+		entry.After,
+		entry.IP,
+		entry.Date,
+	); err != nil {
+		return fmt.Errorf("error creating audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// auditConditions generates the WHERE-clause and its arguments for the
+// criteria of a filter, which are not empty.
+func auditConditions(filter x.AuditFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.UserName != "" {
+		conditions = append(conditions, "u.username = ?")
+		args = append(args, filter.UserName)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "a.action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "a.target_type = ?")
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		conditions = append(conditions, "a.target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "a.date >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "a.date < ?")
+		args = append(args, filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
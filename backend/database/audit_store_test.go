@@ -0,0 +1,287 @@
+// Collection of tests for the database access layer of functions evolving
+// around the audit log.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tAuditEntry is a mock audit entry for testing purposes
+	tAuditEntry = x.AuditEntry{
+		AuditID:    1,
+		UserID:     1,
+		Action:     "update",
+		TargetType: "event",
+		TargetID:   1,
+		Before:     `{"EventID":1,"Name":"Event 1"}`,
+		After:      `{"EventID":1,"Name":"Event 2"}`,
+		IP:         "127.0.0.1",
+		Date:       time.Now(),
+		UserName:   "user_1",
+	}
+
+	// tAuditEntry2 is a mock audit entry for testing purposes
+	tAuditEntry2 = x.AuditEntry{
+		AuditID:    2,
+		UserID:     1,
+		Action:     "create",
+		TargetType: "topic",
+		TargetID:   2,
+		After:      `{"TopicID":2,"Name":"Topic 2"}`,
+		IP:         "127.0.0.1",
+		Date:       time.Now().Add(time.Hour * 1),
+		UserName:   "user_1",
+	}
+
+	// nilAuditEntries is a nil slice of audit entries
+	nilAuditEntries []x.AuditEntry
+)
+
+// TestGetAuditEntries tests getting audit entries matching a filter.
+func TestGetAuditEntries(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &AuditStore{DB: db}
+	defer db.Close()
+
+	tAuditEntries := []x.AuditEntry{tAuditEntry, tAuditEntry2}
+	table := []string{"audit_id", "user_id", "action", "target_type", "target_id", "snapshot_before",
+		"snapshot_after", "ip", "date", "user_name"}
+
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		filter      x.AuditFilter
+		mock        func(filter x.AuditFilter)
+		wantEntries []x.AuditEntry
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			filter: x.AuditFilter{Limit: 25, Offset: 0},
+			mock: func(filter x.AuditFilter) {
+				rows := sqlmock.NewRows(table)
+				for _, entry := range tAuditEntries {
+					rows = rows.AddRow(entry.AuditID, entry.UserID, entry.Action, entry.TargetType,
+						entry.TargetID, entry.Before, entry.After, entry.IP, entry.Date, entry.UserName)
+				}
+
+				mock.ExpectQuery("SELECT (.+) FROM audit_log a (.+) ORDER BY").
+					WithArgs(filter.Limit, filter.Offset).WillReturnRows(rows)
+			},
+			wantEntries: tAuditEntries,
+			wantError:   false,
+		},
+		{
+			// When filtering by every criteria
+			name: "#2 OK (FILTERED)",
+			filter: x.AuditFilter{UserName: "user_1", Action: "update", TargetType: "event", TargetID: 1,
+				From: from, To: to, Limit: 25, Offset: 25},
+			mock: func(filter x.AuditFilter) {
+				rows := sqlmock.NewRows(table).AddRow(tAuditEntry.AuditID, tAuditEntry.UserID,
+					tAuditEntry.Action, tAuditEntry.TargetType, tAuditEntry.TargetID, tAuditEntry.Before,
+					tAuditEntry.After, tAuditEntry.IP, tAuditEntry.Date, tAuditEntry.UserName)
+
+				mock.ExpectQuery("WHERE u.username = (.+) AND a.action = (.+) AND a.target_type = (.+) "+
+					"AND a.target_id = (.+) AND a.date >= (.+) AND a.date < (.+) ORDER BY").
+					WithArgs(filter.UserName, filter.Action, filter.TargetType, filter.TargetID, filter.From,
+						filter.To, filter.Limit, filter.Offset).WillReturnRows(rows)
+			},
+			wantEntries: []x.AuditEntry{tAuditEntry},
+			wantError:   false,
+		},
+		{
+			// When no audit entries match the filter
+			name:   "#3 OK (NO ROWS)",
+			filter: x.AuditFilter{Action: "promote", Limit: 25, Offset: 0},
+			mock: func(filter x.AuditFilter) {
+				rows := sqlmock.NewRows(table)
+
+				mock.ExpectQuery("WHERE a.action = (.+) ORDER BY").
+					WithArgs(filter.Action, filter.Limit, filter.Offset).WillReturnRows(rows)
+			},
+			wantEntries: nilAuditEntries,
+			wantError:   false,
+		},
+		{
+			// When the audit log table doesn't exist
+			name:   "#4 ERROR",
+			filter: x.AuditFilter{Limit: 25, Offset: 0},
+			mock: func(filter x.AuditFilter) {
+				mock.ExpectQuery("SELECT (.+) FROM audit_log").
+					WillReturnError(errors.New("table audit_log does not exist"))
+			},
+			wantEntries: nil,
+			wantError:   true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.filter)
+
+			entries, err := store.GetAuditEntries(test.filter)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetAuditEntries() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(entries, test.wantEntries) {
+				t.Errorf("GetAuditEntries() = %v, want %v", entries, test.wantEntries)
+			}
+		})
+	}
+}
+
+// TestCountAuditEntries tests getting amount of audit entries matching a
+// filter.
+func TestCountAuditEntries(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &AuditStore{DB: db}
+	defer db.Close()
+
+	table := []string{"COUNT(*)"}
+
+	// Declare test cases
+	tests := []struct {
+		name             string
+		filter           x.AuditFilter
+		mock             func(filter x.AuditFilter)
+		wantEntriesCount int
+		wantError        bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			filter: x.AuditFilter{},
+			mock: func(filter x.AuditFilter) {
+				rows := sqlmock.NewRows(table).AddRow(2)
+
+				mock.ExpectQuery("SELECT COUNT((.+)) FROM audit_log").WillReturnRows(rows)
+			},
+			wantEntriesCount: 2,
+			wantError:        false,
+		},
+		{
+			// When filtering by target
+			name:   "#2 OK (FILTERED)",
+			filter: x.AuditFilter{TargetType: "topic", TargetID: 2},
+			mock: func(filter x.AuditFilter) {
+				rows := sqlmock.NewRows(table).AddRow(1)
+
+				mock.ExpectQuery("WHERE a.target_type = (.+) AND a.target_id = (.+)").
+					WithArgs(filter.TargetType, filter.TargetID).WillReturnRows(rows)
+			},
+			wantEntriesCount: 1,
+			wantError:        false,
+		},
+		{
+			// When the audit log table doesn't exist
+			name:   "#3 ERROR",
+			filter: x.AuditFilter{},
+			mock: func(filter x.AuditFilter) {
+				mock.ExpectQuery("SELECT COUNT((.+)) FROM audit_log").
+					WillReturnError(errors.New("table audit_log does not exist"))
+			},
+			wantEntriesCount: 0,
+			wantError:        true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.filter)
+
+			entriesCount, err := store.CountAuditEntries(test.filter)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CountAuditEntries() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && entriesCount != test.wantEntriesCount {
+				t.Errorf("CountAuditEntries() = %v, want %v", entriesCount, test.wantEntriesCount)
+			}
+		})
+	}
+}
+
+// TestCreateAuditEntry tests creating a new audit entry.
+func TestCreateAuditEntry(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &AuditStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO audit_log"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		entry     x.AuditEntry
+		mock      func(entry x.AuditEntry)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:  "#1 OK",
+			entry: tAuditEntry,
+			mock: func(entry x.AuditEntry) {
+				mock.ExpectExec(queryMatch).WithArgs(entry.UserID, entry.Action, entry.TargetType,
+					entry.TargetID, entry.Before, entry.After, entry.IP, entry.Date).
+					WillReturnResult(sqlmock.NewResult(int64(entry.AuditID), 1))
+			},
+			wantError: false,
+		},
+		{
+			// When action is missing
+			name: "#2 ACTION MISSING",
+			entry: x.AuditEntry{
+				UserID:     tAuditEntry.UserID,
+				TargetType: tAuditEntry.TargetType,
+				TargetID:   tAuditEntry.TargetID,
+				Date:       tAuditEntry.Date,
+			},
+			mock: func(entry x.AuditEntry) {
+				mock.ExpectExec(queryMatch).WithArgs(entry.UserID, entry.Action, entry.TargetType,
+					entry.TargetID, entry.Before, entry.After, entry.IP, entry.Date).
+					WillReturnError(errors.New("action can not be empty"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.entry)
+
+			err := store.CreateAuditEntry(&test.entry)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateAuditEntry() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// The database store evolving around the quiz of the day, with all necessary
+// methods that access the database.
+//
+// The events of a challenge are stored once per day, whereas the results are
+// stored once per user and day, which ensures a challenge can't be repeated.
+
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	mysqlDuplicateEntry = 1062 // error number of MySQL for a duplicate primary or unique key
+)
+
+// ChallengeStore is the MySQL database access object.
+type ChallengeStore struct {
+	*sqlx.DB
+}
+
+// GetChallenges gets all challenges with the amount of players and the best
+// points, sorted by day descending.
+func (store *ChallengeStore) GetChallenges() ([]x.Challenge, error) {
+	var challenges []x.Challenge
+
+	query := `
+		SELECT c.day,
+		       COUNT(cr.user_id) AS players_count,
+		       COALESCE(MAX(cr.points), 0) AS best_points
+		FROM (SELECT DISTINCT day FROM challenge_events) c
+		    LEFT JOIN challenge_results cr ON cr.day = c.day
+		GROUP BY c.day
+		ORDER BY c.day DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&challenges, query); err != nil {
+		return []x.Challenge{}, fmt.Errorf("error getting challenges: %w", err)
+	}
+
+	return challenges, nil
+}
+
+// GetChallengeEvents gets the events of the challenge of a day in the order
+// they get asked, which is empty if there's no challenge on that day yet.
+// Events in the trash are deliberately included, since the events of a day are
+// pinned when the challenge gets created, so that every player gets the same
+// questions, even if an event gets moved to the trash during the day.
+func (store *ChallengeStore) GetChallengeEvents(day time.Time) ([]x.Event, error) {
+	var events []x.Event
+
+	query := `
+		SELECT e.*
+		FROM challenge_events ce
+		    JOIN events e ON e.event_id = ce.event_id
+		WHERE ce.day = ?
+		ORDER BY ce.position
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&events, query, day.Format("2006-01-02")); err != nil {
+		return []x.Event{}, fmt.Errorf("error getting events of challenge: %w", err)
+	}
+
+	return events, nil
+}
+
+// CreateChallenge creates the challenge of a day with its events in the order
+// they get asked. Creating a challenge, which already exists, has no effect.
+func (store *ChallengeStore) CreateChallenge(day time.Time, eventIDs []int) error {
+
+	query := `
+		INSERT IGNORE INTO challenge_events(day, position, event_id)
+		VALUES (?, ?, ?)
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statements for each event
+	for position, eventID := range eventIDs {
+		if _, err = tx.Exec(query, day.Format("2006-01-02"), position, eventID); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating challenge: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallengeLeaderboard gets the results of all users in the challenge of a
+// day, sorted by points descending.
+func (store *ChallengeStore) GetChallengeLeaderboard(day time.Time) ([]x.ChallengeResult, error) {
+	var results []x.ChallengeResult
+
+	query := `
+		SELECT cr.user_id, cr.day, cr.points, cr.date,
+		       u.username AS user_name,
+		       RANK() OVER (ORDER BY cr.points DESC) AS ranking
+		FROM challenge_results cr
+		    JOIN users u ON u.user_id = cr.user_id
+		WHERE cr.day = ?
+		  AND u.deleted_at IS NULL
+		ORDER BY cr.points DESC, cr.date, cr.user_id
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&results, query, day.Format("2006-01-02")); err != nil {
+		return []x.ChallengeResult{}, fmt.Errorf("error getting leaderboard of challenge: %w", err)
+	}
+
+	return results, nil
+}
+
+// HasPlayedChallenge checks whether a user has already played the challenge of
+// a day.
+func (store *ChallengeStore) HasPlayedChallenge(userID int, day time.Time) (bool, error) {
+	var played bool
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM challenge_results WHERE user_id = ? AND day = ?)
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&played, query, userID, day.Format("2006-01-02")); err != nil {
+		return false, fmt.Errorf("error checking challenge result: %w", err)
+	}
+
+	return played, nil
+}
+
+// CreateChallengeResult creates the result of a user in the challenge of a day.
+// If the user has already played the challenge of that day, e.g. by submitting
+// it twice simultaneously, x.ErrChallengePlayed gets returned.
+func (store *ChallengeStore) CreateChallengeResult(result *x.ChallengeResult) error {
+
+	query := `
+		INSERT INTO challenge_results(user_id, day, points, date)
+		VALUES (?, ?, ?, ?)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		result.UserID,
+		result.Day.Format("2006-01-02"),
+		result.Points,
+		result.Date,
+	); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			err = x.ErrChallengePlayed
+		}
+		return fmt.Errorf("error creating challenge result: %w", err)
+	}
+
+	return nil
+}
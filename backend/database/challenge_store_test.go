@@ -0,0 +1,479 @@
+// Collection of tests for the database access layer of functions evolving
+// around the quiz of the day.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tChallengeDay is a mock day of a challenge for testing purposes
+	tChallengeDay = time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	// tChallenge is a mock challenge for testing purposes
+	tChallenge = x.Challenge{
+		Day:          tChallengeDay,
+		PlayersCount: 2,
+		BestPoints:   48,
+	}
+
+	// tChallengeResult is a mock result of a challenge for testing purposes
+	tChallengeResult = x.ChallengeResult{
+		UserID:   1,
+		Day:      tChallengeDay,
+		Points:   48,
+		Date:     time.Date(2021, 3, 2, 9, 15, 0, 0, time.UTC),
+		UserName: "testuser1",
+		Rank:     1,
+	}
+
+	// nilChallenges is a nil slice of challenges
+	nilChallenges []x.Challenge
+
+	// nilChallengeResults is a nil slice of results of challenges
+	nilChallengeResults []x.ChallengeResult
+)
+
+// TestGetChallenges tests getting all challenges.
+func TestGetChallenges(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ChallengeStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM \\(SELECT DISTINCT day FROM challenge_events\\) c (.+) ORDER BY c.day DESC"
+
+	table := []string{"day", "players_count", "best_points"}
+
+	// Declare test cases
+	tests := []struct {
+		name           string
+		mock           func()
+		wantChallenges []x.Challenge
+		wantError      bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(tChallenge.Day, tChallenge.PlayersCount, tChallenge.BestPoints)
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			},
+			wantChallenges: []x.Challenge{tChallenge},
+			wantError:      false,
+		},
+		{
+			// When the challenge_events table doesn't exist
+			name: "#2 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).
+					WillReturnError(errors.New("table challenge_events does not exist"))
+			},
+			wantChallenges: nilChallenges,
+			wantError:      true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			challenges, err := store.GetChallenges()
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetChallenges() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(challenges, test.wantChallenges) {
+				t.Errorf("GetChallenges() = %v, want %v", challenges, test.wantChallenges)
+			}
+		})
+	}
+}
+
+// TestGetChallengeEvents tests getting the events of the challenge of a day.
+func TestGetChallengeEvents(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ChallengeStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT e.\\* FROM challenge_events ce (.+) WHERE ce.day = \\? ORDER BY ce.position"
+
+	table := []string{"event_id", "topic_id", "name", "year", "date", "description", "source", "image"}
+
+	tDeletedEvent := tEvent
+	tDeletedEvent.DeletedAt = &tChallengeDay
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		day        time.Time
+		mock       func()
+		wantEvents []x.Event
+		wantError  bool
+	}{
+		{
+			// When everything works as intended, whereas only the calendar
+			// day is compared
+			name: "#1 OK",
+			day:  tChallengeDay.Add(time.Hour * 18),
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(tEvent.EventID, tEvent.TopicID, tEvent.Name, tEvent.Year, tEvent.Date, tEvent.Description,
+						tEvent.Source, tEvent.Image)
+
+				mock.ExpectQuery(queryMatch).WithArgs("2021-03-02").WillReturnRows(rows)
+			},
+			wantEvents: []x.Event{tEvent},
+			wantError:  false,
+		},
+		{
+			// When an event of the challenge has been moved to the trash,
+			// which is still part of the challenge
+			name: "#2 EVENT IN TRASH",
+			day:  tChallengeDay,
+			mock: func() {
+				rows := sqlmock.NewRows(append(table, "deleted_at")).
+					AddRow(tEvent.EventID, tEvent.TopicID, tEvent.Name, tEvent.Year, tEvent.Date, tEvent.Description,
+						tEvent.Source, tEvent.Image, tChallengeDay)
+
+				mock.ExpectQuery(queryMatch).WithArgs("2021-03-02").WillReturnRows(rows)
+			},
+			wantEvents: []x.Event{tDeletedEvent},
+			wantError:  false,
+		},
+		{
+			// When there's no challenge on that day yet
+			name: "#3 NO CHALLENGE",
+			day:  tChallengeDay,
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WithArgs("2021-03-02").WillReturnRows(sqlmock.NewRows(table))
+			},
+			wantEvents: nil,
+			wantError:  false,
+		},
+		{
+			// When the challenge_events table doesn't exist
+			name: "#4 ERROR",
+			day:  tChallengeDay,
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WithArgs("2021-03-02").
+					WillReturnError(errors.New("table challenge_events does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			events, err := store.GetChallengeEvents(test.day)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetChallengeEvents() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(events, test.wantEvents) {
+				t.Errorf("GetChallengeEvents() = %v, want %v", events, test.wantEvents)
+			}
+		})
+	}
+}
+
+// TestCreateChallenge tests creating the challenge of a day with its events.
+func TestCreateChallenge(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ChallengeStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT IGNORE INTO challenge_events"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		eventIDs  []int
+		mock      func()
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:     "#1 OK",
+			eventIDs: []int{3, 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WithArgs("2021-03-02", 0, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(queryMatch).WithArgs("2021-03-02", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantError: false,
+		},
+		{
+			// When one of the events can't be added, which rolls back the
+			// entire transaction
+			name:     "#2 ROLLBACK",
+			eventIDs: []int{3, 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WithArgs("2021-03-02", 0, 3).
+					WillReturnError(errors.New("event does not exist"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+		{
+			// When the transaction can't be started
+			name:     "#3 BEGIN FAILED",
+			eventIDs: []int{3, 1},
+			mock: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("connection lost"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			err := store.CreateChallenge(tChallengeDay, test.eventIDs)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateChallenge() error = %v, want error %v", err, test.wantError)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateChallenge() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+// TestGetChallengeLeaderboard tests getting the results of all users in the
+// challenge of a day.
+func TestGetChallengeLeaderboard(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ChallengeStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) RANK\\(\\) OVER \\(ORDER BY cr.points DESC\\) AS ranking " +
+		"FROM challenge_results cr (.+) WHERE cr.day = \\?"
+
+	table := []string{"user_id", "day", "points", "date", "user_name", "ranking"}
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		mock        func()
+		wantResults []x.ChallengeResult
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(tChallengeResult.UserID, tChallengeResult.Day, tChallengeResult.Points,
+						tChallengeResult.Date, tChallengeResult.UserName, tChallengeResult.Rank)
+
+				mock.ExpectQuery(queryMatch).WithArgs("2021-03-02").WillReturnRows(rows)
+			},
+			wantResults: []x.ChallengeResult{tChallengeResult},
+			wantError:   false,
+		},
+		{
+			// When the challenge_results table doesn't exist
+			name: "#2 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WithArgs("2021-03-02").
+					WillReturnError(errors.New("table challenge_results does not exist"))
+			},
+			wantResults: nilChallengeResults,
+			wantError:   true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			results, err := store.GetChallengeLeaderboard(tChallengeDay)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetChallengeLeaderboard() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(results, test.wantResults) {
+				t.Errorf("GetChallengeLeaderboard() = %v, want %v", results, test.wantResults)
+			}
+		})
+	}
+}
+
+// TestHasPlayedChallenge tests checking whether a user has already played the
+// challenge of a day.
+func TestHasPlayedChallenge(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ChallengeStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT EXISTS\\((.+) FROM challenge_results WHERE user_id = \\? AND day = \\?\\)"
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		mock       func()
+		wantPlayed bool
+		wantError  bool
+	}{
+		{
+			// When the user has already played
+			name: "#1 PLAYED",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"played"}).AddRow(true)
+
+				mock.ExpectQuery(queryMatch).WithArgs(1, "2021-03-02").WillReturnRows(rows)
+			},
+			wantPlayed: true,
+			wantError:  false,
+		},
+		{
+			// When the user hasn't played yet
+			name: "#2 NOT PLAYED",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"played"}).AddRow(false)
+
+				mock.ExpectQuery(queryMatch).WithArgs(1, "2021-03-02").WillReturnRows(rows)
+			},
+			wantPlayed: false,
+			wantError:  false,
+		},
+		{
+			// When the challenge_results table doesn't exist
+			name: "#3 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WithArgs(1, "2021-03-02").
+					WillReturnError(errors.New("table challenge_results does not exist"))
+			},
+			wantPlayed: false,
+			wantError:  true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			played, err := store.HasPlayedChallenge(1, tChallengeDay)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("HasPlayedChallenge() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if played != test.wantPlayed {
+				t.Errorf("HasPlayedChallenge() = %v, want %v", played, test.wantPlayed)
+			}
+		})
+	}
+}
+
+// TestCreateChallengeResult tests creating the result of a user in the
+// challenge of a day.
+func TestCreateChallengeResult(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ChallengeStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO challenge_results"
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		result     x.ChallengeResult
+		mock       func(result x.ChallengeResult)
+		wantError  bool
+		wantPlayed bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			result: tChallengeResult,
+			mock: func(result x.ChallengeResult) {
+				mock.ExpectExec(queryMatch).WithArgs(result.UserID, "2021-03-02", result.Points, result.Date).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When the user has already played the challenge of that day
+			name:   "#2 ALREADY PLAYED",
+			result: tChallengeResult,
+			mock: func(result x.ChallengeResult) {
+				mock.ExpectExec(queryMatch).WithArgs(result.UserID, "2021-03-02", result.Points, result.Date).
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry for key 'PRIMARY'"})
+			},
+			wantError:  true,
+			wantPlayed: true,
+		},
+		{
+			// When the challenge_results table doesn't exist
+			name:   "#3 ERROR",
+			result: tChallengeResult,
+			mock: func(result x.ChallengeResult) {
+				mock.ExpectExec(queryMatch).WithArgs(result.UserID, "2021-03-02", result.Points, result.Date).
+					WillReturnError(errors.New("table challenge_results does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.result)
+
+			err := store.CreateChallengeResult(&test.result)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateChallengeResult() error = %v, want error %v", err, test.wantError)
+			}
+			if errors.Is(err, x.ErrChallengePlayed) != test.wantPlayed {
+				t.Errorf("CreateChallengeResult() error = %v, want x.ErrChallengePlayed %v", err, test.wantPlayed)
+			}
+		})
+	}
+}
@@ -1,5 +1,8 @@
 // The database store evolving around events, with all necessary methods that
 // access the database.
+//
+// Deleting an event moves it to the trash, from where it can be restored until
+// it gets purged. Events in the trash are excluded from all other queries.
 
 package database
 
@@ -23,7 +26,8 @@ func (store *EventStore) GetEvent(eventID int) (x.Event, error) {
 	query := `
 		SELECT *
 		FROM events
-		WHERE event_id = ?
+		WHERE event_id = ? 
+		  AND deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -34,13 +38,43 @@ func (store *EventStore) GetEvent(eventID int) (x.Event, error) {
 	return event, nil
 }
 
-// CountEvents gets amount of events.
+// GetEventsByTopics gets all events of multiple topics, ordered by date.
+func (store *EventStore) GetEventsByTopics(topicIDs []int) ([]x.Event, error) {
+	var events []x.Event
+
+	if len(topicIDs) == 0 {
+		return []x.Event{}, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT * 
+		FROM events 
+		WHERE topic_id IN (?) 
+		  AND deleted_at IS NULL
+		ORDER BY date
+		`, topicIDs)
+	if err != nil {
+		return []x.Event{}, fmt.Errorf("error building query for events of topics: %w", err)
+	}
+
+	// Execute prepared statement
+	if err = store.Select(&events, store.Rebind(query), args...); err != nil {
+		return []x.Event{}, fmt.Errorf("error getting events of topics: %w", err)
+	}
+
+	return events, nil
+}
+
+// CountEvents gets amount of events, excluding events of topics in the trash.
 func (store *EventStore) CountEvents() (int, error) {
 	var eventCount int
 
 	query := `
 		SELECT COUNT(*) 
-		FROM events
+		FROM events e 
+		    JOIN topics t ON t.topic_id = e.topic_id
+		WHERE e.deleted_at IS NULL 
+		  AND t.deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -51,24 +85,74 @@ func (store *EventStore) CountEvents() (int, error) {
 	return eventCount, nil
 }
 
-// CreateEvent creates a new event.
+// CreateEvent creates a new event and sets the ID of the newly created event.
 func (store *EventStore) CreateEvent(event *x.Event) error {
 
 	query := `
-		INSERT INTO events(topic_id, name, year, date) 
-		VALUES (?, ?, ?, ?)
+		INSERT INTO events(topic_id, name, year, date, description, source, image) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 		`
 
 	// Execute prepared statement
-	if _, err := store.Exec(query,
+	result, err := store.Exec(query,
 		event.TopicID,
 		event.Name,
 		event.Year,
 		event.Date,
-	); err != nil {
+		event.Description,
+		event.Source,
+		event.Image,
+	)
+	if err != nil {
 		return fmt.Errorf("error creating event: %w", err)
 	}
 
+	// Set ID of newly created event
+	eventID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting ID of event: %w", err)
+	}
+	event.EventID = int(eventID)
+
+	return nil
+}
+
+// CreateEvents creates multiple new events in a single transaction, so that
+// either all or none of the events get created.
+func (store *EventStore) CreateEvents(events []x.Event) error {
+
+	query := `
+		INSERT INTO events(topic_id, name, year, date, description, source, image) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statement for each event
+	for _, event := range events {
+		if _, err = tx.Exec(query,
+			event.TopicID,
+			event.Name,
+			event.Year,
+			event.Date,
+			event.Description,
+			event.Source,
+			event.Image,
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating events: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -79,7 +163,10 @@ func (store *EventStore) UpdateEvent(event *x.Event) error {
 		UPDATE events 
 		SET name = ?, 
 		    year = ?,
-		    date = ?
+		    date = ?,
+		    description = ?,
+		    source = ?,
+		    image = ?
 		WHERE event_id = ?
 		`
 
@@ -88,6 +175,9 @@ func (store *EventStore) UpdateEvent(event *x.Event) error {
 		event.Name,
 		event.Year,
 		event.Date,
+		event.Description,
+		event.Source,
+		event.Image,
 		event.EventID,
 	); err != nil {
 		return fmt.Errorf("error updating event: %w", err)
@@ -96,12 +186,14 @@ func (store *EventStore) UpdateEvent(event *x.Event) error {
 	return nil
 }
 
-// DeleteEvent deletes an existing event.
+// DeleteEvent moves an existing event to the trash.
 func (store *EventStore) DeleteEvent(eventID int) error {
 
 	query := `
-		DELETE FROM events 
-		WHERE event_id = ?
+		UPDATE events 
+		SET deleted_at = CURRENT_TIMESTAMP 
+		WHERE event_id = ? 
+		  AND deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -111,3 +203,57 @@ func (store *EventStore) DeleteEvent(eventID int) error {
 
 	return nil
 }
+
+// GetDeletedEvents gets all events in the trash, sorted by date of deletion
+// descending.
+func (store *EventStore) GetDeletedEvents() ([]x.Event, error) {
+	var events []x.Event
+
+	query := `
+		SELECT * 
+		FROM events 
+		WHERE deleted_at IS NOT NULL 
+		ORDER BY deleted_at DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&events, query); err != nil {
+		return []x.Event{}, fmt.Errorf("error getting deleted events: %w", err)
+	}
+
+	return events, nil
+}
+
+// RestoreEvent restores an event from the trash.
+func (store *EventStore) RestoreEvent(eventID int) error {
+
+	query := `
+		UPDATE events 
+		SET deleted_at = NULL 
+		WHERE event_id = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, eventID); err != nil {
+		return fmt.Errorf("error restoring event: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeEvent permanently deletes an event in the trash.
+func (store *EventStore) PurgeEvent(eventID int) error {
+
+	query := `
+		DELETE FROM events 
+		WHERE event_id = ? 
+		  AND deleted_at IS NOT NULL
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, eventID); err != nil {
+		return fmt.Errorf("error purging event: %w", err)
+	}
+
+	return nil
+}
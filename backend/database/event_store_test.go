@@ -23,6 +23,10 @@ var (
 		Name:    "Test Event 1",
 		Year:    1800,
 		Date:    time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC),
+
+		Description: "Test Description 1",
+		Source:      "https://source.com",
+		Image:       "https://image.png",
 	}
 )
 
@@ -34,9 +38,9 @@ func TestGetEvent(t *testing.T) {
 	store := &EventStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT (.+) FROM events"
+	queryMatch := "SELECT (.+) FROM events WHERE event_id = \\? AND deleted_at IS NULL"
 
-	table := []string{"event_id", "topic_id", "name", "year", "date"}
+	table := []string{"event_id", "topic_id", "name", "year", "date", "description", "source", "image"}
 
 	// Declare test cases
 	tests := []struct {
@@ -52,7 +56,8 @@ func TestGetEvent(t *testing.T) {
 			eventID: 1,
 			mock: func(eventID int) {
 				rows := sqlmock.NewRows(table).
-					AddRow(tEvent.EventID, tEvent.TopicID, tEvent.Name, tEvent.Year, tEvent.Date)
+					AddRow(tEvent.EventID, tEvent.TopicID, tEvent.Name, tEvent.Year, tEvent.Date, tEvent.Description,
+						tEvent.Source, tEvent.Image)
 
 				mock.ExpectQuery(queryMatch).WithArgs(eventID).WillReturnRows(rows)
 			},
@@ -92,6 +97,85 @@ func TestGetEvent(t *testing.T) {
 	}
 }
 
+// TestGetEventsByTopics tests getting all events of multiple topics.
+func TestGetEventsByTopics(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &EventStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM events WHERE topic_id IN (.+) ORDER BY date"
+
+	table := []string{"event_id", "topic_id", "name", "year", "date", "description", "source", "image"}
+
+	tEvent2 := tEvent
+	tEvent2.EventID, tEvent2.TopicID = 2, 2
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		topicIDs   []int
+		mock       func(topicIDs []int)
+		wantEvents []x.Event
+		wantError  bool
+	}{
+		{
+			// When everything works as intended
+			name:     "#1 OK",
+			topicIDs: []int{1, 2},
+			mock: func(topicIDs []int) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tEvent.EventID, tEvent.TopicID, tEvent.Name, tEvent.Year, tEvent.Date, tEvent.Description,
+						tEvent.Source, tEvent.Image).
+					AddRow(tEvent2.EventID, tEvent2.TopicID, tEvent2.Name, tEvent2.Year, tEvent2.Date,
+						tEvent2.Description, tEvent2.Source, tEvent2.Image)
+
+				mock.ExpectQuery(queryMatch).WithArgs(topicIDs[0], topicIDs[1]).WillReturnRows(rows)
+			},
+			wantEvents: []x.Event{tEvent, tEvent2},
+			wantError:  false,
+		},
+		{
+			// When no topics are given
+			name:       "#2 OK (NO TOPICS)",
+			topicIDs:   []int{},
+			mock:       func(topicIDs []int) {},
+			wantEvents: []x.Event{},
+			wantError:  false,
+		},
+		{
+			// When the events table doesn't exist
+			name:     "#3 ERROR",
+			topicIDs: []int{1},
+			mock: func(topicIDs []int) {
+				mock.ExpectQuery(queryMatch).WithArgs(topicIDs[0]).
+					WillReturnError(errors.New("table events does not exist"))
+			},
+			wantEvents: nil,
+			wantError:  true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicIDs)
+
+			events, err := store.GetEventsByTopics(test.topicIDs)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetEventsByTopics() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(events, test.wantEvents) {
+				t.Errorf("GetEventsByTopics() = %v, want %v", events, test.wantEvents)
+			}
+		})
+	}
+}
+
 // TestCountEvents tests getting amount of events.
 func TestCountEvents(t *testing.T) {
 
@@ -176,7 +260,8 @@ func TestCreateEvent(t *testing.T) {
 			name:  "#1 OK",
 			event: tEvent,
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date).
+				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date, event.Description,
+					event.Source, event.Image).
 					WillReturnResult(sqlmock.NewResult(int64(event.TopicID), 1))
 			},
 			wantError: false,
@@ -192,7 +277,8 @@ func TestCreateEvent(t *testing.T) {
 				Date:    tEvent.Date,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date).
+				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date, event.Description,
+					event.Source, event.Image).
 					WillReturnError(errors.New("topic does not exist"))
 			},
 			wantError: true,
@@ -207,7 +293,8 @@ func TestCreateEvent(t *testing.T) {
 				Date:    tEvent.Date,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date).
+				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date, event.Description,
+					event.Source, event.Image).
 					WillReturnError(errors.New("name can not be empty"))
 			},
 			wantError: true,
@@ -222,7 +309,8 @@ func TestCreateEvent(t *testing.T) {
 				Date:    tEvent.Date,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date).
+				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date, event.Description,
+					event.Source, event.Image).
 					WillReturnError(errors.New("year can not be empty"))
 			},
 			wantError: true,
@@ -237,7 +325,8 @@ func TestCreateEvent(t *testing.T) {
 				Year:    tEvent.Year,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date).
+				mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date, event.Description,
+					event.Source, event.Image).
 					WillReturnError(errors.New("date can not be empty"))
 			},
 			wantError: true,
@@ -259,6 +348,96 @@ func TestCreateEvent(t *testing.T) {
 	}
 }
 
+// TestCreateEvents tests creating multiple new events in a transaction.
+func TestCreateEvents(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &EventStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO events"
+
+	tEvents := []x.Event{
+		tEvent,
+		{
+			TopicID: tEvent.TopicID,
+			Name:    "Test Event 2",
+			Year:    1850,
+			Date:    time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		events    []x.Event
+		mock      func(events []x.Event)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			events: tEvents,
+			mock: func(events []x.Event) {
+				mock.ExpectBegin()
+				for _, event := range events {
+					mock.ExpectExec(queryMatch).WithArgs(event.TopicID, event.Name, event.Year, event.Date, event.Description,
+						event.Source, event.Image).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+			},
+			wantError: false,
+		},
+		{
+			// When one of the events can't be created, which rolls back the
+			// entire transaction
+			name:   "#2 ROLLBACK",
+			events: tEvents,
+			mock: func(events []x.Event) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).
+					WithArgs(events[0].TopicID, events[0].Name, events[0].Year, events[0].Date, events[0].Description,
+						events[0].Source, events[0].Image).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(queryMatch).
+					WithArgs(events[1].TopicID, events[1].Name, events[1].Year, events[1].Date, events[1].Description,
+						events[1].Source, events[1].Image).
+					WillReturnError(errors.New("topic does not exist"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+		{
+			// When the transaction can't be started
+			name:   "#3 BEGIN FAILED",
+			events: tEvents,
+			mock: func(events []x.Event) {
+				mock.ExpectBegin().WillReturnError(errors.New("connection lost"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.events)
+
+			err := store.CreateEvents(test.events)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateEvents() error = %v, want error %v", err, test.wantError)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateEvents() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
 // TestUpdateEvent tests updating an existing event.
 func TestUpdateEvent(t *testing.T) {
 
@@ -281,7 +460,8 @@ func TestUpdateEvent(t *testing.T) {
 			name:  "#1 OK",
 			event: tEvent,
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(tEvent.Name, tEvent.Year, tEvent.Date, tEvent.EventID).
+				mock.ExpectExec(queryMatch).WithArgs(tEvent.Name, tEvent.Year, tEvent.Date, tEvent.Description, tEvent.Source,
+					tEvent.Image, tEvent.EventID).
 					WillReturnResult(sqlmock.NewResult(int64(event.EventID), 1))
 			},
 			wantError: false,
@@ -297,7 +477,8 @@ func TestUpdateEvent(t *testing.T) {
 				Date:    tEvent.Date,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(tEvent.Name, tEvent.Year, tEvent.Date, tEvent.EventID).
+				mock.ExpectExec(queryMatch).WithArgs(tEvent.Name, tEvent.Year, tEvent.Date, tEvent.Description, tEvent.Source,
+					tEvent.Image, tEvent.EventID).
 					WillReturnError(errors.New("event with given id does not exist"))
 			},
 			wantError: true,
@@ -312,7 +493,8 @@ func TestUpdateEvent(t *testing.T) {
 				Date:    tEvent.Date,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.Name, event.Year, event.Date, event.EventID).
+				mock.ExpectExec(queryMatch).WithArgs(event.Name, event.Year, event.Date, event.Description, event.Source,
+					event.Image, event.EventID).
 					WillReturnError(errors.New("name can not be empty"))
 			},
 			wantError: true,
@@ -327,7 +509,8 @@ func TestUpdateEvent(t *testing.T) {
 				Date:    tEvent.Date,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.Name, event.Year, event.Date, event.EventID).
+				mock.ExpectExec(queryMatch).WithArgs(event.Name, event.Year, event.Date, event.Description, event.Source,
+					event.Image, event.EventID).
 					WillReturnError(errors.New("year can not be empty"))
 			},
 			wantError: true,
@@ -342,7 +525,8 @@ func TestUpdateEvent(t *testing.T) {
 				Year:    tEvent.Year,
 			},
 			mock: func(event x.Event) {
-				mock.ExpectExec(queryMatch).WithArgs(event.Name, event.Year, event.Date, event.EventID).
+				mock.ExpectExec(queryMatch).WithArgs(event.Name, event.Year, event.Date, event.Description, event.Source,
+					event.Image, event.EventID).
 					WillReturnError(errors.New("date can not be empty"))
 			},
 			wantError: true,
@@ -364,7 +548,7 @@ func TestUpdateEvent(t *testing.T) {
 	}
 }
 
-// TestDeleteEvent tests deleting an existing event.
+// TestDeleteEvent tests moving an existing event to the trash.
 func TestDeleteEvent(t *testing.T) {
 
 	// New mock database
@@ -372,7 +556,7 @@ func TestDeleteEvent(t *testing.T) {
 	store := &EventStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "DELETE FROM events"
+	queryMatch := "UPDATE events SET deleted_at = CURRENT_TIMESTAMP"
 
 	// Declare test cases
 	tests := []struct {
@@ -417,3 +601,111 @@ func TestDeleteEvent(t *testing.T) {
 		})
 	}
 }
+
+// TestRestoreEvent tests restoring a event from the trash.
+func TestRestoreEvent(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &EventStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE events SET deleted_at = NULL"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		eventID   int
+		mock      func(eventID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			eventID: tEvent.EventID,
+			mock: func(eventID int) {
+				mock.ExpectExec(queryMatch).WithArgs(eventID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When event with given event ID doesn't exist
+			name:    "#2 NOT FOUND",
+			eventID: 0,
+			mock: func(eventID int) {
+				mock.ExpectExec(queryMatch).WithArgs(eventID).
+					WillReturnError(errors.New("event with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.eventID)
+
+			err := store.RestoreEvent(test.eventID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("RestoreEvent() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestPurgeEvent tests permanently deleting a event in the trash.
+func TestPurgeEvent(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &EventStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "DELETE FROM events"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		eventID   int
+		mock      func(eventID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			eventID: tEvent.EventID,
+			mock: func(eventID int) {
+				mock.ExpectExec(queryMatch).WithArgs(eventID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When event with given event ID doesn't exist
+			name:    "#2 NOT FOUND",
+			eventID: 0,
+			mock: func(eventID int) {
+				mock.ExpectExec(queryMatch).WithArgs(eventID).
+					WillReturnError(errors.New("event with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.eventID)
+
+			err := store.PurgeEvent(test.eventID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("PurgeEvent() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+-- Optional description, source and image of an event, which get shown to the
+-- students after having answered a question about the event.
+
+ALTER TABLE events
+    ADD COLUMN description VARCHAR(1000) NOT NULL DEFAULT '',
+    ADD COLUMN source      VARCHAR(5000) NOT NULL DEFAULT '',
+    ADD COLUMN image       VARCHAR(5000) NOT NULL DEFAULT '';
@@ -0,0 +1,18 @@
+-- Append-only log of administrative and content changes. Entries are never
+-- updated or deleted by the application, so the user ID isn't a foreign key,
+-- in order to keep the entries of deleted users.
+
+CREATE TABLE audit_log
+(
+    audit_id        INT AUTO_INCREMENT PRIMARY KEY,
+    user_id         INT          NOT NULL,
+    action          VARCHAR(20)  NOT NULL,
+    target_type     VARCHAR(20)  NOT NULL,
+    target_id       INT          NOT NULL,
+    snapshot_before TEXT         NOT NULL,
+    snapshot_after  TEXT         NOT NULL,
+    ip              VARCHAR(45)  NOT NULL,
+    date            DATETIME     NOT NULL,
+    INDEX (date),
+    INDEX (target_type, target_id)
+);
@@ -0,0 +1,15 @@
+-- Soft deletion of topics, events and users. Deleted rows get moved to the
+-- trash by setting the date of deletion and get purged permanently after the
+-- retention period.
+
+ALTER TABLE topics
+    ADD COLUMN deleted_at DATETIME NULL DEFAULT NULL,
+    ADD INDEX (deleted_at);
+
+ALTER TABLE events
+    ADD COLUMN deleted_at DATETIME NULL DEFAULT NULL,
+    ADD INDEX (deleted_at);
+
+ALTER TABLE users
+    ADD COLUMN deleted_at DATETIME NULL DEFAULT NULL,
+    ADD INDEX (deleted_at);
@@ -0,0 +1,14 @@
+-- Revisions of events and topics, recorded with every change. Like the audit
+-- log, revisions are never updated or deleted by the application, so the user
+-- ID isn't a foreign key.
+
+CREATE TABLE revisions
+(
+    revision_id INT AUTO_INCREMENT PRIMARY KEY,
+    target_type VARCHAR(20) NOT NULL,
+    target_id   INT         NOT NULL,
+    user_id     INT         NOT NULL,
+    snapshot    TEXT        NOT NULL,
+    date        DATETIME    NOT NULL,
+    INDEX (target_type, target_id)
+);
@@ -0,0 +1,21 @@
+-- Corrections of events and new events suggested by users, which get
+-- moderated by admins.
+
+CREATE TABLE suggestions
+(
+    suggestion_id INT AUTO_INCREMENT PRIMARY KEY,
+    topic_id      INT           NOT NULL,
+    event_id      INT           NOT NULL DEFAULT 0,
+    user_id       INT           NOT NULL,
+    name          VARCHAR(150)  NOT NULL DEFAULT '',
+    year          INT           NOT NULL,
+    event_date    DATETIME      NOT NULL,
+    comment       VARCHAR(1000) NOT NULL DEFAULT '',
+    status        VARCHAR(20)   NOT NULL DEFAULT 'pending',
+    reason        VARCHAR(1000) NOT NULL DEFAULT '',
+    date          DATETIME      NOT NULL,
+    INDEX (status),
+    INDEX (user_id),
+    FOREIGN KEY (topic_id) REFERENCES topics (topic_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- Tags to categorize topics (e.g. "Antike"), with a many-to-many relation
+-- between topics and tags.
+
+CREATE TABLE tags
+(
+    tag_id INT AUTO_INCREMENT PRIMARY KEY,
+    name   VARCHAR(30) NOT NULL UNIQUE
+);
+
+CREATE TABLE topic_tags
+(
+    topic_id INT NOT NULL,
+    tag_id   INT NOT NULL,
+    PRIMARY KEY (topic_id, tag_id),
+    FOREIGN KEY (topic_id) REFERENCES topics (topic_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags (tag_id) ON DELETE CASCADE
+);
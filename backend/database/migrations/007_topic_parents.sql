@@ -0,0 +1,7 @@
+-- Optional parent topic of a topic (e.g. "Schlacht um Stalingrad" being part
+-- of "Zweiter Weltkrieg"), 0 for a top-level topic.
+
+ALTER TABLE topics
+    ADD parent_id INT NOT NULL DEFAULT 0 AFTER topic_id;
+
+CREATE INDEX topics_parent_id ON topics (parent_id);
@@ -0,0 +1,5 @@
+-- Preferred language of a user (e.g. "en"), empty if the language should be
+-- negotiated by the browser.
+
+ALTER TABLE users
+    ADD locale VARCHAR(5) NOT NULL DEFAULT '' AFTER verified;
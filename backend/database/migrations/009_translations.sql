@@ -0,0 +1,23 @@
+-- Translations of the names and descriptions of topics and events into other
+-- languages than German (e.g. "en"), for bilingual schools. Topics and events
+-- without a translation fall back to German.
+
+CREATE TABLE topic_translations
+(
+    topic_id    INT           NOT NULL,
+    locale      VARCHAR(5)    NOT NULL,
+    name        VARCHAR(50)   NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    PRIMARY KEY (topic_id, locale),
+    FOREIGN KEY (topic_id) REFERENCES topics (topic_id) ON DELETE CASCADE
+);
+
+CREATE TABLE event_translations
+(
+    event_id    INT           NOT NULL,
+    locale      VARCHAR(5)    NOT NULL,
+    name        VARCHAR(150)  NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    PRIMARY KEY (event_id, locale),
+    FOREIGN KEY (event_id) REFERENCES events (event_id) ON DELETE CASCADE
+);
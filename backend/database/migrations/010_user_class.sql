@@ -0,0 +1,7 @@
+-- School class of a user (e.g. "4a"), empty if none, in order to rank the
+-- scores of a class among themselves.
+
+ALTER TABLE users
+    ADD class VARCHAR(20) NOT NULL DEFAULT '' AFTER locale;
+
+CREATE INDEX users_class ON users (class);
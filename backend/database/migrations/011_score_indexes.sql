@@ -0,0 +1,6 @@
+-- Indexes for ranking and paging the leaderboard and comparing the points of
+-- a quiz in the database, instead of loading every score.
+
+CREATE INDEX scores_points ON scores (points DESC, date);
+
+CREATE INDEX scores_topic_points ON scores (topic_id, points DESC, date);
@@ -0,0 +1,18 @@
+-- History of skill ratings, similar to the Elo rating system, which get
+-- updated after every quiz. A row is either the overall rating of a user
+-- (without topic), the rating of a user in a topic or the difficulty of a
+-- topic (without user).
+
+CREATE TABLE ratings
+(
+    rating_id INT      NOT NULL AUTO_INCREMENT,
+    user_id   INT,
+    topic_id  INT,
+    rating    INT      NOT NULL,
+    date      DATETIME NOT NULL,
+    PRIMARY KEY (rating_id),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (topic_id) REFERENCES topics (topic_id) ON DELETE CASCADE
+);
+
+CREATE INDEX ratings_user_topic ON ratings (user_id, topic_id, date);
@@ -0,0 +1,13 @@
+-- Achievements unlocked by users after a quiz, such as a perfect phase 3 or a
+-- first place in a topic. The rules of the achievements are part of the
+-- application, so only the key of an achievement and its unlock date are
+-- stored.
+
+CREATE TABLE achievements
+(
+    user_id     INT         NOT NULL,
+    achievement VARCHAR(30) NOT NULL,
+    date        DATETIME    NOT NULL,
+    PRIMARY KEY (user_id, achievement),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
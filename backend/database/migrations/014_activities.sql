@@ -0,0 +1,19 @@
+-- Daily activity of users, being the amount of quizzes played and practice
+-- sessions (flashcards and worksheets) per day, for streaks and the activity
+-- heatmap on the profile. Quizzes played so far are taken over from the
+-- scores.
+
+CREATE TABLE activities
+(
+    user_id   INT  NOT NULL,
+    day       DATE NOT NULL,
+    quizzes   INT  NOT NULL DEFAULT 0,
+    practices INT  NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+INSERT INTO activities(user_id, day, quizzes)
+SELECT user_id, CAST(date AS DATE), COUNT(*)
+FROM scores
+GROUP BY user_id, CAST(date AS DATE);
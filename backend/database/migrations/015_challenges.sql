@@ -0,0 +1,25 @@
+-- Quiz of the day, which consists of the same events for every user on a day.
+-- The events get chosen with a seed derived from the date and are stored on
+-- the first request of the day, so that past challenges stay unchanged in the
+-- archive, even if events get added later on. Every user can play the quiz of
+-- a day once.
+
+CREATE TABLE challenge_events
+(
+    day      DATE NOT NULL,
+    position INT  NOT NULL,
+    event_id INT  NOT NULL,
+    PRIMARY KEY (day, position),
+    FOREIGN KEY (event_id) REFERENCES events (event_id) ON DELETE CASCADE
+);
+
+CREATE TABLE challenge_results
+(
+    user_id INT      NOT NULL,
+    day     DATE     NOT NULL,
+    points  INT      NOT NULL,
+    date    DATETIME NOT NULL,
+    PRIMARY KEY (user_id, day),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    INDEX (day, points DESC)
+);
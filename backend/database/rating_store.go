@@ -0,0 +1,172 @@
+// The database store evolving around ratings of users and difficulties of
+// topics, with all necessary methods that access the database.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// RatingStore is the MySQL database access object.
+type RatingStore struct {
+	*sqlx.DB
+}
+
+// GetRatingsByUser gets the history of all ratings of a user, overall and in
+// every topic, sorted by date.
+func (store *RatingStore) GetRatingsByUser(userID int) ([]x.Rating, error) {
+	var ratings []x.Rating
+
+	query := `
+		SELECT r.rating_id,
+		       r.user_id,
+		       COALESCE(r.topic_id, 0) AS topic_id,
+		       r.rating,
+		       r.date,
+		       COALESCE(t.name, '') AS topic_name
+		FROM ratings r
+		    LEFT JOIN topics t ON t.topic_id = r.topic_id
+		WHERE r.user_id = ?
+		  AND t.deleted_at IS NULL
+		ORDER BY r.date, r.rating_id
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&ratings, query, userID); err != nil {
+		return []x.Rating{}, fmt.Errorf("error getting ratings of user: %w", err)
+	}
+
+	return ratings, nil
+}
+
+// CreateRatedScore creates a new score together with the ratings calculated
+// from the current ratings, which either all get created or none of them.
+//
+// The topic gets locked for the duration of the transaction, so that users
+// finishing a quiz of the same topic simultaneously get rated one after the
+// other, instead of overwriting each other's change of the difficulty.
+func (store *RatingStore) CreateRatedScore(score *x.Score, rate x.ScoreRater) error {
+
+	lockQuery := `
+		SELECT topic_id
+		FROM topics
+		WHERE topic_id = ?
+		FOR UPDATE
+		`
+
+	scoreQuery := `
+		INSERT INTO scores(topic_id, user_id, points, date) 
+		VALUES (?, ?, ?, ?)
+		`
+
+	ratingQuery := `
+		INSERT INTO ratings(user_id, topic_id, rating, date)
+		VALUES (?, ?, ?, ?)
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statement to lock the topic
+	var topicID int
+	if err = tx.Get(&topicID, lockQuery, score.TopicID); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error locking topic: %w", err)
+	}
+
+	// Execute prepared statements to get the current ratings
+	rating, err := currentRating(tx, score.UserID, 0)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	topicRating, err := currentRating(tx, score.UserID, score.TopicID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	difficulty, err := currentRating(tx, 0, score.TopicID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	// Execute prepared statement to create the score
+	if _, err = tx.Exec(scoreQuery,
+		score.TopicID,
+		score.UserID,
+		score.Points,
+		score.Date,
+	); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error creating score: %w", err)
+	}
+
+	// Execute prepared statements for each new rating
+	for _, rating := range rate(rating, topicRating, difficulty) {
+		if _, err = tx.Exec(ratingQuery,
+			nullID(rating.UserID),
+			nullID(rating.TopicID),
+			rating.Rating,
+			rating.Date,
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating rating: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// currentRating gets the latest rating of a user in a topic within a
+// transaction, whereas a 'topicID' of 0 stands for the overall rating of the
+// user and a 'userID' of 0 for the difficulty of the topic. It is 0 if there
+// is no rating yet. The rating gets read with a lock, so that it is the latest
+// committed one.
+func currentRating(tx *sqlx.Tx, userID int, topicID int) (int, error) {
+	var rating int
+
+	query := `
+		SELECT rating
+		FROM ratings
+		WHERE user_id <=> ?
+		  AND topic_id <=> ?
+		ORDER BY date DESC, rating_id DESC
+		LIMIT 1
+		FOR UPDATE
+		`
+
+	// Execute prepared statement
+	err := tx.Get(&rating, query, nullID(userID), nullID(topicID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error getting current rating: %w", err)
+	}
+
+	return rating, nil
+}
+
+// nullID converts an ID to a value to be stored in a nullable column, whereas
+// an ID of 0 becomes NULL.
+func nullID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
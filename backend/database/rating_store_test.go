@@ -0,0 +1,232 @@
+// Collection of tests for the database access layer of functions evolving
+// around ratings of users and difficulties of topics.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tRating is a mock overall rating of a user for testing purposes
+	tRating = x.Rating{
+		RatingID: 1,
+		UserID:   1,
+		TopicID:  0,
+		Rating:   1012,
+		Date:     time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	// tRating2 is a mock rating of a user in a topic for testing purposes
+	tRating2 = x.Rating{
+		RatingID:  2,
+		UserID:    1,
+		TopicID:   1,
+		Rating:    1012,
+		Date:      time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+		TopicName: "Topic 1",
+	}
+
+	// nilRatings is a nil slice of ratings
+	nilRatings []x.Rating
+)
+
+// TestGetRatingsByUser tests getting the history of all ratings of a user.
+func TestGetRatingsByUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &RatingStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM ratings r (.+) WHERE r.user_id = \\? (.+) ORDER BY r.date"
+
+	table := []string{"rating_id", "user_id", "topic_id", "rating", "date", "topic_name"}
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		userID      int
+		mock        func(userID int)
+		wantRatings []x.Rating
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: 1,
+			mock: func(userID int) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tRating.RatingID, tRating.UserID, tRating.TopicID, tRating.Rating, tRating.Date,
+						tRating.TopicName).
+					AddRow(tRating2.RatingID, tRating2.UserID, tRating2.TopicID, tRating2.Rating, tRating2.Date,
+						tRating2.TopicName)
+
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
+			},
+			wantRatings: []x.Rating{tRating, tRating2},
+			wantError:   false,
+		},
+		{
+			// When the ratings table doesn't exist
+			name:   "#2 ERROR",
+			userID: 1,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("table ratings does not exist"))
+			},
+			wantRatings: nilRatings,
+			wantError:   true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			ratings, err := store.GetRatingsByUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetRatingsByUser() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(ratings, test.wantRatings) {
+				t.Errorf("GetRatingsByUser() = %v, want %v", ratings, test.wantRatings)
+			}
+		})
+	}
+}
+
+// TestCreateRatedScore tests creating a score together with the ratings
+// calculated from the current ratings, which get read after locking the topic.
+func TestCreateRatedScore(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &RatingStore{DB: db}
+	defer db.Close()
+
+	lockMatch := "SELECT topic_id FROM topics WHERE topic_id = \\? FOR UPDATE"
+	ratingMatch := "SELECT rating FROM ratings WHERE user_id <=> \\? AND topic_id <=> \\? (.+) FOR UPDATE"
+	scoreMatch := "INSERT INTO scores"
+	createMatch := "INSERT INTO ratings"
+
+	tScore := x.Score{TopicID: 1, UserID: 1, Points: 60, Date: tRating.Date}
+	tDifficulty := x.Rating{TopicID: 1, Rating: 994, Date: tRating.Date}
+
+	// rate returns the new ratings, remembering the current ratings it got
+	var gotCurrent []int
+	rate := func(rating int, topicRating int, difficulty int) []x.Rating {
+		gotCurrent = []int{rating, topicRating, difficulty}
+		return []x.Rating{tRating, tRating2, tDifficulty}
+	}
+
+	// expectCurrentRatings expects the current overall rating and difficulty,
+	// whereas the user hasn't got a rating in the topic yet
+	expectCurrentRatings := func() {
+		mock.ExpectQuery(lockMatch).WithArgs(tScore.TopicID).
+			WillReturnRows(sqlmock.NewRows([]string{"topic_id"}).AddRow(tScore.TopicID))
+		mock.ExpectQuery(ratingMatch).WithArgs(tScore.UserID, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"rating"}).AddRow(1000))
+		mock.ExpectQuery(ratingMatch).WithArgs(tScore.UserID, tScore.TopicID).
+			WillReturnRows(sqlmock.NewRows([]string{"rating"}))
+		mock.ExpectQuery(ratingMatch).WithArgs(nil, tScore.TopicID).
+			WillReturnRows(sqlmock.NewRows([]string{"rating"}).AddRow(1006))
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		mock        func()
+		wantCurrent []int
+		wantError   bool
+	}{
+		{
+			// When everything works as intended, whereas IDs of 0 are stored
+			// as NULL
+			name: "#1 OK",
+			mock: func() {
+				mock.ExpectBegin()
+				expectCurrentRatings()
+				mock.ExpectExec(scoreMatch).WithArgs(tScore.TopicID, tScore.UserID, tScore.Points, tScore.Date).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(createMatch).WithArgs(tRating.UserID, nil, tRating.Rating, tRating.Date).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(createMatch).WithArgs(tRating2.UserID, tRating2.TopicID, tRating2.Rating, tRating2.Date).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(createMatch).WithArgs(nil, tDifficulty.TopicID, tDifficulty.Rating, tDifficulty.Date).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+			},
+			wantCurrent: []int{1000, 0, 1006},
+			wantError:   false,
+		},
+		{
+			// When one of the ratings can't be created, which rolls back the
+			// score as well
+			name: "#2 ROLLBACK",
+			mock: func() {
+				mock.ExpectBegin()
+				expectCurrentRatings()
+				mock.ExpectExec(scoreMatch).WithArgs(tScore.TopicID, tScore.UserID, tScore.Points, tScore.Date).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(createMatch).WithArgs(tRating.UserID, nil, tRating.Rating, tRating.Date).
+					WillReturnError(errors.New("user does not exist"))
+				mock.ExpectRollback()
+			},
+			wantCurrent: []int{1000, 0, 1006},
+			wantError:   true,
+		},
+		{
+			// When the topic doesn't exist
+			name: "#3 TOPIC NOT FOUND",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockMatch).WithArgs(tScore.TopicID).
+					WillReturnRows(sqlmock.NewRows([]string{"topic_id"}))
+				mock.ExpectRollback()
+			},
+			wantCurrent: nil,
+			wantError:   true,
+		},
+		{
+			// When the transaction can't be started
+			name: "#4 BEGIN FAILED",
+			mock: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("connection lost"))
+			},
+			wantCurrent: nil,
+			wantError:   true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			gotCurrent = nil
+			test.mock()
+
+			err := store.CreateRatedScore(&tScore, rate)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateRatedScore() error = %v, want error %v", err, test.wantError)
+			}
+			if !reflect.DeepEqual(gotCurrent, test.wantCurrent) {
+				t.Errorf("CreateRatedScore() current ratings = %v, want %v", gotCurrent, test.wantCurrent)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateRatedScore() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// The database store evolving around revisions of events and topics, with all
+// necessary methods that access the database. Revisions are append-only, thus
+// there are no methods to update or delete revisions.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// RevisionStore is the MySQL database access object.
+type RevisionStore struct {
+	*sqlx.DB
+}
+
+// GetRevision gets a revision by ID.
+func (store *RevisionStore) GetRevision(revisionID int) (x.Revision, error) {
+	var revision x.Revision
+
+	query := `
+		SELECT r.*,
+		       COALESCE(u.username, '') AS user_name
+		FROM revisions r
+		    LEFT JOIN users u ON u.user_id = r.user_id
+		WHERE r.revision_id = ?
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&revision, query, revisionID); err != nil {
+		return x.Revision{}, fmt.Errorf("error getting revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// GetRevisionsByTarget gets all revisions of a certain event or topic, sorted
+// by date descending.
+func (store *RevisionStore) GetRevisionsByTarget(targetType string, targetID int) ([]x.Revision, error) {
+	var revisions []x.Revision
+
+	query := `
+		SELECT r.*,
+		       COALESCE(u.username, '') AS user_name
+		FROM revisions r
+		    LEFT JOIN users u ON u.user_id = r.user_id
+		WHERE r.target_type = ? 
+		  AND r.target_id = ?
+		ORDER BY r.date DESC, r.revision_id DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&revisions, query, targetType, targetID); err != nil {
+		return []x.Revision{}, fmt.Errorf("error getting revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// CreateRevision creates a new revision.
+func (store *RevisionStore) CreateRevision(revision *x.Revision) error {
+
+	query := `
+		INSERT INTO revisions(target_type, target_id, user_id, snapshot, date)
+		VALUES (?, ?, ?, ?, ?)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		revision.TargetType,
+		revision.TargetID,
+		revision.UserID,
+		revision.Snapshot,
+		revision.Date,
+	); err != nil {
+		return fmt.Errorf("error creating revision: %w", err)
+	}
+
+	return nil
+}
+
+// IsImageReferenced checks whether an image is still referenced by a topic or
+// event, including those in the trash, or by a revision of an existing topic or
+// event, which could restore the image when reverted to. Revisions of purged
+// topics and events are ignored.
+func (store *RevisionStore) IsImageReferenced(imageURL string) (bool, error) {
+	var referenced bool
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM topics WHERE image = ?)
+		    OR EXISTS(SELECT 1 FROM events WHERE image = ?)
+		    OR EXISTS(SELECT 1 
+		              FROM revisions r
+		              WHERE r.snapshot LIKE CONCAT('%"Image":"', ?, '"%')
+		                AND ((r.target_type = 'topic' AND r.target_id IN (SELECT topic_id FROM topics))
+		                  OR (r.target_type = 'event' AND r.target_id IN (SELECT event_id FROM events))))
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&referenced, query, imageURL, imageURL, imageURL); err != nil {
+		return false, fmt.Errorf("error checking references of image: %w", err)
+	}
+
+	return referenced, nil
+}
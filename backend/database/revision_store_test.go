@@ -0,0 +1,327 @@
+// Collection of tests for the database access layer of functions evolving
+// around revisions of events and topics.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tRevision is a mock revision for testing purposes
+	tRevision = x.Revision{
+		RevisionID: 1,
+		TargetType: "event",
+		TargetID:   1,
+		UserID:     1,
+		Snapshot:   `{"EventID":1,"Name":"Event 1","Year":1800}`,
+		Date:       time.Now(),
+		UserName:   "user_1",
+	}
+
+	// tRevision2 is a mock revision for testing purposes
+	tRevision2 = x.Revision{
+		RevisionID: 2,
+		TargetType: "event",
+		TargetID:   1,
+		UserID:     2,
+		Snapshot:   `{"EventID":1,"Name":"Event 1","Year":1850}`,
+		Date:       time.Now().Add(time.Hour * 1),
+		UserName:   "user_2",
+	}
+
+	// nilRevisions is a nil slice of revisions
+	nilRevisions []x.Revision
+)
+
+// TestGetRevision tests getting a revision by ID.
+func TestGetRevision(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &RevisionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM revisions"
+
+	table := []string{"revision_id", "target_type", "target_id", "user_id", "snapshot", "date", "user_name"}
+
+	// Declare test cases
+	tests := []struct {
+		name         string
+		revisionID   int
+		mock         func(revisionID int)
+		wantRevision x.Revision
+		wantError    bool
+	}{
+		{
+			// When everything works as intended
+			name:       "#1 OK",
+			revisionID: tRevision.RevisionID,
+			mock: func(revisionID int) {
+				rows := sqlmock.NewRows(table).AddRow(tRevision.RevisionID, tRevision.TargetType,
+					tRevision.TargetID, tRevision.UserID, tRevision.Snapshot, tRevision.Date, tRevision.UserName)
+
+				mock.ExpectQuery(queryMatch).WithArgs(revisionID).WillReturnRows(rows)
+			},
+			wantRevision: tRevision,
+			wantError:    false,
+		},
+		{
+			// When revision with given revision ID doesn't exist
+			name:       "#2 NOT FOUND",
+			revisionID: 0,
+			mock: func(revisionID int) {
+				rows := sqlmock.NewRows(table)
+
+				mock.ExpectQuery(queryMatch).WithArgs(revisionID).WillReturnRows(rows)
+			},
+			wantRevision: x.Revision{},
+			wantError:    true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.revisionID)
+
+			revision, err := store.GetRevision(test.revisionID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetRevision() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(revision, test.wantRevision) {
+				t.Errorf("GetRevision() = %v, want %v", revision, test.wantRevision)
+			}
+		})
+	}
+}
+
+// TestGetRevisionsByTarget tests getting all revisions of a certain event or
+// topic.
+func TestGetRevisionsByTarget(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &RevisionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM revisions (.+) WHERE r.target_type = (.+) AND r.target_id = (.+) ORDER BY"
+
+	tRevisions := []x.Revision{tRevision2, tRevision}
+	table := []string{"revision_id", "target_type", "target_id", "user_id", "snapshot", "date", "user_name"}
+
+	// Declare test cases
+	tests := []struct {
+		name          string
+		targetType    string
+		targetID      int
+		mock          func(targetType string, targetID int)
+		wantRevisions []x.Revision
+		wantError     bool
+	}{
+		{
+			// When everything works as intended
+			name:       "#1 OK",
+			targetType: "event",
+			targetID:   1,
+			mock: func(targetType string, targetID int) {
+				rows := sqlmock.NewRows(table)
+				for _, revision := range tRevisions {
+					rows = rows.AddRow(revision.RevisionID, revision.TargetType, revision.TargetID,
+						revision.UserID, revision.Snapshot, revision.Date, revision.UserName)
+				}
+
+				mock.ExpectQuery(queryMatch).WithArgs(targetType, targetID).WillReturnRows(rows)
+			},
+			wantRevisions: tRevisions,
+			wantError:     false,
+		},
+		{
+			// When there are no revisions of the target
+			name:       "#2 OK (NO ROWS)",
+			targetType: "topic",
+			targetID:   1,
+			mock: func(targetType string, targetID int) {
+				rows := sqlmock.NewRows(table)
+
+				mock.ExpectQuery(queryMatch).WithArgs(targetType, targetID).WillReturnRows(rows)
+			},
+			wantRevisions: nilRevisions,
+			wantError:     false,
+		},
+		{
+			// When the revisions table doesn't exist
+			name:       "#3 ERROR",
+			targetType: "event",
+			targetID:   1,
+			mock: func(targetType string, targetID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(targetType, targetID).
+					WillReturnError(errors.New("table revisions does not exist"))
+			},
+			wantRevisions: nil,
+			wantError:     true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.targetType, test.targetID)
+
+			revisions, err := store.GetRevisionsByTarget(test.targetType, test.targetID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetRevisionsByTarget() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(revisions, test.wantRevisions) {
+				t.Errorf("GetRevisionsByTarget() = %v, want %v", revisions, test.wantRevisions)
+			}
+		})
+	}
+}
+
+// TestCreateRevision tests creating a new revision.
+func TestCreateRevision(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &RevisionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO revisions"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		revision  x.Revision
+		mock      func(revision x.Revision)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:     "#1 OK",
+			revision: tRevision,
+			mock: func(revision x.Revision) {
+				mock.ExpectExec(queryMatch).WithArgs(revision.TargetType, revision.TargetID, revision.UserID,
+					revision.Snapshot, revision.Date).
+					WillReturnResult(sqlmock.NewResult(int64(revision.RevisionID), 1))
+			},
+			wantError: false,
+		},
+		{
+			// When snapshot is missing
+			name: "#2 SNAPSHOT MISSING",
+			revision: x.Revision{
+				TargetType: tRevision.TargetType,
+				TargetID:   tRevision.TargetID,
+				UserID:     tRevision.UserID,
+				Date:       tRevision.Date,
+			},
+			mock: func(revision x.Revision) {
+				mock.ExpectExec(queryMatch).WithArgs(revision.TargetType, revision.TargetID, revision.UserID,
+					revision.Snapshot, revision.Date).
+					WillReturnError(errors.New("snapshot can not be empty"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.revision)
+
+			err := store.CreateRevision(&test.revision)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateRevision() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestIsImageReferenced tests checking whether an image is still referenced by
+// a topic, an event or a revision.
+func TestIsImageReferenced(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &RevisionStore{DB: db}
+	defer db.Close()
+
+	tImage := "/frontend/static/uploads/abc.png"
+	queryMatch := "SELECT EXISTS\\((.+) FROM topics WHERE image = \\?\\)(.+)FROM revisions"
+
+	// Declare test cases
+	tests := []struct {
+		name           string
+		mock           func()
+		wantReferenced bool
+		wantError      bool
+	}{
+		{
+			// When the image is still referenced
+			name: "#1 REFERENCED",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"referenced"}).AddRow(true)
+
+				mock.ExpectQuery(queryMatch).WithArgs(tImage, tImage, tImage).WillReturnRows(rows)
+			},
+			wantReferenced: true,
+			wantError:      false,
+		},
+		{
+			// When the image isn't referenced anymore
+			name: "#2 NOT REFERENCED",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"referenced"}).AddRow(false)
+
+				mock.ExpectQuery(queryMatch).WithArgs(tImage, tImage, tImage).WillReturnRows(rows)
+			},
+			wantReferenced: false,
+			wantError:      false,
+		},
+		{
+			// When the revisions table doesn't exist
+			name: "#3 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WithArgs(tImage, tImage, tImage).
+					WillReturnError(errors.New("table revisions does not exist"))
+			},
+			wantReferenced: false,
+			wantError:      true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			referenced, err := store.IsImageReferenced(tImage)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("IsImageReferenced() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if referenced != test.wantReferenced {
+				t.Errorf("IsImageReferenced() = %v, want %v", referenced, test.wantReferenced)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package database
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -17,84 +18,212 @@ type ScoreStore struct {
 	*sqlx.DB
 }
 
-// GetScores gets all scores, sorted by points descending.
-func (store *ScoreStore) GetScores() ([]x.Score, error) {
+// GetScoresByUser gets all scores of a user, sorted by date, in order to
+// show the history of the user's points in every topic.
+func (store *ScoreStore) GetScoresByUser(userID int) ([]x.Score, error) {
 	var scores []x.Score
 
 	query := `
-		SELECT s.*, 
+		SELECT s.score_id, s.topic_id, s.user_id, s.points, s.date, 
 		       t.name AS topic_name, 
 		       u.username AS user_name
 		FROM scores s 
-		    LEFT JOIN topics t ON t.topic_id = s.topic_id 
-		    LEFT JOIN users u ON u.user_id = s.user_id
-		ORDER BY points DESC
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		WHERE s.user_id = ? 
+		  AND t.deleted_at IS NULL
+		ORDER BY s.date, s.score_id
 		`
 
 	// Execute prepared statement
-	if err := store.Select(&scores, query); err != nil {
-		return []x.Score{}, fmt.Errorf("error getting scores: %w", err)
+	if err := store.Select(&scores, query, userID); err != nil {
+		return []x.Score{}, fmt.Errorf("error getting scores of user: %w", err)
 	}
 
 	return scores, nil
 }
 
-// GetScoresByTopic gets scores of a certain topic, sorted by points
-// descending.
-func (store *ScoreStore) GetScoresByTopic(topicID int) ([]x.Score, error) {
-	var scores []x.Score
+// GetTopicStatsByUser gets the statistics of a user in every topic played,
+// being the amount of attempts, the best and average points and the
+// percentile of the user's best points among the best points of all players
+// of the topic.
+func (store *ScoreStore) GetTopicStatsByUser(userID int) ([]x.TopicStats, error) {
+	var stats []x.TopicStats
 
 	query := `
+		SELECT players.topic_id,
+		       t.name AS topic_name,
+		       players.attempts_count,
+		       players.best_points,
+		       players.average_points,
+		       players.percentile,
+		       players.players_count
+		FROM (
+		    SELECT best.*,
+		           ROUND(PERCENT_RANK() OVER (PARTITION BY best.topic_id ORDER BY best.best_points) * 100) AS percentile,
+		           COUNT(*) OVER (PARTITION BY best.topic_id) AS players_count
+		    FROM (
+		        SELECT s.topic_id, s.user_id,
+		               COUNT(*) AS attempts_count,
+		               MAX(s.points) AS best_points,
+		               AVG(s.points) AS average_points
+		        FROM scores s
+		            JOIN users u ON u.user_id = s.user_id
+		        WHERE u.deleted_at IS NULL
+		        GROUP BY s.topic_id, s.user_id
+		    ) best
+		) players
+		    JOIN topics t ON t.topic_id = players.topic_id
+		WHERE players.user_id = ?
+		  AND t.deleted_at IS NULL
+		ORDER BY players.topic_id
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&stats, query, userID); err != nil {
+		return []x.TopicStats{}, fmt.Errorf("error getting statistics of user: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetLeaderboard gets a page of the leaderboard matching the filter, ranked by
+// points descending, whereas equal points share a rank and the earlier score
+// comes first. The rank is computed over the whole leaderboard, not only over
+// the page. Depending on the mode, a row is either a score, the best score of
+// a user per topic, the sum of the best scores of a user across topics or the
+// current rating of a user, which then stands in place of the points.
+func (store *ScoreStore) GetLeaderboard(filter x.LeaderboardFilter) ([]x.Score, error) {
+	var scores []x.Score
+
+	where, args := leaderboardConditions(filter)
+
+	var query string
+	switch filter.Mode {
+	case x.LeaderboardRating:
+		where, args = ratingConditions(filter)
+		query = `
+		SELECT 0 AS score_id, topic_id, user_id, rating AS points, date, topic_name, user_name,
+		       RANK() OVER (ORDER BY rating DESC) AS ranking
+		FROM (
+		    SELECT r.user_id,
+		           COALESCE(r.topic_id, 0) AS topic_id,
+		           r.rating,
+		           r.date,
+		           COALESCE(t.name, '') AS topic_name,
+		           u.username AS user_name,
+		           ROW_NUMBER() OVER (PARTITION BY r.user_id 
+		               ORDER BY r.date DESC, r.rating_id DESC) AS attempt
+		    FROM ratings r
+		        JOIN users u ON u.user_id = r.user_id
+		        LEFT JOIN topics t ON t.topic_id = r.topic_id
+		    ` + where + `
+		) latest
+		WHERE latest.attempt = 1
+		ORDER BY rating DESC, date, user_id
+		LIMIT ? OFFSET ?
+		`
+	case x.LeaderboardBest:
+		query = `
+		SELECT score_id, topic_id, user_id, points, date, topic_name, user_name,
+		       RANK() OVER (ORDER BY points DESC) AS ranking
+		FROM (` + bestScoresQuery(where) + `) best
+		WHERE best.attempt = 1
+		ORDER BY points DESC, date, score_id
+		LIMIT ? OFFSET ?
+		`
+	case x.LeaderboardTotal:
+		// The topic is only known, if the user's total consists of one topic
+		query = `
+		SELECT 0 AS score_id,
+		       IF(COUNT(*) = 1, MIN(topic_id), 0) AS topic_id,
+		       user_id,
+		       SUM(points) AS points,
+		       MAX(date) AS date,
+		       IF(COUNT(*) = 1, MIN(topic_name), '') AS topic_name,
+		       user_name,
+		       RANK() OVER (ORDER BY SUM(points) DESC) AS ranking
+		FROM (` + bestScoresQuery(where) + `) best
+		WHERE best.attempt = 1
+		GROUP BY user_id, user_name
+		ORDER BY points DESC, date, user_id
+		LIMIT ? OFFSET ?
+		`
+	default:
+		query = `
 		SELECT s.score_id, s.topic_id, s.user_id, s.points, s.date, 
 		       t.name AS topic_name, 
-		       u.username AS user_name
+		       u.username AS user_name,
+		       RANK() OVER (ORDER BY s.points DESC) AS ranking
 		FROM scores s 
-		    LEFT JOIN topics t ON t.topic_id = s.topic_id 
-		    LEFT JOIN users u ON u.user_id = s.user_id
-		WHERE s.topic_id = ?
-		ORDER BY points DESC
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		` + where + `
+		ORDER BY s.points DESC, s.date, s.score_id
+		LIMIT ? OFFSET ?
 		`
+	}
+	args = append(args, filter.Limit, filter.Offset)
 
 	// Execute prepared statement
-	if err := store.Select(&scores, query, topicID); err != nil {
-		return []x.Score{}, fmt.Errorf("error getting scores: %w", err)
+	if err := store.Select(&scores, query, args...); err != nil {
+		return []x.Score{}, fmt.Errorf("error getting leaderboard: %w", err)
 	}
 
 	return scores, nil
 }
 
-// GetScoresByTopicAndUser gets scores of a certain topic and user, sorted by
-// points descending.
-func (store *ScoreStore) GetScoresByTopicAndUser(topicID int, userID int) ([]x.Score, error) {
-	var scores []x.Score
+// CountLeaderboard gets amount of rows of a leaderboard matching the filter.
+func (store *ScoreStore) CountLeaderboard(filter x.LeaderboardFilter) (int, error) {
+	var rowsCount int
 
+	// What is counted depends on what a row of the leaderboard is
+	count := "COUNT(*)"
+	switch filter.Mode {
+	case x.LeaderboardBest:
+		count = "COUNT(DISTINCT s.user_id, s.topic_id)"
+	case x.LeaderboardTotal:
+		count = "COUNT(DISTINCT s.user_id)"
+	}
+
+	where, args := leaderboardConditions(filter)
 	query := `
-		SELECT s.*, 
-		       t.name AS topic_name, 
-		       u.username AS user_name
+		SELECT ` + count + `
 		FROM scores s 
-		    LEFT JOIN topics t ON t.topic_id = s.topic_id 
-		    LEFT JOIN users u ON u.user_id = s.user_id
-		WHERE s.topic_id = ? 
-		  AND s.user_id = ?
-		ORDER BY points DESC
-		`
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		` + where
+
+	if filter.Mode == x.LeaderboardRating {
+		where, args = ratingConditions(filter)
+		query = `
+		SELECT COUNT(DISTINCT r.user_id)
+		FROM ratings r
+		    JOIN users u ON u.user_id = r.user_id
+		    LEFT JOIN topics t ON t.topic_id = r.topic_id
+		` + where
+	}
 
 	// Execute prepared statement
-	if err := store.Select(&scores, query, topicID, userID); err != nil {
-		return []x.Score{}, fmt.Errorf("error getting scores: %w", err)
+	if err := store.Get(&rowsCount, query, args...); err != nil {
+		return 0, fmt.Errorf("error getting number of rows of leaderboard: %w", err)
 	}
 
-	return scores, nil
+	return rowsCount, nil
 }
 
-// CountScores gets amount of scores.
+// CountScores gets amount of scores, excluding scores of topics and users in
+// the trash, like the leaderboard.
 func (store *ScoreStore) CountScores() (int, error) {
 	var scoresCount int
 
 	query := `
-		SELECT COUNT(score_id) 
-		FROM scores
+		SELECT COUNT(s.score_id) 
+		FROM scores s 
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		WHERE t.deleted_at IS NULL 
+		  AND u.deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -105,14 +234,19 @@ func (store *ScoreStore) CountScores() (int, error) {
 	return scoresCount, nil
 }
 
-// CountScoresByDate gets amount of scores in a certain date range.
+// CountScoresByDate gets amount of scores in a certain date range, excluding
+// scores of topics and users in the trash, like the leaderboard.
 func (store *ScoreStore) CountScoresByDate(start time.Time, end time.Time) (int, error) {
 	var scoresCount int
 
 	query := `
-		SELECT COUNT(score_id) 
-		FROM scores
-		WHERE date BETWEEN ? AND ?
+		SELECT COUNT(s.score_id) 
+		FROM scores s 
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		WHERE s.date BETWEEN ? AND ?
+		  AND t.deleted_at IS NULL 
+		  AND u.deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -123,6 +257,55 @@ func (store *ScoreStore) CountScoresByDate(start time.Time, end time.Time) (int,
 	return scoresCount, nil
 }
 
+// CountLowerScoresByTopic gets amount of scores of a certain topic with less
+// points than the given points, as well as the total amount of scores of the
+// topic, in order to compare a user's points to all others.
+func (store *ScoreStore) CountLowerScoresByTopic(topicID int, points int) (int, int, error) {
+	var counts struct {
+		Lower int `db:"lower_count"`
+		Total int `db:"total_count"`
+	}
+
+	query := `
+		SELECT COALESCE(SUM(s.points < ?), 0) AS lower_count, 
+		       COUNT(*) AS total_count
+		FROM scores s 
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		WHERE s.topic_id = ? 
+		  AND t.deleted_at IS NULL 
+		  AND u.deleted_at IS NULL
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&counts, query, points, topicID); err != nil {
+		return 0, 0, fmt.Errorf("error getting number of lower scores: %w", err)
+	}
+
+	return counts.Lower, counts.Total, nil
+}
+
+// CountTopicsByUser gets amount of different topics, in which a user has at
+// least 1 score.
+func (store *ScoreStore) CountTopicsByUser(userID int) (int, error) {
+	var topicsCount int
+
+	query := `
+		SELECT COUNT(DISTINCT s.topic_id)
+		FROM scores s 
+		    JOIN topics t ON t.topic_id = s.topic_id
+		WHERE s.user_id = ? 
+		  AND t.deleted_at IS NULL
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&topicsCount, query, userID); err != nil {
+		return 0, fmt.Errorf("error getting number of topics of user: %w", err)
+	}
+
+	return topicsCount, nil
+}
+
 // CreateScore creates a new score.
 func (store *ScoreStore) CreateScore(score *x.Score) error {
 
@@ -143,3 +326,69 @@ func (store *ScoreStore) CreateScore(score *x.Score) error {
 
 	return nil
 }
+
+// leaderboardConditions generates the WHERE-clause and its arguments for the
+// criteria of a leaderboard, which are not empty. Scores of topics and users
+// in the trash are always excluded.
+func leaderboardConditions(filter x.LeaderboardFilter) (string, []interface{}) {
+	conditions := []string{"t.deleted_at IS NULL", "u.deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.TopicID != 0 {
+		conditions = append(conditions, "s.topic_id = ?")
+		args = append(args, filter.TopicID)
+	}
+	if filter.Class != "" {
+		conditions = append(conditions, "u.class = ?")
+		args = append(args, filter.Class)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "s.date >= ?")
+		args = append(args, filter.Since)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ratingConditions generates the WHERE-clause and its arguments for the
+// criteria of a leaderboard of ratings. Without a topic, the overall ratings
+// of the users are used. Ratings of topics and users in the trash are always
+// excluded, just like the difficulties of topics.
+func ratingConditions(filter x.LeaderboardFilter) (string, []interface{}) {
+	conditions := []string{"r.user_id IS NOT NULL", "t.deleted_at IS NULL", "u.deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.TopicID != 0 {
+		conditions = append(conditions, "r.topic_id = ?")
+		args = append(args, filter.TopicID)
+	} else {
+		conditions = append(conditions, "r.topic_id IS NULL")
+	}
+	if filter.Class != "" {
+		conditions = append(conditions, "u.class = ?")
+		args = append(args, filter.Class)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "r.date >= ?")
+		args = append(args, filter.Since)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// bestScoresQuery generates the query for the scores matching the
+// WHERE-clause, numbered by attempt per user and topic, whereas the best score
+// of a user per topic is attempt 1. Equal points are decided by date, thus the
+// earlier score is the best one.
+func bestScoresQuery(where string) string {
+	return `
+		SELECT s.score_id, s.topic_id, s.user_id, s.points, s.date, 
+		       t.name AS topic_name, 
+		       u.username AS user_name,
+		       ROW_NUMBER() OVER (PARTITION BY s.user_id, s.topic_id 
+		           ORDER BY s.points DESC, s.date, s.score_id) AS attempt
+		FROM scores s 
+		    JOIN topics t ON t.topic_id = s.topic_id 
+		    JOIN users u ON u.user_id = s.user_id
+		` + where
+}
@@ -54,51 +54,142 @@ var (
 	nilScores []x.Score
 )
 
-// TestGetScores tests getting all scores.
-func TestGetScores(t *testing.T) {
+// TestGetLeaderboard tests getting the ranked scores matching a filter.
+func TestGetLeaderboard(t *testing.T) {
 
 	// New mock database
 	db, mock := NewMock()
 	store := &ScoreStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT (.+) FROM scores"
+	queryMatch := "SELECT (.+) RANK\\(\\) OVER (.+) FROM scores s (.+) WHERE (.+) ORDER BY s.points DESC(.+) LIMIT (.+) OFFSET"
 
-	tScores := []x.Score{tScore, tScore2, tScore3}
-	table := []string{"score_id", "topic_id", "user_id", "points", "date", "topic_name", "user_name"}
+	bestMatch := "SELECT (.+) RANK\\(\\) OVER (.+) FROM \\( SELECT (.+) ROW_NUMBER\\(\\) OVER " +
+		"\\(PARTITION BY s.user_id, s.topic_id (.+)\\) best WHERE best.attempt = 1 (.+) LIMIT (.+) OFFSET"
+	totalMatch := "SELECT (.+) SUM\\(points\\) AS points, (.+) FROM \\( SELECT (.+)\\) best " +
+		"WHERE best.attempt = 1 GROUP BY user_id, user_name (.+) LIMIT (.+) OFFSET"
+	ratingMatch := "SELECT (.+) rating AS points, (.+) FROM \\( SELECT (.+) FROM ratings r (.+) " +
+		"WHERE r.user_id IS NOT NULL (.+) r.topic_id IS NULL(.+)\\) latest WHERE latest.attempt = 1 (.+) LIMIT"
+
+	since := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	table := []string{"score_id", "topic_id", "user_id", "points", "date", "topic_name", "user_name", "ranking"}
 
 	// Declare test cases
 	tests := []struct {
 		name       string
-		mock       func()
+		filter     x.LeaderboardFilter
+		mock       func(filter x.LeaderboardFilter)
 		wantScores []x.Score
 		wantError  bool
 	}{
 		{
 			// When everything works as intended
-			name: "#1 OK",
-			mock: func() {
-				rows := sqlmock.NewRows(table)
-				for _, score := range tScores {
-					rows = rows.AddRow(score.ScoreID, score.TopicID, score.UserID, score.Points, score.Date,
-						score.TopicName, score.UserName)
-				}
-
-				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			name:   "#1 OK",
+			filter: x.LeaderboardFilter{Limit: 10},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tScore3.ScoreID, tScore3.TopicID, tScore3.UserID, tScore3.Points, tScore3.Date,
+						tScore3.TopicName, tScore3.UserName, 1).
+					AddRow(tScore2.ScoreID, tScore2.TopicID, tScore2.UserID, tScore2.Points, tScore2.Date,
+						tScore2.TopicName, tScore2.UserName, 2)
+
+				mock.ExpectQuery(queryMatch).WithArgs(filter.Limit, filter.Offset).WillReturnRows(rows)
 			},
-			wantScores: tScores,
-			wantError:  false,
+			wantScores: func() []x.Score {
+				score3, score2 := tScore3, tScore2
+				score3.Rank, score2.Rank = 1, 2
+				return []x.Score{score3, score2}
+			}(),
+			wantError: false,
 		},
 		{
-			// When the scores table is empty
-			name: "#2 OK (NO ROWS)",
-			mock: func() {
-				rows := sqlmock.NewRows(table)
-
-				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			// When filtering by topic, class and time window
+			name:   "#2 OK (FILTERED)",
+			filter: x.LeaderboardFilter{TopicID: 1, Class: "4a", Since: since, Limit: 10, Offset: 20},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tScore.ScoreID, tScore.TopicID, tScore.UserID, tScore.Points, tScore.Date,
+						tScore.TopicName, tScore.UserName, 1)
+
+				mock.ExpectQuery(queryMatch).
+					WithArgs(filter.TopicID, filter.Class, filter.Since, filter.Limit, filter.Offset).
+					WillReturnRows(rows)
+			},
+			wantScores: func() []x.Score {
+				score := tScore
+				score.Rank = 1
+				return []x.Score{score}
+			}(),
+			wantError: false,
+		},
+		{
+			// When listing the best score of every user per topic
+			name:   "#3 OK (BEST)",
+			filter: x.LeaderboardFilter{Mode: x.LeaderboardBest, Class: "4a", Limit: 10},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tScore2.ScoreID, tScore2.TopicID, tScore2.UserID, tScore2.Points, tScore2.Date,
+						tScore2.TopicName, tScore2.UserName, 1)
+
+				mock.ExpectQuery(bestMatch).WithArgs(filter.Class, filter.Limit, filter.Offset).
+					WillReturnRows(rows)
+			},
+			wantScores: func() []x.Score {
+				score2 := tScore2
+				score2.Rank = 1
+				return []x.Score{score2}
+			}(),
+			wantError: false,
+		},
+		{
+			// When listing the total points of every user across topics
+			name:   "#4 OK (TOTAL)",
+			filter: x.LeaderboardFilter{Mode: x.LeaderboardTotal, Limit: 10},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).
+					AddRow(0, 0, tScore.UserID, tScore.Points+tScore3.Points, tScore3.Date,
+						"", tScore.UserName, 1)
+
+				mock.ExpectQuery(totalMatch).WithArgs(filter.Limit, filter.Offset).WillReturnRows(rows)
+			},
+			wantScores: []x.Score{{
+				UserID:   tScore.UserID,
+				Points:   tScore.Points + tScore3.Points,
+				Date:     tScore3.Date,
+				UserName: tScore.UserName,
+				Rank:     1,
+			}},
+			wantError: false,
+		},
+		{
+			// When listing the current overall rating of every user
+			name:   "#5 OK (RATING)",
+			filter: x.LeaderboardFilter{Mode: x.LeaderboardRating, Limit: 10},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).
+					AddRow(0, 0, tScore2.UserID, 1016, tScore2.Date, "", tScore2.UserName, 1)
+
+				mock.ExpectQuery(ratingMatch).WithArgs(filter.Limit, filter.Offset).WillReturnRows(rows)
+			},
+			wantScores: []x.Score{{
+				UserID:   tScore2.UserID,
+				Points:   1016,
+				Date:     tScore2.Date,
+				UserName: tScore2.UserName,
+				Rank:     1,
+			}},
+			wantError: false,
+		},
+		{
+			// When the scores table doesn't exist
+			name:   "#6 ERROR",
+			filter: x.LeaderboardFilter{TopicID: 1, Limit: 10},
+			mock: func(filter x.LeaderboardFilter) {
+				mock.ExpectQuery(queryMatch).WithArgs(filter.TopicID, filter.Limit, filter.Offset).
+					WillReturnError(errors.New("table scores does not exist"))
 			},
 			wantScores: nilScores,
-			wantError:  false,
+			wantError:  true,
 		},
 	}
 
@@ -106,77 +197,77 @@ func TestGetScores(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 
-			test.mock()
+			test.mock(test.filter)
 
-			scores, err := store.GetScores()
+			scores, err := store.GetLeaderboard(test.filter)
 
 			if (err != nil) != test.wantError {
-				t.Errorf("GetScores() error = %v, want error %v", err, test.wantError)
+				t.Errorf("GetLeaderboard() error = %v, want error %v", err, test.wantError)
 				return
 			}
 			if err == nil && !reflect.DeepEqual(scores, test.wantScores) {
-				t.Errorf("GetScores() = %v, want %v", scores, test.wantScores)
+				t.Errorf("GetLeaderboard() = %v, want %v", scores, test.wantScores)
 			}
 		})
 	}
 }
 
-// TestGetScoresByTopic tests getting all scores of a certain topic.
-func TestGetScoresByTopic(t *testing.T) {
+// TestGetScoresByUser tests getting all scores of a certain user.
+func TestGetScoresByUser(t *testing.T) {
 
 	// New mock database
 	db, mock := NewMock()
 	store := &ScoreStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT (.+) FROM scores"
+	queryMatch := "SELECT (.+) FROM scores s (.+) WHERE s.user_id = \\? (.+) ORDER BY s.date"
 
-	tScores := []x.Score{tScore, tScore2}
+	tScores := []x.Score{tScore, tScore3}
 	table := []string{"score_id", "topic_id", "user_id", "points", "date", "topic_name", "user_name"}
 
 	// Declare test cases
 	tests := []struct {
 		name       string
-		topicID    int
-		mock       func(topicID int)
+		userID     int
+		mock       func(userID int)
 		wantScores []x.Score
 		wantError  bool
 	}{
 		{
 			// When everything works as intended
-			name:    "#1 OK",
-			topicID: tScores[0].TopicID,
-			mock: func(topicID int) {
+			name:   "#1 OK",
+			userID: 1,
+			mock: func(userID int) {
 				rows := sqlmock.NewRows(table)
 				for _, score := range tScores {
 					rows = rows.AddRow(score.ScoreID, score.TopicID, score.UserID, score.Points, score.Date,
 						score.TopicName, score.UserName)
 				}
 
-				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(rows)
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
 			},
 			wantScores: tScores,
 			wantError:  false,
 		},
 		{
-			// When the scores table is empty
-			name:    "#2 OK (NO ROWS)",
-			topicID: tScores[0].TopicID,
-			mock: func(topicID int) {
+			// When the user hasn't played yet
+			name:   "#2 OK (NO ROWS)",
+			userID: 1,
+			mock: func(userID int) {
 				rows := sqlmock.NewRows(table)
 
-				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(rows)
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
 			},
 			wantScores: nilScores,
 			wantError:  false,
 		},
 		{
-			// When topic with given topic ID doesn't exist
-			name:    "#3 TOPIC NOT FOUND",
-			topicID: 0,
-			mock: func(topicID int) {
-				mock.ExpectQuery(queryMatch).WithArgs(topicID).
-					WillReturnError(errors.New("topic with given id does not exist"))
+			// When the scores table doesn't exist
+			name:   "#3 ERROR",
+			userID: 1,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("table scores does not exist"))
 			},
 			wantScores: nilScores,
 			wantError:  true,
@@ -187,97 +278,77 @@ func TestGetScoresByTopic(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 
-			test.mock(test.topicID)
+			test.mock(test.userID)
 
-			scores, err := store.GetScoresByTopic(test.topicID)
+			scores, err := store.GetScoresByUser(test.userID)
 
 			if (err != nil) != test.wantError {
-				t.Errorf("GetScoresByTopic() error = %v, want error %v", err, test.wantError)
+				t.Errorf("GetScoresByUser() error = %v, want error %v", err, test.wantError)
 				return
 			}
 			if err == nil && !reflect.DeepEqual(scores, test.wantScores) {
-				t.Errorf("GetScoresByTopic() = %v, want %v", scores, test.wantScores)
+				t.Errorf("GetScoresByUser() = %v, want %v", scores, test.wantScores)
 			}
 		})
 	}
 }
 
-// TestGetScoresByTopicAndUser tests getting all scores of a certain topic and
-// a certain user.
-func TestGetScoresByTopicAndUser(t *testing.T) {
+// TestGetTopicStatsByUser tests getting the statistics of a user in every
+// topic played.
+func TestGetTopicStatsByUser(t *testing.T) {
 
 	// New mock database
 	db, mock := NewMock()
 	store := &ScoreStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT (.+) FROM scores"
-
-	tScores := []x.Score{tScore}
-	table := []string{"score_id", "topic_id", "user_id", "points", "date", "topic_name", "user_name"}
+	queryMatch := "SELECT (.+)PERCENT_RANK\\(\\) OVER \\(PARTITION BY best.topic_id ORDER BY best.best_points\\)" +
+		"(.+) GROUP BY s.topic_id, s.user_id (.+) WHERE players.user_id = \\?"
+
+	tStats := x.TopicStats{
+		TopicID:       1,
+		TopicName:     "Topic 1",
+		AttemptsCount: 3,
+		BestPoints:    60,
+		AveragePoints: 48.3333,
+		Percentile:    75,
+		PlayersCount:  5,
+	}
+	table := []string{"topic_id", "topic_name", "attempts_count", "best_points", "average_points", "percentile",
+		"players_count"}
 
 	// Declare test cases
 	tests := []struct {
-		name       string
-		topicID    int
-		userID     int
-		mock       func(topicID int, userID int)
-		wantScores []x.Score
-		wantError  bool
+		name      string
+		userID    int
+		mock      func(userID int)
+		wantStats []x.TopicStats
+		wantError bool
 	}{
 		{
 			// When everything works as intended
-			name:    "#1 OK",
-			topicID: tScores[0].TopicID,
-			userID:  tScores[0].UserID,
-			mock: func(topicID int, userID int) {
-				rows := sqlmock.NewRows(table)
-				for _, score := range tScores {
-					rows = rows.AddRow(score.ScoreID, score.TopicID, score.UserID, score.Points, score.Date,
-						score.TopicName, score.UserName)
-				}
-
-				mock.ExpectQuery(queryMatch).WithArgs(topicID, userID).WillReturnRows(rows)
-			},
-			wantScores: tScores,
-			wantError:  false,
-		},
-		{
-			// When the scores table is empty
-			name:    "#2 OK (NO ROWS)",
-			topicID: tScores[0].TopicID,
-			userID:  tScores[0].UserID,
-			mock: func(topicID int, userID int) {
-				rows := sqlmock.NewRows(table)
-
-				mock.ExpectQuery(queryMatch).WithArgs(topicID, userID).WillReturnRows(rows)
+			name:   "#1 OK",
+			userID: 1,
+			mock: func(userID int) {
+				rows := sqlmock.NewRows(table).
+					AddRow(tStats.TopicID, tStats.TopicName, tStats.AttemptsCount, tStats.BestPoints,
+						tStats.AveragePoints, tStats.Percentile, tStats.PlayersCount)
+
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
 			},
-			wantScores: nilScores,
-			wantError:  false,
-		},
-		{
-			// When topic with given topic ID doesn't exist
-			name:    "#3 TOPIC NOT FOUND",
-			topicID: 0,
-			userID:  tScores[0].UserID,
-			mock: func(topicID int, userID int) {
-				mock.ExpectQuery(queryMatch).WithArgs(topicID, userID).
-					WillReturnError(errors.New("topic with given id does not exist"))
-			},
-			wantScores: nilScores,
-			wantError:  true,
+			wantStats: []x.TopicStats{tStats},
+			wantError: false,
 		},
 		{
-			// When user with given user ID doesn't exist
-			name:    "#4 USER NOT FOUND",
-			topicID: tScores[0].TopicID,
-			userID:  0,
-			mock: func(topicID int, userID int) {
-				mock.ExpectQuery(queryMatch).WithArgs(topicID, userID).
-					WillReturnError(errors.New("user with given id does not exist"))
+			// When the scores table doesn't exist
+			name:   "#2 ERROR",
+			userID: 1,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("table scores does not exist"))
 			},
-			wantScores: nilScores,
-			wantError:  true,
+			wantStats: nil,
+			wantError: true,
 		},
 	}
 
@@ -285,16 +356,16 @@ func TestGetScoresByTopicAndUser(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 
-			test.mock(test.topicID, test.userID)
+			test.mock(test.userID)
 
-			scores, err := store.GetScoresByTopicAndUser(test.topicID, test.userID)
+			stats, err := store.GetTopicStatsByUser(test.userID)
 
 			if (err != nil) != test.wantError {
-				t.Errorf("GetScoresByTopicAndUser() error = %v, want error %v", err, test.wantError)
+				t.Errorf("GetTopicStatsByUser() error = %v, want error %v", err, test.wantError)
 				return
 			}
-			if err == nil && !reflect.DeepEqual(scores, test.wantScores) {
-				t.Errorf("GetScoresByTopicAndUser() = %v, want %v", scores, test.wantScores)
+			if err == nil && !reflect.DeepEqual(stats, test.wantStats) {
+				t.Errorf("GetTopicStatsByUser() = %v, want %v", stats, test.wantStats)
 			}
 		})
 	}
@@ -308,7 +379,7 @@ func TestCountScores(t *testing.T) {
 	store := &ScoreStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT COUNT((.+)) FROM scores"
+	queryMatch := "SELECT COUNT\\(s.score_id\\) FROM scores s (.+) WHERE t.deleted_at IS NULL AND u.deleted_at IS NULL"
 
 	table := []string{"COUNT(*)"}
 
@@ -370,7 +441,7 @@ func TestCountScoresByDate(t *testing.T) {
 	store := &ScoreStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT COUNT((.+)) FROM scores"
+	queryMatch := "SELECT COUNT\\(s.score_id\\) FROM scores s (.+) WHERE s.date BETWEEN \\? AND \\? AND t.deleted_at IS NULL AND u.deleted_at IS NULL"
 
 	table := []string{"COUNT(*)"}
 
@@ -445,6 +516,245 @@ func TestCountScoresByDate(t *testing.T) {
 	}
 }
 
+// TestCountLeaderboard tests getting the amount of scores matching a filter.
+func TestCountLeaderboard(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ScoreStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT COUNT\\(\\*\\) FROM scores s (.+) WHERE (.+)"
+
+	table := []string{"COUNT(*)"}
+
+	// Declare test cases
+	tests := []struct {
+		name            string
+		filter          x.LeaderboardFilter
+		mock            func(filter x.LeaderboardFilter)
+		wantScoresCount int
+		wantError       bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			filter: x.LeaderboardFilter{TopicID: 1, Class: "4a", Limit: 10},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).AddRow(3)
+
+				mock.ExpectQuery(queryMatch).WithArgs(filter.TopicID, filter.Class).WillReturnRows(rows)
+			},
+			wantScoresCount: 3,
+			wantError:       false,
+		},
+		{
+			// When counting the best scores of every user per topic
+			name:   "#2 OK (BEST)",
+			filter: x.LeaderboardFilter{Mode: x.LeaderboardBest},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).AddRow(2)
+
+				mock.ExpectQuery("SELECT COUNT\\(DISTINCT s.user_id, s.topic_id\\) FROM scores s (.+)").
+					WillReturnRows(rows)
+			},
+			wantScoresCount: 2,
+			wantError:       false,
+		},
+		{
+			// When counting the users with a total
+			name:   "#3 OK (TOTAL)",
+			filter: x.LeaderboardFilter{Mode: x.LeaderboardTotal},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).AddRow(1)
+
+				mock.ExpectQuery("SELECT COUNT\\(DISTINCT s.user_id\\) FROM scores s (.+)").
+					WillReturnRows(rows)
+			},
+			wantScoresCount: 1,
+			wantError:       false,
+		},
+		{
+			// When counting the users with a rating in a topic
+			name:   "#4 OK (RATING)",
+			filter: x.LeaderboardFilter{Mode: x.LeaderboardRating, TopicID: 1},
+			mock: func(filter x.LeaderboardFilter) {
+				rows := sqlmock.NewRows(table).AddRow(2)
+
+				mock.ExpectQuery("SELECT COUNT\\(DISTINCT r.user_id\\) FROM ratings r (.+) r.topic_id = \\?").
+					WithArgs(filter.TopicID).WillReturnRows(rows)
+			},
+			wantScoresCount: 2,
+			wantError:       false,
+		},
+		{
+			// When the scores table doesn't exist
+			name:   "#5 ERROR",
+			filter: x.LeaderboardFilter{},
+			mock: func(filter x.LeaderboardFilter) {
+				mock.ExpectQuery(queryMatch).WillReturnError(errors.New("table scores does not exist"))
+			},
+			wantScoresCount: 0,
+			wantError:       true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.filter)
+
+			scoresCount, err := store.CountLeaderboard(test.filter)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CountLeaderboard() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if scoresCount != test.wantScoresCount {
+				t.Errorf("CountLeaderboard() = %v, want %v", scoresCount, test.wantScoresCount)
+			}
+		})
+	}
+}
+
+// TestCountLowerScoresByTopic tests getting the amount of scores of a topic
+// with less points, as well as the total amount of scores of the topic.
+func TestCountLowerScoresByTopic(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ScoreStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) AS lower_count, (.+) AS total_count FROM scores s (.+) WHERE s.topic_id = \\?"
+
+	table := []string{"lower_count", "total_count"}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		points    int
+		mock      func(topicID int, points int)
+		wantLower int
+		wantTotal int
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: 1,
+			points:  55,
+			mock: func(topicID int, points int) {
+				rows := sqlmock.NewRows(table).AddRow(20, 50)
+
+				mock.ExpectQuery(queryMatch).WithArgs(points, topicID).WillReturnRows(rows)
+			},
+			wantLower: 20,
+			wantTotal: 50,
+			wantError: false,
+		},
+		{
+			// When the scores table doesn't exist
+			name:    "#2 ERROR",
+			topicID: 1,
+			points:  55,
+			mock: func(topicID int, points int) {
+				mock.ExpectQuery(queryMatch).WithArgs(points, topicID).
+					WillReturnError(errors.New("table scores does not exist"))
+			},
+			wantLower: 0,
+			wantTotal: 0,
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID, test.points)
+
+			lower, total, err := store.CountLowerScoresByTopic(test.topicID, test.points)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CountLowerScoresByTopic() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if lower != test.wantLower || total != test.wantTotal {
+				t.Errorf("CountLowerScoresByTopic() = %v, %v, want %v, %v",
+					lower, total, test.wantLower, test.wantTotal)
+			}
+		})
+	}
+}
+
+// TestCountTopicsByUser tests getting the amount of different topics played
+// by a user.
+func TestCountTopicsByUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &ScoreStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT COUNT\\(DISTINCT s.topic_id\\) FROM scores s (.+) WHERE s.user_id = \\?"
+
+	table := []string{"COUNT(DISTINCT s.topic_id)"}
+
+	// Declare test cases
+	tests := []struct {
+		name            string
+		userID          int
+		mock            func(userID int)
+		wantTopicsCount int
+		wantError       bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: 1,
+			mock: func(userID int) {
+				rows := sqlmock.NewRows(table).AddRow(2)
+
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(rows)
+			},
+			wantTopicsCount: 2,
+			wantError:       false,
+		},
+		{
+			// When the scores table doesn't exist
+			name:   "#2 ERROR",
+			userID: 1,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("table scores does not exist"))
+			},
+			wantTopicsCount: 0,
+			wantError:       true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			topicsCount, err := store.CountTopicsByUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CountTopicsByUser() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if topicsCount != test.wantTopicsCount {
+				t.Errorf("CountTopicsByUser() = %v, want %v", topicsCount, test.wantTopicsCount)
+			}
+		})
+	}
+}
+
 // TestCreateScore tests creating a new score
 func TestCreateScore(t *testing.T) {
 
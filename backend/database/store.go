@@ -32,6 +32,15 @@ func NewStore(dataSourceName string) (*Store, error) {
 		&UserStore{DB: db},
 		&ScoreStore{DB: db},
 		&TokenStore{DB: db},
+		&AuditStore{DB: db},
+		&RevisionStore{DB: db},
+		&SuggestionStore{DB: db},
+		&TagStore{DB: db},
+		&TranslationStore{DB: db},
+		&RatingStore{DB: db},
+		&AchievementStore{DB: db},
+		&ActivityStore{DB: db},
+		&ChallengeStore{DB: db},
 	}, nil
 }
 
@@ -42,6 +51,15 @@ type Store struct {
 	*UserStore
 	*ScoreStore
 	*TokenStore
+	*AuditStore
+	*RevisionStore
+	*SuggestionStore
+	*TagStore
+	*TranslationStore
+	*RatingStore
+	*AchievementStore
+	*ActivityStore
+	*ChallengeStore
 }
 
 // NewMock creates a new mock sqlx database for testing purposes.
@@ -0,0 +1,260 @@
+// The database store evolving around suggestions, with all necessary methods
+// that access the database.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// SuggestionStore is the MySQL database access object.
+type SuggestionStore struct {
+	*sqlx.DB
+}
+
+// suggestionSelect selects suggestions including the names of their topic,
+// event and user, as well as the current year of the event.
+const suggestionSelect = `
+		SELECT sg.*,
+		       COALESCE(t.name, '') AS topic_name,
+		       COALESCE(e.name, '') AS event_name,
+		       COALESCE(e.year, 0) AS event_year,
+		       COALESCE(u.username, '') AS user_name
+		FROM suggestions sg
+		    LEFT JOIN topics t ON t.topic_id = sg.topic_id
+		    LEFT JOIN events e ON e.event_id = sg.event_id
+		    LEFT JOIN users u ON u.user_id = sg.user_id
+		`
+
+// GetSuggestion gets a suggestion by ID.
+func (store *SuggestionStore) GetSuggestion(suggestionID int) (x.Suggestion, error) {
+	var suggestion x.Suggestion
+
+	query := suggestionSelect + `
+		WHERE sg.suggestion_id = ?
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&suggestion, query, suggestionID); err != nil {
+		return x.Suggestion{}, fmt.Errorf("error getting suggestion: %w", err)
+	}
+
+	return suggestion, nil
+}
+
+// GetSuggestionsByStatus gets all suggestions with a certain status, sorted by
+// date ascending, excluding suggestions of topics in the trash.
+func (store *SuggestionStore) GetSuggestionsByStatus(status string) ([]x.Suggestion, error) {
+	var suggestions []x.Suggestion
+
+	query := suggestionSelect + `
+		WHERE sg.status = ? 
+		  AND t.deleted_at IS NULL
+		ORDER BY sg.date, sg.suggestion_id
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&suggestions, query, status); err != nil {
+		return []x.Suggestion{}, fmt.Errorf("error getting suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// GetSuggestionsByUser gets all suggestions of a certain user, sorted by date
+// descending.
+func (store *SuggestionStore) GetSuggestionsByUser(userID int) ([]x.Suggestion, error) {
+	var suggestions []x.Suggestion
+
+	query := suggestionSelect + `
+		WHERE sg.user_id = ?
+		ORDER BY sg.date DESC, sg.suggestion_id DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&suggestions, query, userID); err != nil {
+		return []x.Suggestion{}, fmt.Errorf("error getting suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// CountSuggestionsByStatus gets amount of suggestions with a certain status,
+// excluding suggestions of topics in the trash.
+func (store *SuggestionStore) CountSuggestionsByStatus(status string) (int, error) {
+	var suggestionsCount int
+
+	query := `
+		SELECT COUNT(*)
+		FROM suggestions sg
+		    JOIN topics t ON t.topic_id = sg.topic_id
+		WHERE sg.status = ? 
+		  AND t.deleted_at IS NULL
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&suggestionsCount, query, status); err != nil {
+		return 0, fmt.Errorf("error getting number of suggestions: %w", err)
+	}
+
+	return suggestionsCount, nil
+}
+
+// CreateSuggestion creates a new suggestion.
+func (store *SuggestionStore) CreateSuggestion(suggestion *x.Suggestion) error {
+
+	query := `
+		INSERT INTO suggestions(topic_id, event_id, user_id, name, year, event_date, comment, status, date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		suggestion.TopicID,
+		suggestion.EventID,
+		suggestion.UserID,
+		suggestion.Name,
+		suggestion.Year,
+		suggestion.EventDate,
+		suggestion.Comment,
+		suggestion.Status,
+		suggestion.Date,
+	); err != nil {
+		return fmt.Errorf("error creating suggestion: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSuggestion updates the status and the reason of an existing
+// suggestion, upon being moderated.
+func (store *SuggestionStore) UpdateSuggestion(suggestion *x.Suggestion) error {
+
+	query := `
+		UPDATE suggestions 
+		SET status = ?, 
+		    reason = ?
+		WHERE suggestion_id = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		suggestion.Status,
+		suggestion.Reason,
+		suggestion.SuggestionID,
+	); err != nil {
+		return fmt.Errorf("error updating suggestion: %w", err)
+	}
+
+	return nil
+}
+
+// AcceptSuggestion marks a pending suggestion as accepted and applies it to an
+// event in a single transaction, so that a suggestion can't be accepted twice.
+// An event with an ID of 0 gets created and its ID gets set, otherwise the
+// event gets updated. If the suggestion isn't pending anymore, nothing gets
+// applied and sql.ErrNoRows gets returned.
+func (store *SuggestionStore) AcceptSuggestion(suggestion *x.Suggestion, event *x.Event) error {
+
+	query := `
+		UPDATE suggestions 
+		SET status = ?, 
+		    reason = ?
+		WHERE suggestion_id = ?
+		  AND status = 'pending'
+		`
+
+	queryCreate := `
+		INSERT INTO events(topic_id, name, year, date, description, source, image) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`
+
+	queryUpdate := `
+		UPDATE events 
+		SET name = ?, 
+		    year = ?,
+		    date = ?,
+		    description = ?,
+		    source = ?,
+		    image = ?
+		WHERE event_id = ?
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statement to mark the suggestion, unless it has
+	// already been moderated
+	result, err := tx.Exec(query,
+		suggestion.Status,
+		suggestion.Reason,
+		suggestion.SuggestionID,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating suggestion: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating suggestion: %w", err)
+	}
+	if affected == 0 {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating suggestion: %w", sql.ErrNoRows)
+	}
+
+	if event.EventID == 0 {
+		// Execute prepared statement to create the event
+		result, err = tx.Exec(queryCreate,
+			event.TopicID,
+			event.Name,
+			event.Year,
+			event.Date,
+			event.Description,
+			event.Source,
+			event.Image,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating event: %w", err)
+		}
+
+		// Set ID of newly created event
+		eventID, err := result.LastInsertId()
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error getting ID of event: %w", err)
+		}
+		event.EventID = int(eventID)
+	} else {
+		// Execute prepared statement to update the event
+		if _, err = tx.Exec(queryUpdate,
+			event.Name,
+			event.Year,
+			event.Date,
+			event.Description,
+			event.Source,
+			event.Image,
+			event.EventID,
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error updating event: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
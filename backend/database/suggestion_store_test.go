@@ -0,0 +1,557 @@
+// Collection of tests for the database access layer of functions evolving
+// around suggestions.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tSuggestion is a mock suggestion for testing purposes
+	tSuggestion = x.Suggestion{
+		SuggestionID: 1,
+		TopicID:      1,
+		EventID:      1,
+		UserID:       1,
+		Year:         1850,
+		EventDate:    time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC),
+		Comment:      "Siehe Schulbuch",
+		Status:       "pending",
+		Date:         time.Now(),
+		TopicName:    "Topic 1",
+		EventName:    "Event 1",
+		EventYear:    1800,
+		UserName:     "user_1",
+	}
+
+	// tSuggestion2 is a mock suggestion for testing purposes
+	tSuggestion2 = x.Suggestion{
+		SuggestionID: 2,
+		TopicID:      1,
+		UserID:       2,
+		Name:         "Event 2",
+		Year:         1900,
+		EventDate:    time.Date(1900, 5, 1, 0, 0, 0, 0, time.UTC),
+		Status:       "pending",
+		Date:         time.Now().Add(time.Hour * 1),
+		TopicName:    "Topic 1",
+		UserName:     "user_2",
+	}
+
+	// tSuggestionTable contains the columns of a suggestion
+	tSuggestionTable = []string{"suggestion_id", "topic_id", "event_id", "user_id", "name", "year", "event_date",
+		"comment", "status", "reason", "date", "topic_name", "event_name", "event_year", "user_name"}
+
+	// nilSuggestions is a nil slice of suggestions
+	nilSuggestions []x.Suggestion
+)
+
+// tSuggestionRows converts mock suggestions to mock rows.
+func tSuggestionRows(suggestions ...x.Suggestion) *sqlmock.Rows {
+	rows := sqlmock.NewRows(tSuggestionTable)
+	for _, s := range suggestions {
+		rows = rows.AddRow(s.SuggestionID, s.TopicID, s.EventID, s.UserID, s.Name, s.Year, s.EventDate,
+			s.Comment, s.Status, s.Reason, s.Date, s.TopicName, s.EventName, s.EventYear, s.UserName)
+	}
+	return rows
+}
+
+// TestGetSuggestion tests getting a suggestion by ID.
+func TestGetSuggestion(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM suggestions sg (.+) WHERE sg.suggestion_id = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name           string
+		suggestionID   int
+		mock           func(suggestionID int)
+		wantSuggestion x.Suggestion
+		wantError      bool
+	}{
+		{
+			// When everything works as intended
+			name:         "#1 OK",
+			suggestionID: tSuggestion.SuggestionID,
+			mock: func(suggestionID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(suggestionID).WillReturnRows(tSuggestionRows(tSuggestion))
+			},
+			wantSuggestion: tSuggestion,
+			wantError:      false,
+		},
+		{
+			// When suggestion with given suggestion ID doesn't exist
+			name:         "#2 NOT FOUND",
+			suggestionID: 0,
+			mock: func(suggestionID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(suggestionID).WillReturnRows(tSuggestionRows())
+			},
+			wantSuggestion: x.Suggestion{},
+			wantError:      true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.suggestionID)
+
+			suggestion, err := store.GetSuggestion(test.suggestionID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetSuggestion() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(suggestion, test.wantSuggestion) {
+				t.Errorf("GetSuggestion() = %v, want %v", suggestion, test.wantSuggestion)
+			}
+		})
+	}
+}
+
+// TestGetSuggestionsByStatus tests getting all suggestions with a certain
+// status.
+func TestGetSuggestionsByStatus(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM suggestions sg (.+) WHERE sg.status = (.+) ORDER BY"
+
+	// Declare test cases
+	tests := []struct {
+		name            string
+		status          string
+		mock            func(status string)
+		wantSuggestions []x.Suggestion
+		wantError       bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			status: "pending",
+			mock: func(status string) {
+				mock.ExpectQuery(queryMatch).WithArgs(status).
+					WillReturnRows(tSuggestionRows(tSuggestion, tSuggestion2))
+			},
+			wantSuggestions: []x.Suggestion{tSuggestion, tSuggestion2},
+			wantError:       false,
+		},
+		{
+			// When there are no suggestions with given status
+			name:   "#2 OK (NO ROWS)",
+			status: "rejected",
+			mock: func(status string) {
+				mock.ExpectQuery(queryMatch).WithArgs(status).WillReturnRows(tSuggestionRows())
+			},
+			wantSuggestions: nilSuggestions,
+			wantError:       false,
+		},
+		{
+			// When the suggestions table doesn't exist
+			name:   "#3 ERROR",
+			status: "pending",
+			mock: func(status string) {
+				mock.ExpectQuery(queryMatch).WithArgs(status).
+					WillReturnError(errors.New("table suggestions does not exist"))
+			},
+			wantSuggestions: nil,
+			wantError:       true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.status)
+
+			suggestions, err := store.GetSuggestionsByStatus(test.status)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetSuggestionsByStatus() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(suggestions, test.wantSuggestions) {
+				t.Errorf("GetSuggestionsByStatus() = %v, want %v", suggestions, test.wantSuggestions)
+			}
+		})
+	}
+}
+
+// TestGetSuggestionsByUser tests getting all suggestions of a certain user.
+func TestGetSuggestionsByUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM suggestions sg (.+) WHERE sg.user_id = (.+) ORDER BY"
+
+	// Declare test cases
+	tests := []struct {
+		name            string
+		userID          int
+		mock            func(userID int)
+		wantSuggestions []x.Suggestion
+		wantError       bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: tSuggestion.UserID,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(tSuggestionRows(tSuggestion))
+			},
+			wantSuggestions: []x.Suggestion{tSuggestion},
+			wantError:       false,
+		},
+		{
+			// When user hasn't suggested anything
+			name:   "#2 OK (NO ROWS)",
+			userID: 3,
+			mock: func(userID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(userID).WillReturnRows(tSuggestionRows())
+			},
+			wantSuggestions: nilSuggestions,
+			wantError:       false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			suggestions, err := store.GetSuggestionsByUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetSuggestionsByUser() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(suggestions, test.wantSuggestions) {
+				t.Errorf("GetSuggestionsByUser() = %v, want %v", suggestions, test.wantSuggestions)
+			}
+		})
+	}
+}
+
+// TestCountSuggestionsByStatus tests getting amount of suggestions with a
+// certain status.
+func TestCountSuggestionsByStatus(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT COUNT((.+)) FROM suggestions sg (.+) WHERE sg.status = (.+)"
+
+	table := []string{"COUNT(*)"}
+
+	// Declare test cases
+	tests := []struct {
+		name                 string
+		status               string
+		mock                 func(status string)
+		wantSuggestionsCount int
+		wantError            bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			status: "pending",
+			mock: func(status string) {
+				rows := sqlmock.NewRows(table).AddRow(2)
+
+				mock.ExpectQuery(queryMatch).WithArgs(status).WillReturnRows(rows)
+			},
+			wantSuggestionsCount: 2,
+			wantError:            false,
+		},
+		{
+			// When the suggestions table doesn't exist
+			name:   "#2 ERROR",
+			status: "pending",
+			mock: func(status string) {
+				mock.ExpectQuery(queryMatch).WithArgs(status).
+					WillReturnError(errors.New("table suggestions does not exist"))
+			},
+			wantSuggestionsCount: 0,
+			wantError:            true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.status)
+
+			suggestionsCount, err := store.CountSuggestionsByStatus(test.status)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CountSuggestionsByStatus() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && suggestionsCount != test.wantSuggestionsCount {
+				t.Errorf("CountSuggestionsByStatus() = %v, want %v", suggestionsCount, test.wantSuggestionsCount)
+			}
+		})
+	}
+}
+
+// TestCreateSuggestion tests creating a new suggestion.
+func TestCreateSuggestion(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO suggestions"
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		suggestion x.Suggestion
+		mock       func(suggestion x.Suggestion)
+		wantError  bool
+	}{
+		{
+			// When everything works as intended
+			name:       "#1 OK",
+			suggestion: tSuggestion2,
+			mock: func(s x.Suggestion) {
+				mock.ExpectExec(queryMatch).WithArgs(s.TopicID, s.EventID, s.UserID, s.Name, s.Year,
+					s.EventDate, s.Comment, s.Status, s.Date).
+					WillReturnResult(sqlmock.NewResult(int64(s.SuggestionID), 1))
+			},
+			wantError: false,
+		},
+		{
+			// When topic doesn't exist
+			name: "#2 TOPIC NOT FOUND",
+			suggestion: x.Suggestion{
+				TopicID: 0,
+				UserID:  tSuggestion.UserID,
+				Status:  "pending",
+			},
+			mock: func(s x.Suggestion) {
+				mock.ExpectExec(queryMatch).WithArgs(s.TopicID, s.EventID, s.UserID, s.Name, s.Year,
+					s.EventDate, s.Comment, s.Status, s.Date).
+					WillReturnError(errors.New("foreign key constraint fails"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.suggestion)
+
+			err := store.CreateSuggestion(&test.suggestion)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateSuggestion() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestUpdateSuggestion tests updating the status and reason of a suggestion.
+func TestUpdateSuggestion(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE suggestions SET status = (.+), reason = (.+) WHERE suggestion_id = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		suggestion x.Suggestion
+		mock       func(suggestion x.Suggestion)
+		wantError  bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			suggestion: x.Suggestion{
+				SuggestionID: tSuggestion.SuggestionID,
+				Status:       "rejected",
+				Reason:       "Das Jahr stimmt bereits.",
+			},
+			mock: func(s x.Suggestion) {
+				mock.ExpectExec(queryMatch).WithArgs(s.Status, s.Reason, s.SuggestionID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When the suggestions table doesn't exist
+			name:       "#2 ERROR",
+			suggestion: tSuggestion,
+			mock: func(s x.Suggestion) {
+				mock.ExpectExec(queryMatch).WithArgs(s.Status, s.Reason, s.SuggestionID).
+					WillReturnError(errors.New("table suggestions does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.suggestion)
+
+			err := store.UpdateSuggestion(&test.suggestion)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("UpdateSuggestion() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestAcceptSuggestion tests accepting a suggestion and applying it to an
+// event in a single transaction.
+func TestAcceptSuggestion(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &SuggestionStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE suggestions SET status = (.+), reason = (.+) WHERE suggestion_id = (.+) AND status = 'pending'"
+	queryMatchCreate := "INSERT INTO events"
+	queryMatchUpdate := "UPDATE events SET (.+) WHERE event_id = (.+)"
+
+	tEvent := x.Event{TopicID: tSuggestion.TopicID, Name: "Mauerfall", Year: 1989,
+		Date: time.Date(1989, 11, 9, 0, 0, 0, 0, time.UTC)}
+	tEvent2 := tEvent
+	tEvent2.EventID = 5
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		event       x.Event
+		mock        func(s x.Suggestion, event x.Event)
+		wantEventID int
+		wantError   bool
+		wantNoRows  bool
+	}{
+		{
+			// When a new event gets created
+			name:  "#1 OK (CREATE)",
+			event: tEvent,
+			mock: func(s x.Suggestion, event x.Event) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WithArgs(s.Status, s.Reason, s.SuggestionID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(queryMatchCreate).WithArgs(event.TopicID, event.Name, event.Year, event.Date,
+					event.Description, event.Source, event.Image).
+					WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectCommit()
+			},
+			wantEventID: 9,
+			wantError:   false,
+		},
+		{
+			// When an existing event gets corrected
+			name:  "#2 OK (UPDATE)",
+			event: tEvent2,
+			mock: func(s x.Suggestion, event x.Event) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WithArgs(s.Status, s.Reason, s.SuggestionID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(queryMatchUpdate).WithArgs(event.Name, event.Year, event.Date, event.Description,
+					event.Source, event.Image, event.EventID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantEventID: 5,
+			wantError:   false,
+		},
+		{
+			// When the suggestion has already been moderated, so that the
+			// event doesn't get touched
+			name:  "#3 NOT PENDING",
+			event: tEvent,
+			mock: func(s x.Suggestion, event x.Event) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WithArgs(s.Status, s.Reason, s.SuggestionID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantEventID: 0,
+			wantError:   true,
+			wantNoRows:  true,
+		},
+		{
+			// When the event can't be created, which rolls back the status of
+			// the suggestion as well
+			name:  "#4 ROLLBACK",
+			event: tEvent,
+			mock: func(s x.Suggestion, event x.Event) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WithArgs(s.Status, s.Reason, s.SuggestionID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(queryMatchCreate).WillReturnError(errors.New("name can not be empty"))
+				mock.ExpectRollback()
+			},
+			wantEventID: 0,
+			wantError:   true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			suggestion := tSuggestion
+			suggestion.Status = "accepted"
+			test.mock(suggestion, test.event)
+
+			err := store.AcceptSuggestion(&suggestion, &test.event)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("AcceptSuggestion() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) != test.wantNoRows {
+				t.Errorf("AcceptSuggestion() error = %v, want sql.ErrNoRows %v", err, test.wantNoRows)
+			}
+			if test.event.EventID != test.wantEventID {
+				t.Errorf("AcceptSuggestion() event ID = %v, want %v", test.event.EventID, test.wantEventID)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("AcceptSuggestion() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// The database store evolving around tags of topics, with all necessary
+// methods that access the database.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// TagStore is the MySQL database access object.
+type TagStore struct {
+	*sqlx.DB
+}
+
+// GetTags gets all tags, sorted by name, including the amount of topics (not
+// in the trash) per tag.
+func (store *TagStore) GetTags() ([]x.Tag, error) {
+	var tags []x.Tag
+
+	query := `
+		SELECT tg.*, 
+		       COUNT(DISTINCT t.topic_id) AS topics_count
+		FROM tags tg
+		    LEFT JOIN topic_tags tt ON tt.tag_id = tg.tag_id
+		    LEFT JOIN topics t ON t.topic_id = tt.topic_id AND t.deleted_at IS NULL
+		GROUP BY tg.tag_id
+		ORDER BY tg.name
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&tags, query); err != nil {
+		return []x.Tag{}, fmt.Errorf("error getting tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetTagsByTopic gets all tags of a topic, sorted by name.
+func (store *TagStore) GetTagsByTopic(topicID int) ([]x.Tag, error) {
+	var tags []x.Tag
+
+	query := `
+		SELECT tg.* 
+		FROM tags tg
+		    JOIN topic_tags tt ON tt.tag_id = tg.tag_id
+		WHERE tt.topic_id = ?
+		ORDER BY tg.name
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&tags, query, topicID); err != nil {
+		return []x.Tag{}, fmt.Errorf("error getting tags of topic: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetTagsOfTopics gets the tags of all topics, sorted by name, mapped by the
+// ID of the topic.
+func (store *TagStore) GetTagsOfTopics() (map[int][]x.Tag, error) {
+	var rows []struct {
+		TopicID int `db:"topic_id"`
+		x.Tag
+	}
+
+	query := `
+		SELECT tt.topic_id, tg.* 
+		FROM topic_tags tt
+		    JOIN tags tg ON tg.tag_id = tt.tag_id
+		ORDER BY tg.name
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&rows, query); err != nil {
+		return map[int][]x.Tag{}, fmt.Errorf("error getting tags of topics: %w", err)
+	}
+
+	tags := make(map[int][]x.Tag)
+	for _, row := range rows {
+		tags[row.TopicID] = append(tags[row.TopicID], row.Tag)
+	}
+
+	return tags, nil
+}
+
+// UpdateTopicTags replaces the tags of a topic, creating tags that don't exist
+// yet and deleting tags that no longer belong to any topic, within a single
+// transaction.
+func (store *TagStore) UpdateTopicTags(topicID int, names []string) error {
+
+	queryDelete := `
+		DELETE FROM topic_tags 
+		WHERE topic_id = ?
+		`
+
+	// Creating an existing tag updates nothing, but returns its ID
+	queryTag := `
+		INSERT INTO tags(name) 
+		VALUES (?)
+		ON DUPLICATE KEY UPDATE tag_id = LAST_INSERT_ID(tag_id)
+		`
+
+	queryTopicTag := `
+		INSERT INTO topic_tags(topic_id, tag_id) 
+		VALUES (?, ?)
+		`
+
+	queryUnused := `
+		DELETE FROM tags 
+		WHERE tag_id NOT IN (SELECT tag_id FROM topic_tags)
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statement to remove the previous tags
+	if _, err = tx.Exec(queryDelete, topicID); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error deleting tags of topic: %w", err)
+	}
+
+	// Execute prepared statements for each tag
+	for _, name := range names {
+		result, err := tx.Exec(queryTag, name)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating tag: %w", err)
+		}
+
+		// Retrieve ID of the new or existing tag
+		tagID, err := result.LastInsertId()
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error getting id of tag: %w", err)
+		}
+
+		if _, err = tx.Exec(queryTopicTag, topicID, tagID); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error adding tag to topic: %w", err)
+		}
+	}
+
+	// Execute prepared statement to delete unused tags
+	if _, err = tx.Exec(queryUnused); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error deleting unused tags: %w", err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
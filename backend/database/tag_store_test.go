@@ -0,0 +1,321 @@
+// Collection of tests for the database access layer of functions evolving
+// around tags of topics.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tTag is a mock tag for testing purposes
+	tTag = x.Tag{
+		TagID:       1,
+		Name:        "Antike",
+		TopicsCount: 2,
+	}
+
+	// tTag2 is a mock tag for testing purposes
+	tTag2 = x.Tag{
+		TagID:       2,
+		Name:        "Schweizer Geschichte",
+		TopicsCount: 1,
+	}
+
+	// nilTags is a nil slice of tags
+	nilTags []x.Tag
+)
+
+// TestGetTags tests getting all tags.
+func TestGetTags(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TagStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM tags tg (.+) ORDER BY tg.name"
+
+	table := []string{"tag_id", "name", "topics_count"}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		mock      func()
+		wantTags  []x.Tag
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(tTag.TagID, tTag.Name, tTag.TopicsCount).
+					AddRow(tTag2.TagID, tTag2.Name, tTag2.TopicsCount)
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			},
+			wantTags:  []x.Tag{tTag, tTag2},
+			wantError: false,
+		},
+		{
+			// When there are no tags
+			name: "#2 OK (NO ROWS)",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WillReturnRows(sqlmock.NewRows(table))
+			},
+			wantTags:  nilTags,
+			wantError: false,
+		},
+		{
+			// When the tags table doesn't exist
+			name: "#3 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WillReturnError(errors.New("table tags does not exist"))
+			},
+			wantTags:  nil,
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			tags, err := store.GetTags()
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetTags() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(tags, test.wantTags) {
+				t.Errorf("GetTags() = %v, want %v", tags, test.wantTags)
+			}
+		})
+	}
+}
+
+// TestGetTagsByTopic tests getting all tags of a topic.
+func TestGetTagsByTopic(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TagStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM tags tg (.+) WHERE tt.topic_id = (.+) ORDER BY tg.name"
+
+	table := []string{"tag_id", "name"}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		mock      func(topicID int)
+		wantTags  []x.Tag
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: 1,
+			mock: func(topicID int) {
+				rows := sqlmock.NewRows(table).AddRow(tTag.TagID, tTag.Name)
+
+				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(rows)
+			},
+			wantTags:  []x.Tag{{TagID: tTag.TagID, Name: tTag.Name}},
+			wantError: false,
+		},
+		{
+			// When topic has no tags
+			name:    "#2 OK (NO ROWS)",
+			topicID: 2,
+			mock: func(topicID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(sqlmock.NewRows(table))
+			},
+			wantTags:  nilTags,
+			wantError: false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID)
+
+			tags, err := store.GetTagsByTopic(test.topicID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetTagsByTopic() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(tags, test.wantTags) {
+				t.Errorf("GetTagsByTopic() = %v, want %v", tags, test.wantTags)
+			}
+		})
+	}
+}
+
+// TestGetTagsOfTopics tests getting the tags of all topics.
+func TestGetTagsOfTopics(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TagStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM topic_tags tt (.+) ORDER BY tg.name"
+
+	table := []string{"topic_id", "tag_id", "name"}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		mock      func()
+		wantTags  map[int][]x.Tag
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(1, tTag.TagID, tTag.Name).
+					AddRow(2, tTag.TagID, tTag.Name).
+					AddRow(1, tTag2.TagID, tTag2.Name)
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			},
+			wantTags: map[int][]x.Tag{
+				1: {{TagID: tTag.TagID, Name: tTag.Name}, {TagID: tTag2.TagID, Name: tTag2.Name}},
+				2: {{TagID: tTag.TagID, Name: tTag.Name}},
+			},
+			wantError: false,
+		},
+		{
+			// When the tags table doesn't exist
+			name: "#2 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WillReturnError(errors.New("table tags does not exist"))
+			},
+			wantTags:  nil,
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			tags, err := store.GetTagsOfTopics()
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetTagsOfTopics() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(tags, test.wantTags) {
+				t.Errorf("GetTagsOfTopics() = %v, want %v", tags, test.wantTags)
+			}
+		})
+	}
+}
+
+// TestUpdateTopicTags tests replacing the tags of a topic.
+func TestUpdateTopicTags(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TagStore{DB: db}
+	defer db.Close()
+
+	queryMatchDelete := "DELETE FROM topic_tags WHERE topic_id = (.+)"
+	queryMatchTag := "INSERT INTO tags"
+	queryMatchTopicTag := "INSERT INTO topic_tags"
+	queryMatchUnused := "DELETE FROM tags WHERE tag_id NOT IN"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		names     []string
+		mock      func(topicID int, names []string)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: 1,
+			names:   []string{tTag.Name, tTag2.Name},
+			mock: func(topicID int, names []string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatchDelete).WithArgs(topicID).WillReturnResult(sqlmock.NewResult(0, 1))
+				for i, name := range names {
+					mock.ExpectExec(queryMatchTag).WithArgs(name).WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+					mock.ExpectExec(queryMatchTopicTag).WithArgs(topicID, int64(i+1)).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectExec(queryMatchUnused).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			wantError: false,
+		},
+		{
+			// When removing all tags
+			name:    "#2 OK (NO TAGS)",
+			topicID: 1,
+			names:   nil,
+			mock: func(topicID int, names []string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatchDelete).WithArgs(topicID).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(queryMatchUnused).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantError: false,
+		},
+		{
+			// When topic doesn't exist, which rolls back the removal of the
+			// previous tags
+			name:    "#3 TOPIC NOT FOUND",
+			topicID: 0,
+			names:   []string{tTag.Name},
+			mock: func(topicID int, names []string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatchDelete).WithArgs(topicID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(queryMatchTag).WithArgs(names[0]).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(queryMatchTopicTag).WithArgs(topicID, int64(1)).
+					WillReturnError(errors.New("foreign key constraint fails"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID, test.names)
+
+			err := store.UpdateTopicTags(test.topicID, test.names)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("UpdateTopicTags() error = %v, want error %v", err, test.wantError)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("UpdateTopicTags() %v", err)
+			}
+		})
+	}
+}
@@ -1,5 +1,8 @@
 // The database store evolving around topics, with all necessary methods that
 // access the database.
+//
+// Deleting a topic moves it to the trash, from where it can be restored until
+// it gets purged. Topics in the trash are excluded from all other queries.
 
 package database
 
@@ -16,7 +19,9 @@ type TopicStore struct {
 	*sqlx.DB
 }
 
-// GetTopic gets a topic and its events by ID.
+// GetTopic gets a topic and its events by ID. Grouping by the topic makes
+// sure that a missing topic or a topic in the trash results in
+// sql.ErrNoRows, rather than a row of NULLs of the aggregate functions.
 func (store *TopicStore) GetTopic(topicID int) (x.Topic, error) {
 	var topic x.Topic
 
@@ -26,8 +31,10 @@ func (store *TopicStore) GetTopic(topicID int) (x.Topic, error) {
 		       COUNT(DISTINCT e.event_id) AS events_count
 		FROM topics t 
 			LEFT JOIN scores s ON s.topic_id = t.topic_id 
-		    LEFT JOIN events e on t.topic_id = e.topic_id
-		WHERE t.topic_id = ?
+		    LEFT JOIN events e on t.topic_id = e.topic_id AND e.deleted_at IS NULL
+		WHERE t.topic_id = ? 
+		  AND t.deleted_at IS NULL
+		GROUP BY t.topic_id
 		`
 
 	// Execute prepared statement
@@ -39,6 +46,7 @@ func (store *TopicStore) GetTopic(topicID int) (x.Topic, error) {
 		SELECT * 
 		FROM events 
 		WHERE topic_id = ? 
+		  AND deleted_at IS NULL
 		ORDER BY date
 		`
 
@@ -60,7 +68,8 @@ func (store *TopicStore) GetTopics() ([]x.Topic, error) {
 		       COUNT(DISTINCT e.event_id) AS events_count
 		FROM topics t 
 			LEFT JOIN scores s ON s.topic_id = t.topic_id 
-		    LEFT JOIN events e on t.topic_id = e.topic_id
+		    LEFT JOIN events e on t.topic_id = e.topic_id AND e.deleted_at IS NULL
+		WHERE t.deleted_at IS NULL
 		GROUP BY t.topic_id, t.start_year 
 		ORDER BY t.start_year
 		`
@@ -73,25 +82,101 @@ func (store *TopicStore) GetTopics() ([]x.Topic, error) {
 	return topics, nil
 }
 
-// CreateTopic creates a new topic.
+// CreateTopic creates a new topic and sets the ID of the newly created topic.
 func (store *TopicStore) CreateTopic(topic *x.Topic) error {
 
 	query := `
-		INSERT INTO topics(name, start_year, end_year, description, image) 
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO topics(parent_id, name, start_year, end_year, description, image) 
+		VALUES (?, ?, ?, ?, ?, ?)
 		`
 
 	// Execute prepared statement
-	if _, err := store.Exec(query,
+	result, err := store.Exec(query,
+		topic.ParentID,
 		topic.Name,
 		topic.StartYear,
 		topic.EndYear,
 		topic.Description,
 		topic.Image,
-	); err != nil {
+	)
+	if err != nil {
 		return fmt.Errorf("error creating topic: %w", err)
 	}
 
+	// Set ID of newly created topic
+	topicID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting ID of topic: %w", err)
+	}
+	topic.TopicID = int(topicID)
+
+	return nil
+}
+
+// CreateTopicWithEvents creates a new topic including all of its events in a
+// single transaction and sets the ID of the newly created topic.
+func (store *TopicStore) CreateTopicWithEvents(topic *x.Topic) error {
+
+	query := `
+		INSERT INTO topics(parent_id, name, start_year, end_year, description, image) 
+		VALUES (?, ?, ?, ?, ?, ?)
+		`
+
+	queryEvents := `
+		INSERT INTO events(topic_id, name, year, date, description, source, image) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`
+
+	// Begin transaction
+	tx, err := store.Beginx()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	// Execute prepared statement
+	result, err := tx.Exec(query,
+		topic.ParentID,
+		topic.Name,
+		topic.StartYear,
+		topic.EndYear,
+		topic.Description,
+		topic.Image,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error creating topic: %w", err)
+	}
+
+	// Retrieve ID of the new topic
+	topicID, err := result.LastInsertId()
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error getting id of topic: %w", err)
+	}
+	topic.TopicID = int(topicID)
+
+	// Execute prepared statement for each event
+	for i := range topic.Events {
+		topic.Events[i].TopicID = topic.TopicID
+		if _, err = tx.Exec(queryEvents,
+			topic.Events[i].TopicID,
+			topic.Events[i].Name,
+			topic.Events[i].Year,
+			topic.Events[i].Date,
+			topic.Events[i].Description,
+			topic.Events[i].Source,
+			topic.Events[i].Image,
+		); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error creating events of topic: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -123,14 +208,33 @@ func (store *TopicStore) UpdateTopic(topic *x.Topic) error {
 	return nil
 }
 
-// DeleteTopic deletes an existing topic.
-func (store *TopicStore) DeleteTopic(topicID int) error {
+// MoveTopic sets the parent topic of a topic, 0 making it a top-level topic.
+func (store *TopicStore) MoveTopic(topicID int, parentID int) error {
 
 	query := `
-		DELETE FROM topics 
+		UPDATE topics 
+		SET parent_id = ? 
 		WHERE topic_id = ?
 		`
 
+	// Execute prepared statement
+	if _, err := store.Exec(query, parentID, topicID); err != nil {
+		return fmt.Errorf("error moving topic: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTopic moves an existing topic to the trash.
+func (store *TopicStore) DeleteTopic(topicID int) error {
+
+	query := `
+		UPDATE topics 
+		SET deleted_at = CURRENT_TIMESTAMP 
+		WHERE topic_id = ? 
+		  AND deleted_at IS NULL
+		`
+
 	// Execute prepared statement
 	if _, err := store.Exec(query, topicID); err != nil {
 		return fmt.Errorf("error deleting topic: %w", err)
@@ -138,3 +242,77 @@ func (store *TopicStore) DeleteTopic(topicID int) error {
 
 	return nil
 }
+
+// GetDeletedTopics gets all topics in the trash including all of their events,
+// sorted by date of deletion descending.
+func (store *TopicStore) GetDeletedTopics() ([]x.Topic, error) {
+	var topics []x.Topic
+
+	query := `
+		SELECT t.*, 
+		       COUNT(DISTINCT s.score_id) AS scores_count,
+		       COUNT(DISTINCT e.event_id) AS events_count
+		FROM topics t 
+			LEFT JOIN scores s ON s.topic_id = t.topic_id 
+		    LEFT JOIN events e on t.topic_id = e.topic_id
+		WHERE t.deleted_at IS NOT NULL
+		GROUP BY t.topic_id, t.deleted_at 
+		ORDER BY t.deleted_at DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&topics, query); err != nil {
+		return []x.Topic{}, fmt.Errorf("error getting deleted topics: %w", err)
+	}
+
+	query = `
+		SELECT * 
+		FROM events 
+		WHERE topic_id = ? 
+		ORDER BY date
+		`
+
+	// Execute prepared statement for each topic, since their events are
+	// needed when purging them (e.g. for deleting uploaded images)
+	for i := range topics {
+		if err := store.Select(&topics[i].Events, query, topics[i].TopicID); err != nil {
+			return []x.Topic{}, fmt.Errorf("error getting events of deleted topic: %w", err)
+		}
+	}
+
+	return topics, nil
+}
+
+// RestoreTopic restores a topic from the trash.
+func (store *TopicStore) RestoreTopic(topicID int) error {
+
+	query := `
+		UPDATE topics 
+		SET deleted_at = NULL 
+		WHERE topic_id = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, topicID); err != nil {
+		return fmt.Errorf("error restoring topic: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeTopic permanently deletes a topic in the trash.
+func (store *TopicStore) PurgeTopic(topicID int) error {
+
+	query := `
+		DELETE FROM topics 
+		WHERE topic_id = ? 
+		  AND deleted_at IS NOT NULL
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, topicID); err != nil {
+		return fmt.Errorf("error purging topic: %w", err)
+	}
+
+	return nil
+}
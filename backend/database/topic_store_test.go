@@ -4,6 +4,7 @@
 package database
 
 import (
+	"database/sql"
 	"errors"
 	"reflect"
 	"testing"
@@ -75,20 +76,21 @@ func TestGetTopic(t *testing.T) {
 	store := &TopicStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "SELECT (.+) FROM topics"
+	queryMatch := "SELECT (.+) FROM topics (.+) WHERE t.topic_id = \\? AND t.deleted_at IS NULL GROUP BY t.topic_id"
 	queryMatchEvents := "SELECT (.+) FROM events"
 
 	table := []string{"topic_id", "name", "start_year", "end_year", "description", "image", "scores_count",
 		"events_count"}
-	tableEvents := []string{"event_id", "topic_id", "name", "year", "date"}
+	tableEvents := []string{"event_id", "topic_id", "name", "year", "date", "description", "source", "image"}
 
 	// Declare test cases
 	tests := []struct {
-		name      string
-		topicID   int
-		mock      func(topicID int)
-		wantTopic x.Topic
-		wantError bool
+		name       string
+		topicID    int
+		mock       func(topicID int)
+		wantTopic  x.Topic
+		wantError  bool
+		wantNoRows bool
 	}{
 		{
 			// When everything works as expected
@@ -103,7 +105,8 @@ func TestGetTopic(t *testing.T) {
 
 				rowsEvents := sqlmock.NewRows(tableEvents)
 				for _, event := range tTopic.Events {
-					rowsEvents = rowsEvents.AddRow(event.EventID, event.TopicID, event.Name, event.Year, event.Date)
+					rowsEvents = rowsEvents.AddRow(event.EventID, event.TopicID, event.Name, event.Year, event.Date,
+						event.Description, event.Source, event.Image)
 				}
 				mock.ExpectQuery(queryMatchEvents).WithArgs(topicID).WillReturnRows(rowsEvents)
 			},
@@ -111,19 +114,32 @@ func TestGetTopic(t *testing.T) {
 			wantError: false,
 		},
 		{
-			// When topic with given topic ID doesn't exist
+			// When topic with given topic ID doesn't exist, which results in
+			// no row at all, since the query is grouped by the topic
 			name:    "#2 NOT FOUND",
 			topicID: 0,
 			mock: func(topicID int) {
 				rows := sqlmock.NewRows(table)
 
 				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(rows)
+			},
+			wantTopic:  x.Topic{},
+			wantError:  true,
+			wantNoRows: true,
+		},
+		{
+			// When topic with given topic ID is in the trash, which is
+			// excluded by the query
+			name:    "#3 IN TRASH",
+			topicID: tTopic.TopicID,
+			mock: func(topicID int) {
+				rows := sqlmock.NewRows(table)
 
-				rowsEvents := sqlmock.NewRows(tableEvents)
-				mock.ExpectQuery(queryMatchEvents).WithArgs(topicID).WillReturnRows(rowsEvents)
+				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(rows)
 			},
-			wantTopic: x.Topic{},
-			wantError: true,
+			wantTopic:  x.Topic{},
+			wantError:  true,
+			wantNoRows: true,
 		},
 	}
 
@@ -139,6 +155,9 @@ func TestGetTopic(t *testing.T) {
 				t.Errorf("GetTopic() error = %v, want error %v", err, test.wantError)
 				return
 			}
+			if errors.Is(err, sql.ErrNoRows) != test.wantNoRows {
+				t.Errorf("GetTopic() error = %v, want sql.ErrNoRows %v", err, test.wantNoRows)
+			}
 			if err == nil && !reflect.DeepEqual(topic, test.wantTopic) {
 				t.Errorf("GetTopic() = %v, want %v", topic, test.wantTopic)
 			}
@@ -235,8 +254,8 @@ func TestCreateTopic(t *testing.T) {
 			name:  "#1 OK",
 			topic: tTopic,
 			mock: func(topic x.Topic) {
-				mock.ExpectExec(queryMatch).WithArgs(topic.Name, topic.StartYear, topic.EndYear, topic.Description,
-					topic.Image).
+				mock.ExpectExec(queryMatch).WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear,
+					topic.Description, topic.Image).
 					WillReturnResult(sqlmock.NewResult(int64(topic.TopicID), 1))
 			},
 			wantError: false,
@@ -251,8 +270,8 @@ func TestCreateTopic(t *testing.T) {
 				Image:       tTopic.Image,
 			},
 			mock: func(topic x.Topic) {
-				mock.ExpectExec(queryMatch).WithArgs(topic.Name, topic.StartYear, topic.EndYear, topic.Description,
-					topic.Image).
+				mock.ExpectExec(queryMatch).WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear,
+					topic.Description, topic.Image).
 					WillReturnError(errors.New("name can not be empty"))
 			},
 			wantError: true,
@@ -267,8 +286,8 @@ func TestCreateTopic(t *testing.T) {
 				Image:       tTopic.Image,
 			},
 			mock: func(topic x.Topic) {
-				mock.ExpectExec(queryMatch).WithArgs(topic.Name, topic.StartYear, topic.EndYear, topic.Description,
-					topic.Image).
+				mock.ExpectExec(queryMatch).WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear,
+					topic.Description, topic.Image).
 					WillReturnError(errors.New("start-year can not be empty"))
 			},
 			wantError: true,
@@ -283,8 +302,8 @@ func TestCreateTopic(t *testing.T) {
 				Image:       tTopic.Image,
 			},
 			mock: func(topic x.Topic) {
-				mock.ExpectExec(queryMatch).WithArgs(topic.Name, topic.StartYear, topic.EndYear, topic.Description,
-					topic.Image).
+				mock.ExpectExec(queryMatch).WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear,
+					topic.Description, topic.Image).
 					WillReturnError(errors.New("end-year can not be empty"))
 			},
 			wantError: true,
@@ -300,8 +319,8 @@ func TestCreateTopic(t *testing.T) {
 				Image:     tTopic.Image,
 			},
 			mock: func(topic x.Topic) {
-				mock.ExpectExec(queryMatch).WithArgs(topic.Name, topic.StartYear, topic.EndYear, topic.Description,
-					topic.Image).
+				mock.ExpectExec(queryMatch).WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear,
+					topic.Description, topic.Image).
 					WillReturnResult(sqlmock.NewResult(int64(topic.TopicID), 1))
 			},
 			wantError: false,
@@ -317,8 +336,8 @@ func TestCreateTopic(t *testing.T) {
 				Description: tTopic.Description,
 			},
 			mock: func(topic x.Topic) {
-				mock.ExpectExec(queryMatch).WithArgs(topic.Name, topic.StartYear, topic.EndYear, topic.Description,
-					topic.Image).
+				mock.ExpectExec(queryMatch).WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear,
+					topic.Description, topic.Image).
 					WillReturnError(errors.New("image can not be empty"))
 			},
 			wantError: true,
@@ -340,6 +359,96 @@ func TestCreateTopic(t *testing.T) {
 	}
 }
 
+// TestCreateTopicWithEvents tests creating a new topic including its events.
+func TestCreateTopicWithEvents(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TopicStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO topics"
+	queryMatchEvents := "INSERT INTO events"
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		topic       x.Topic
+		mock        func(topic x.Topic)
+		wantTopicID int
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name:  "#1 OK",
+			topic: tTopic,
+			mock: func(topic x.Topic) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).
+					WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear, topic.Description, topic.Image).
+					WillReturnResult(sqlmock.NewResult(7, 1))
+				for _, event := range topic.Events {
+					mock.ExpectExec(queryMatchEvents).WithArgs(7, event.Name, event.Year, event.Date, event.Description,
+						event.Source, event.Image).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+			},
+			wantTopicID: 7,
+			wantError:   false,
+		},
+		{
+			// When an event can't be created, which rolls back the topic as
+			// well
+			name:  "#2 EVENT FAILED",
+			topic: tTopic,
+			mock: func(topic x.Topic) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).
+					WithArgs(topic.ParentID, topic.Name, topic.StartYear, topic.EndYear, topic.Description, topic.Image).
+					WillReturnResult(sqlmock.NewResult(8, 1))
+				mock.ExpectExec(queryMatchEvents).WillReturnError(errors.New("name can not be empty"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+		{
+			// When the topic can't be created
+			name:  "#3 TOPIC FAILED",
+			topic: tTopic,
+			mock: func(topic x.Topic) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryMatch).WillReturnError(errors.New("name can not be empty"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topic)
+
+			// Copy events, since the topic ID of each event gets overwritten
+			test.topic.Events = append([]x.Event{}, test.topic.Events...)
+			err := store.CreateTopicWithEvents(&test.topic)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("CreateTopicWithEvents() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && test.topic.TopicID != test.wantTopicID {
+				t.Errorf("CreateTopicWithEvents() topic ID = %v, want %v", test.topic.TopicID, test.wantTopicID)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateTopicWithEvents() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
 // TestUpdateTopic tests updating an existing topic.
 func TestUpdateTopic(t *testing.T) {
 
@@ -487,7 +596,75 @@ func TestUpdateTopic(t *testing.T) {
 	}
 }
 
-// TestDeleteTopic tests deleting an existing topic.
+// TestMoveTopic tests setting the parent topic of a topic.
+func TestMoveTopic(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TopicStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE topics SET parent_id = (.+) WHERE topic_id = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		parentID  int
+		mock      func(topicID int, parentID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:     "#1 OK",
+			topicID:  2,
+			parentID: tTopic.TopicID,
+			mock: func(topicID int, parentID int) {
+				mock.ExpectExec(queryMatch).WithArgs(parentID, topicID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When the topic becomes a top-level topic
+			name:     "#2 OK (TOP-LEVEL)",
+			topicID:  2,
+			parentID: 0,
+			mock: func(topicID int, parentID int) {
+				mock.ExpectExec(queryMatch).WithArgs(parentID, topicID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When topic with given topic ID doesn't exist
+			name:     "#3 NOT FOUND",
+			topicID:  0,
+			parentID: tTopic.TopicID,
+			mock: func(topicID int, parentID int) {
+				mock.ExpectExec(queryMatch).WithArgs(parentID, topicID).
+					WillReturnError(errors.New("topic with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID, test.parentID)
+
+			err := store.MoveTopic(test.topicID, test.parentID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("MoveTopic() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestDeleteTopic tests moving an existing topic to the trash.
 func TestDeleteTopic(t *testing.T) {
 
 	// New mock database
@@ -495,7 +672,7 @@ func TestDeleteTopic(t *testing.T) {
 	store := &TopicStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "DELETE FROM topics"
+	queryMatch := "UPDATE topics SET deleted_at = CURRENT_TIMESTAMP"
 
 	// Declare test cases
 	tests := []struct {
@@ -540,3 +717,207 @@ func TestDeleteTopic(t *testing.T) {
 		})
 	}
 }
+
+// TestGetDeletedTopics tests getting all topics in the trash including their
+// events.
+func TestGetDeletedTopics(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TopicStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM topics (.+) WHERE t.deleted_at IS NOT NULL"
+	queryMatchEvents := "SELECT (.+) FROM events"
+
+	deletedAt := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	tDeletedTopic := tTopic
+	tDeletedTopic.DeletedAt = &deletedAt
+
+	table := []string{"topic_id", "name", "start_year", "end_year", "description", "image", "deleted_at",
+		"scores_count", "events_count"}
+	tableEvents := []string{"event_id", "topic_id", "name", "year", "date", "description", "source", "image"}
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		mock       func()
+		wantTopics []x.Topic
+		wantError  bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(tDeletedTopic.TopicID, tDeletedTopic.Name, tDeletedTopic.StartYear, tDeletedTopic.EndYear,
+						tDeletedTopic.Description, tDeletedTopic.Image, tDeletedTopic.DeletedAt,
+						tDeletedTopic.ScoresCount, tDeletedTopic.EventsCount)
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+
+				rowsEvents := sqlmock.NewRows(tableEvents)
+				for _, event := range tDeletedTopic.Events {
+					rowsEvents = rowsEvents.AddRow(event.EventID, event.TopicID, event.Name, event.Year, event.Date,
+						event.Description, event.Source, event.Image)
+				}
+				mock.ExpectQuery(queryMatchEvents).WithArgs(tDeletedTopic.TopicID).WillReturnRows(rowsEvents)
+			},
+			wantTopics: []x.Topic{tDeletedTopic},
+			wantError:  false,
+		},
+		{
+			// When the trash is empty
+			name: "#2 OK (NO ROWS)",
+			mock: func() {
+				rows := sqlmock.NewRows(table)
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			},
+			wantTopics: nilTopics,
+			wantError:  false,
+		},
+		{
+			// When getting the events fails
+			name: "#3 EVENTS ERROR",
+			mock: func() {
+				rows := sqlmock.NewRows(table).
+					AddRow(tDeletedTopic.TopicID, tDeletedTopic.Name, tDeletedTopic.StartYear, tDeletedTopic.EndYear,
+						tDeletedTopic.Description, tDeletedTopic.Image, tDeletedTopic.DeletedAt,
+						tDeletedTopic.ScoresCount, tDeletedTopic.EventsCount)
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+				mock.ExpectQuery(queryMatchEvents).WithArgs(tDeletedTopic.TopicID).
+					WillReturnError(errors.New("table events does not exist"))
+			},
+			wantTopics: nil,
+			wantError:  true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			topics, err := store.GetDeletedTopics()
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetDeletedTopics() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(topics, test.wantTopics) {
+				t.Errorf("GetDeletedTopics() = %v, want %v", topics, test.wantTopics)
+			}
+		})
+	}
+}
+
+// TestRestoreTopic tests restoring a topic from the trash.
+func TestRestoreTopic(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TopicStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE topics SET deleted_at = NULL"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		mock      func(topicID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: tTopic.TopicID,
+			mock: func(topicID int) {
+				mock.ExpectExec(queryMatch).WithArgs(topicID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When topic with given topic ID doesn't exist
+			name:    "#2 NOT FOUND",
+			topicID: 0,
+			mock: func(topicID int) {
+				mock.ExpectExec(queryMatch).WithArgs(topicID).
+					WillReturnError(errors.New("topic with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID)
+
+			err := store.RestoreTopic(test.topicID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("RestoreTopic() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestPurgeTopic tests permanently deleting a topic in the trash.
+func TestPurgeTopic(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TopicStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "DELETE FROM topics"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		mock      func(topicID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: tTopic.TopicID,
+			mock: func(topicID int) {
+				mock.ExpectExec(queryMatch).WithArgs(topicID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When topic with given topic ID doesn't exist
+			name:    "#2 NOT FOUND",
+			topicID: 0,
+			mock: func(topicID int) {
+				mock.ExpectExec(queryMatch).WithArgs(topicID).
+					WillReturnError(errors.New("topic with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID)
+
+			err := store.PurgeTopic(test.topicID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("PurgeTopic() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
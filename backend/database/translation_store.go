@@ -0,0 +1,191 @@
+// The database store evolving around translations of topics and events into
+// other languages, with all necessary methods that access the database.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// TranslationStore is the MySQL database access object.
+type TranslationStore struct {
+	*sqlx.DB
+}
+
+// GetTopicTranslations gets all translations of a topic, sorted by locale.
+func (store *TranslationStore) GetTopicTranslations(topicID int) ([]x.Translation, error) {
+	var translations []x.Translation
+
+	query := `
+		SELECT topic_id AS id, locale, name, description
+		FROM topic_translations
+		WHERE topic_id = ?
+		ORDER BY locale
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&translations, query, topicID); err != nil {
+		return []x.Translation{}, fmt.Errorf("error getting translations of topic: %w", err)
+	}
+
+	return translations, nil
+}
+
+// GetTopicTranslationsByLocale gets the translations of all topics into a
+// locale, mapped by the ID of the topic.
+func (store *TranslationStore) GetTopicTranslationsByLocale(locale string) (map[int]x.Translation, error) {
+	var rows []x.Translation
+
+	query := `
+		SELECT topic_id AS id, locale, name, description
+		FROM topic_translations
+		WHERE locale = ?
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&rows, query, locale); err != nil {
+		return map[int]x.Translation{}, fmt.Errorf("error getting translations of topics: %w", err)
+	}
+
+	translations := make(map[int]x.Translation)
+	for _, row := range rows {
+		translations[row.ID] = row
+	}
+
+	return translations, nil
+}
+
+// SaveTopicTranslation creates the translation of a topic into a locale or
+// updates it, if it already exists.
+func (store *TranslationStore) SaveTopicTranslation(translation *x.Translation) error {
+
+	query := `
+		INSERT INTO topic_translations(topic_id, locale, name, description)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name), description = VALUES(description)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		translation.ID,
+		translation.Locale,
+		translation.Name,
+		translation.Description); err != nil {
+		return fmt.Errorf("error saving translation of topic: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTopicTranslation deletes the translation of a topic into a locale.
+func (store *TranslationStore) DeleteTopicTranslation(topicID int, locale string) error {
+
+	query := `
+		DELETE FROM topic_translations
+		WHERE topic_id = ?
+		  AND locale = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, topicID, locale); err != nil {
+		return fmt.Errorf("error deleting translation of topic: %w", err)
+	}
+
+	return nil
+}
+
+// GetEventTranslations gets all translations of an event, sorted by locale.
+func (store *TranslationStore) GetEventTranslations(eventID int) ([]x.Translation, error) {
+	var translations []x.Translation
+
+	query := `
+		SELECT event_id AS id, locale, name, description
+		FROM event_translations
+		WHERE event_id = ?
+		ORDER BY locale
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&translations, query, eventID); err != nil {
+		return []x.Translation{}, fmt.Errorf("error getting translations of event: %w", err)
+	}
+
+	return translations, nil
+}
+
+// GetEventTranslationsByTopics gets the translations of all events of
+// multiple topics into a locale, mapped by the ID of the event.
+func (store *TranslationStore) GetEventTranslationsByTopics(topicIDs []int,
+	locale string) (map[int]x.Translation, error) {
+	var rows []x.Translation
+
+	if len(topicIDs) == 0 {
+		return map[int]x.Translation{}, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT et.event_id AS id, et.locale, et.name, et.description
+		FROM event_translations et
+		    JOIN events e ON e.event_id = et.event_id
+		WHERE e.topic_id IN (?)
+		  AND et.locale = ?
+		`, topicIDs, locale)
+	if err != nil {
+		return map[int]x.Translation{}, fmt.Errorf("error building query for translations of events: %w", err)
+	}
+
+	// Execute prepared statement
+	if err = store.Select(&rows, store.Rebind(query), args...); err != nil {
+		return map[int]x.Translation{}, fmt.Errorf("error getting translations of events: %w", err)
+	}
+
+	translations := make(map[int]x.Translation)
+	for _, row := range rows {
+		translations[row.ID] = row
+	}
+
+	return translations, nil
+}
+
+// SaveEventTranslation creates the translation of an event into a locale or
+// updates it, if it already exists.
+func (store *TranslationStore) SaveEventTranslation(translation *x.Translation) error {
+
+	query := `
+		INSERT INTO event_translations(event_id, locale, name, description)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name), description = VALUES(description)
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query,
+		translation.ID,
+		translation.Locale,
+		translation.Name,
+		translation.Description); err != nil {
+		return fmt.Errorf("error saving translation of event: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEventTranslation deletes the translation of an event into a locale.
+func (store *TranslationStore) DeleteEventTranslation(eventID int, locale string) error {
+
+	query := `
+		DELETE FROM event_translations
+		WHERE event_id = ?
+		  AND locale = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, eventID, locale); err != nil {
+		return fmt.Errorf("error deleting translation of event: %w", err)
+	}
+
+	return nil
+}
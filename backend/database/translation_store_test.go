@@ -0,0 +1,551 @@
+// Collection of tests for the database access layer of functions evolving
+// around translations of topics and events.
+
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+var (
+	// tTranslation is a mock translation of a topic for testing purposes
+	tTranslation = x.Translation{
+		ID:          1,
+		Locale:      "en",
+		Name:        "Second World War",
+		Description: "The war from 1939 to 1945.",
+	}
+
+	// tTranslation2 is a mock translation of an event for testing purposes
+	tTranslation2 = x.Translation{
+		ID:          2,
+		Locale:      "en",
+		Name:        "Battle of Stalingrad",
+		Description: "",
+	}
+
+	// nilTranslations is a nil slice of translations
+	nilTranslations []x.Translation
+
+	// translationsTable are the columns of a translation
+	translationsTable = []string{"id", "locale", "name", "description"}
+)
+
+// TestGetTopicTranslations tests getting all translations of a topic.
+func TestGetTopicTranslations(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM topic_translations WHERE topic_id = (.+) ORDER BY locale"
+
+	// Declare test cases
+	tests := []struct {
+		name             string
+		topicID          int
+		mock             func(topicID int)
+		wantTranslations []x.Translation
+		wantError        bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: tTranslation.ID,
+			mock: func(topicID int) {
+				rows := sqlmock.NewRows(translationsTable).
+					AddRow(tTranslation.ID, tTranslation.Locale, tTranslation.Name, tTranslation.Description)
+
+				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(rows)
+			},
+			wantTranslations: []x.Translation{tTranslation},
+			wantError:        false,
+		},
+		{
+			// When topic has no translations
+			name:    "#2 OK (NO ROWS)",
+			topicID: 3,
+			mock: func(topicID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(topicID).WillReturnRows(sqlmock.NewRows(translationsTable))
+			},
+			wantTranslations: nilTranslations,
+			wantError:        false,
+		},
+		{
+			// When the translations table doesn't exist
+			name:    "#3 ERROR",
+			topicID: tTranslation.ID,
+			mock: func(topicID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(topicID).
+					WillReturnError(errors.New("table topic_translations does not exist"))
+			},
+			wantTranslations: nil,
+			wantError:        true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID)
+
+			translations, err := store.GetTopicTranslations(test.topicID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetTopicTranslations() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(translations, test.wantTranslations) {
+				t.Errorf("GetTopicTranslations() = %v, want %v", translations, test.wantTranslations)
+			}
+		})
+	}
+}
+
+// TestGetTopicTranslationsByLocale tests getting the translations of all
+// topics into a locale.
+func TestGetTopicTranslationsByLocale(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM topic_translations WHERE locale = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name             string
+		locale           string
+		mock             func(locale string)
+		wantTranslations map[int]x.Translation
+		wantError        bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			locale: "en",
+			mock: func(locale string) {
+				rows := sqlmock.NewRows(translationsTable).
+					AddRow(tTranslation.ID, tTranslation.Locale, tTranslation.Name, tTranslation.Description)
+
+				mock.ExpectQuery(queryMatch).WithArgs(locale).WillReturnRows(rows)
+			},
+			wantTranslations: map[int]x.Translation{tTranslation.ID: tTranslation},
+			wantError:        false,
+		},
+		{
+			// When there are no translations into the locale
+			name:   "#2 OK (NO ROWS)",
+			locale: "fr",
+			mock: func(locale string) {
+				mock.ExpectQuery(queryMatch).WithArgs(locale).WillReturnRows(sqlmock.NewRows(translationsTable))
+			},
+			wantTranslations: map[int]x.Translation{},
+			wantError:        false,
+		},
+		{
+			// When the translations table doesn't exist
+			name:   "#3 ERROR",
+			locale: "en",
+			mock: func(locale string) {
+				mock.ExpectQuery(queryMatch).WithArgs(locale).
+					WillReturnError(errors.New("table topic_translations does not exist"))
+			},
+			wantTranslations: nil,
+			wantError:        true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.locale)
+
+			translations, err := store.GetTopicTranslationsByLocale(test.locale)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetTopicTranslationsByLocale() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(translations, test.wantTranslations) {
+				t.Errorf("GetTopicTranslationsByLocale() = %v, want %v", translations, test.wantTranslations)
+			}
+		})
+	}
+}
+
+// TestSaveTopicTranslation tests creating or updating the translation of a
+// topic.
+func TestSaveTopicTranslation(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO topic_translations(.+) VALUES (.+) ON DUPLICATE KEY UPDATE"
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		translation x.Translation
+		mock        func(translation x.Translation)
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name:        "#1 OK",
+			translation: tTranslation,
+			mock: func(translation x.Translation) {
+				mock.ExpectExec(queryMatch).
+					WithArgs(translation.ID, translation.Locale, translation.Name, translation.Description).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When topic with given topic ID doesn't exist
+			name:        "#2 NOT FOUND",
+			translation: x.Translation{ID: 0, Locale: "en", Name: "Topic"},
+			mock: func(translation x.Translation) {
+				mock.ExpectExec(queryMatch).
+					WithArgs(translation.ID, translation.Locale, translation.Name, translation.Description).
+					WillReturnError(errors.New("foreign key constraint fails"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.translation)
+
+			err := store.SaveTopicTranslation(&test.translation)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("SaveTopicTranslation() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestDeleteTopicTranslation tests deleting the translation of a topic.
+func TestDeleteTopicTranslation(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "DELETE FROM topic_translations WHERE topic_id = (.+) AND locale = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		topicID   int
+		locale    string
+		mock      func(topicID int, locale string)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			topicID: tTranslation.ID,
+			locale:  tTranslation.Locale,
+			mock: func(topicID int, locale string) {
+				mock.ExpectExec(queryMatch).WithArgs(topicID, locale).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When the translations table doesn't exist
+			name:    "#2 ERROR",
+			topicID: tTranslation.ID,
+			locale:  tTranslation.Locale,
+			mock: func(topicID int, locale string) {
+				mock.ExpectExec(queryMatch).WithArgs(topicID, locale).
+					WillReturnError(errors.New("table topic_translations does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicID, test.locale)
+
+			err := store.DeleteTopicTranslation(test.topicID, test.locale)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("DeleteTopicTranslation() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestGetEventTranslations tests getting all translations of an event.
+func TestGetEventTranslations(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM event_translations WHERE event_id = (.+) ORDER BY locale"
+
+	// Declare test cases
+	tests := []struct {
+		name             string
+		eventID          int
+		mock             func(eventID int)
+		wantTranslations []x.Translation
+		wantError        bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			eventID: tTranslation2.ID,
+			mock: func(eventID int) {
+				rows := sqlmock.NewRows(translationsTable).
+					AddRow(tTranslation2.ID, tTranslation2.Locale, tTranslation2.Name, tTranslation2.Description)
+
+				mock.ExpectQuery(queryMatch).WithArgs(eventID).WillReturnRows(rows)
+			},
+			wantTranslations: []x.Translation{tTranslation2},
+			wantError:        false,
+		},
+		{
+			// When event has no translations
+			name:    "#2 OK (NO ROWS)",
+			eventID: 3,
+			mock: func(eventID int) {
+				mock.ExpectQuery(queryMatch).WithArgs(eventID).WillReturnRows(sqlmock.NewRows(translationsTable))
+			},
+			wantTranslations: nilTranslations,
+			wantError:        false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.eventID)
+
+			translations, err := store.GetEventTranslations(test.eventID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetEventTranslations() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(translations, test.wantTranslations) {
+				t.Errorf("GetEventTranslations() = %v, want %v", translations, test.wantTranslations)
+			}
+		})
+	}
+}
+
+// TestGetEventTranslationsByTopics tests getting the translations of all
+// events of multiple topics into a locale.
+func TestGetEventTranslationsByTopics(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT (.+) FROM event_translations et (.+) WHERE e.topic_id IN (.+) AND et.locale = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name             string
+		topicIDs         []int
+		locale           string
+		mock             func(topicIDs []int, locale string)
+		wantTranslations map[int]x.Translation
+		wantError        bool
+	}{
+		{
+			// When everything works as intended
+			name:     "#1 OK",
+			topicIDs: []int{1, 2},
+			locale:   "en",
+			mock: func(topicIDs []int, locale string) {
+				rows := sqlmock.NewRows(translationsTable).
+					AddRow(tTranslation2.ID, tTranslation2.Locale, tTranslation2.Name, tTranslation2.Description)
+
+				mock.ExpectQuery(queryMatch).WithArgs(topicIDs[0], topicIDs[1], locale).WillReturnRows(rows)
+			},
+			wantTranslations: map[int]x.Translation{tTranslation2.ID: tTranslation2},
+			wantError:        false,
+		},
+		{
+			// When there are no topics, no query gets executed
+			name:             "#2 OK (NO TOPICS)",
+			topicIDs:         []int{},
+			locale:           "en",
+			mock:             func(topicIDs []int, locale string) {},
+			wantTranslations: map[int]x.Translation{},
+			wantError:        false,
+		},
+		{
+			// When the translations table doesn't exist
+			name:     "#3 ERROR",
+			topicIDs: []int{1},
+			locale:   "en",
+			mock: func(topicIDs []int, locale string) {
+				mock.ExpectQuery(queryMatch).WithArgs(topicIDs[0], locale).
+					WillReturnError(errors.New("table event_translations does not exist"))
+			},
+			wantTranslations: nil,
+			wantError:        true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.topicIDs, test.locale)
+
+			translations, err := store.GetEventTranslationsByTopics(test.topicIDs, test.locale)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetEventTranslationsByTopics() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(translations, test.wantTranslations) {
+				t.Errorf("GetEventTranslationsByTopics() = %v, want %v", translations, test.wantTranslations)
+			}
+		})
+	}
+}
+
+// TestSaveEventTranslation tests creating or updating the translation of an
+// event.
+func TestSaveEventTranslation(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "INSERT INTO event_translations(.+) VALUES (.+) ON DUPLICATE KEY UPDATE"
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		translation x.Translation
+		mock        func(translation x.Translation)
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name:        "#1 OK",
+			translation: tTranslation2,
+			mock: func(translation x.Translation) {
+				mock.ExpectExec(queryMatch).
+					WithArgs(translation.ID, translation.Locale, translation.Name, translation.Description).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When event with given event ID doesn't exist
+			name:        "#2 NOT FOUND",
+			translation: x.Translation{ID: 0, Locale: "en", Name: "Event"},
+			mock: func(translation x.Translation) {
+				mock.ExpectExec(queryMatch).
+					WithArgs(translation.ID, translation.Locale, translation.Name, translation.Description).
+					WillReturnError(errors.New("foreign key constraint fails"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.translation)
+
+			err := store.SaveEventTranslation(&test.translation)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("SaveEventTranslation() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestDeleteEventTranslation tests deleting the translation of an event.
+func TestDeleteEventTranslation(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &TranslationStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "DELETE FROM event_translations WHERE event_id = (.+) AND locale = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		eventID   int
+		locale    string
+		mock      func(eventID int, locale string)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:    "#1 OK",
+			eventID: tTranslation2.ID,
+			locale:  tTranslation2.Locale,
+			mock: func(eventID int, locale string) {
+				mock.ExpectExec(queryMatch).WithArgs(eventID, locale).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When the translations table doesn't exist
+			name:    "#2 ERROR",
+			eventID: tTranslation2.ID,
+			locale:  tTranslation2.Locale,
+			mock: func(eventID int, locale string) {
+				mock.ExpectExec(queryMatch).WithArgs(eventID, locale).
+					WillReturnError(errors.New("table event_translations does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.eventID, test.locale)
+
+			err := store.DeleteEventTranslation(test.eventID, test.locale)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("DeleteEventTranslation() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 // The database store evolving around users, with all necessary methods that
 // access the database.
+//
+// Deleting a user moves the user to the trash, from where the user can be
+// restored until the user gets purged. Users in the trash are excluded from
+// all other queries, except for checking whether a username or email is taken,
+// since they stay reserved until the user gets purged.
 
 package database
 
@@ -25,7 +30,8 @@ func (store *UserStore) GetUser(userID int) (x.User, error) {
 		       COUNT(DISTINCT s.score_id) AS scores_count
 		FROM users u 
 		    LEFT JOIN scores s ON s.user_id = u.user_id
-		WHERE u.user_id = ?
+		WHERE u.user_id = ? 
+		  AND u.deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -45,7 +51,8 @@ func (store *UserStore) GetUserByUsername(username string) (x.User, error) {
 		       COUNT(DISTINCT s.score_id) AS scores_count
 		FROM users u 
 		    LEFT JOIN scores s ON s.user_id = u.user_id
-		WHERE u.username = ?
+		WHERE u.username = ? 
+		  AND u.deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -65,7 +72,8 @@ func (store *UserStore) GetUserByEmail(email string) (x.User, error) {
 		       COUNT(DISTINCT s.score_id) AS scores_count
 		FROM users u 
 		    LEFT JOIN scores s ON s.user_id = u.user_id
-		WHERE u.email = ?
+		WHERE u.email = ? 
+		  AND u.deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -85,6 +93,7 @@ func (store *UserStore) GetUsers() ([]x.User, error) {
 		       COUNT(DISTINCT s.score_id) AS scores_count
 		FROM users u
 		    LEFT JOIN scores s ON s.user_id = u.user_id
+		WHERE u.deleted_at IS NULL
 		GROUP BY u.user_id, u.admin, u.username
 		ORDER BY u.admin DESC, u.username 
 		` // Sorted in alphabetical order, but all admins first
@@ -97,13 +106,48 @@ func (store *UserStore) GetUsers() ([]x.User, error) {
 	return users, nil
 }
 
+// IsUsernameTaken checks whether a username is taken, including by users in
+// the trash.
+func (store *UserStore) IsUsernameTaken(username string) (bool, error) {
+	var taken bool
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM users WHERE username = ?)
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&taken, query, username); err != nil {
+		return false, fmt.Errorf("error checking username: %w", err)
+	}
+
+	return taken, nil
+}
+
+// IsEmailTaken checks whether an email is taken, including by users in the
+// trash.
+func (store *UserStore) IsEmailTaken(email string) (bool, error) {
+	var taken bool
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)
+		`
+
+	// Execute prepared statement
+	if err := store.Get(&taken, query, email); err != nil {
+		return false, fmt.Errorf("error checking email: %w", err)
+	}
+
+	return taken, nil
+}
+
 // CountUsers gets amount of users.
 func (store *UserStore) CountUsers() (int, error) {
 	var userCount int
 
 	query := `
 		SELECT COUNT(user_id) 
-		FROM users
+		FROM users 
+		WHERE deleted_at IS NULL
 		`
 
 	// Execute prepared statement
@@ -159,14 +203,71 @@ func (store *UserStore) UpdateUser(user *x.User) error {
 	return nil
 }
 
-// DeleteUser deletes an existing user.
-func (store *UserStore) DeleteUser(userID int) error {
+// UpdateUserLocale updates the preferred language of a user.
+func (store *UserStore) UpdateUserLocale(userID int, locale string) error {
 
 	query := `
-		DELETE FROM users 
+		UPDATE users 
+		SET locale = ? 
+		WHERE user_id = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, locale, userID); err != nil {
+		return fmt.Errorf("error updating locale of user: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUserClass updates the school class of a user.
+func (store *UserStore) UpdateUserClass(userID int, class string) error {
+
+	query := `
+		UPDATE users 
+		SET class = ? 
 		WHERE user_id = ?
 		`
 
+	// Execute prepared statement
+	if _, err := store.Exec(query, class, userID); err != nil {
+		return fmt.Errorf("error updating class of user: %w", err)
+	}
+
+	return nil
+}
+
+// GetClasses gets the school classes of all users (not in the trash), sorted
+// alphabetically.
+func (store *UserStore) GetClasses() ([]string, error) {
+	var classes []string
+
+	query := `
+		SELECT DISTINCT class 
+		FROM users 
+		WHERE class != '' 
+		  AND deleted_at IS NULL
+		ORDER BY class
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&classes, query); err != nil {
+		return []string{}, fmt.Errorf("error getting classes: %w", err)
+	}
+
+	return classes, nil
+}
+
+// DeleteUser moves an existing user to the trash.
+func (store *UserStore) DeleteUser(userID int) error {
+
+	query := `
+		UPDATE users 
+		SET deleted_at = CURRENT_TIMESTAMP 
+		WHERE user_id = ? 
+		  AND deleted_at IS NULL
+		`
+
 	// Execute prepared statement
 	if _, err := store.Exec(query, userID); err != nil {
 		return fmt.Errorf("error deleting user: %w", err)
@@ -174,3 +275,60 @@ func (store *UserStore) DeleteUser(userID int) error {
 
 	return nil
 }
+
+// GetDeletedUsers gets all users in the trash, sorted by date of deletion
+// descending.
+func (store *UserStore) GetDeletedUsers() ([]x.User, error) {
+	var users []x.User
+
+	query := `
+		SELECT u.*,
+		       COUNT(DISTINCT s.score_id) AS scores_count
+		FROM users u
+		    LEFT JOIN scores s ON s.user_id = u.user_id
+		WHERE u.deleted_at IS NOT NULL
+		GROUP BY u.user_id, u.deleted_at
+		ORDER BY u.deleted_at DESC
+		`
+
+	// Execute prepared statement
+	if err := store.Select(&users, query); err != nil {
+		return []x.User{}, fmt.Errorf("error getting deleted users: %w", err)
+	}
+
+	return users, nil
+}
+
+// RestoreUser restores a user from the trash.
+func (store *UserStore) RestoreUser(userID int) error {
+
+	query := `
+		UPDATE users 
+		SET deleted_at = NULL 
+		WHERE user_id = ?
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, userID); err != nil {
+		return fmt.Errorf("error restoring user: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeUser permanently deletes a user in the trash.
+func (store *UserStore) PurgeUser(userID int) error {
+
+	query := `
+		DELETE FROM users 
+		WHERE user_id = ? 
+		  AND deleted_at IS NOT NULL
+		`
+
+	// Execute prepared statement
+	if _, err := store.Exec(query, userID); err != nil {
+		return fmt.Errorf("error purging user: %w", err)
+	}
+
+	return nil
+}
@@ -582,7 +582,179 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
-// TestDeleteUser tests deleting an existing user.
+// TestUpdateUserLocale tests updating the preferred language of a user.
+func TestUpdateUserLocale(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &UserStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE users SET locale = (.+) WHERE user_id = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		userID    int
+		locale    string
+		mock      func(userID int, locale string)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: tUser.UserID,
+			locale: "en",
+			mock: func(userID int, locale string) {
+				mock.ExpectExec(queryMatch).WithArgs(locale, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When user with given user ID doesn't exist
+			name:   "#2 NOT FOUND",
+			userID: 0,
+			locale: "en",
+			mock: func(userID int, locale string) {
+				mock.ExpectExec(queryMatch).WithArgs(locale, userID).
+					WillReturnError(errors.New("user with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID, test.locale)
+
+			err := store.UpdateUserLocale(test.userID, test.locale)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("UpdateUserLocale() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestUpdateUserClass tests updating the school class of a user.
+func TestUpdateUserClass(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &UserStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE users SET class = (.+) WHERE user_id = (.+)"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		userID    int
+		class     string
+		mock      func(userID int, class string)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: tUser.UserID,
+			class:  "4a",
+			mock: func(userID int, class string) {
+				mock.ExpectExec(queryMatch).WithArgs(class, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When user with given user ID doesn't exist
+			name:   "#2 NOT FOUND",
+			userID: 0,
+			class:  "4a",
+			mock: func(userID int, class string) {
+				mock.ExpectExec(queryMatch).WithArgs(class, userID).
+					WillReturnError(errors.New("user with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID, test.class)
+
+			err := store.UpdateUserClass(test.userID, test.class)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("UpdateUserClass() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestGetClasses tests getting the school classes of all users.
+func TestGetClasses(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &UserStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT DISTINCT class FROM users WHERE (.+) ORDER BY class"
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		mock        func()
+		wantClasses []string
+		wantError   bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"class"}).AddRow("4a").AddRow("4b")
+
+				mock.ExpectQuery(queryMatch).WillReturnRows(rows)
+			},
+			wantClasses: []string{"4a", "4b"},
+			wantError:   false,
+		},
+		{
+			// When the users table doesn't exist
+			name: "#2 ERROR",
+			mock: func() {
+				mock.ExpectQuery(queryMatch).WillReturnError(errors.New("table users does not exist"))
+			},
+			wantClasses: nil,
+			wantError:   true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock()
+
+			classes, err := store.GetClasses()
+
+			if (err != nil) != test.wantError {
+				t.Errorf("GetClasses() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(classes, test.wantClasses) {
+				t.Errorf("GetClasses() = %v, want %v", classes, test.wantClasses)
+			}
+		})
+	}
+}
+
+// TestDeleteUser tests moving an existing user to the trash.
 func TestDeleteUser(t *testing.T) {
 
 	// New mock database
@@ -590,7 +762,7 @@ func TestDeleteUser(t *testing.T) {
 	store := &UserStore{DB: db}
 	defer db.Close()
 
-	queryMatch := "DELETE FROM users"
+	queryMatch := "UPDATE users SET deleted_at = CURRENT_TIMESTAMP"
 
 	// Declare test cases
 	tests := []struct {
@@ -635,3 +807,187 @@ func TestDeleteUser(t *testing.T) {
 		})
 	}
 }
+
+// TestIsUsernameTaken tests checking whether a username is taken.
+func TestIsUsernameTaken(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &UserStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "SELECT EXISTS(.+) FROM users WHERE username"
+
+	table := []string{"taken"}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		username  string
+		mock      func(username string)
+		wantTaken bool
+		wantError bool
+	}{
+		{
+			// When the username is taken
+			name:     "#1 TAKEN",
+			username: tUser.Username,
+			mock: func(username string) {
+				rows := sqlmock.NewRows(table).AddRow(true)
+
+				mock.ExpectQuery(queryMatch).WithArgs(username).WillReturnRows(rows)
+			},
+			wantTaken: true,
+			wantError: false,
+		},
+		{
+			// When the username isn't taken
+			name:     "#2 NOT TAKEN",
+			username: "user_3",
+			mock: func(username string) {
+				rows := sqlmock.NewRows(table).AddRow(false)
+
+				mock.ExpectQuery(queryMatch).WithArgs(username).WillReturnRows(rows)
+			},
+			wantTaken: false,
+			wantError: false,
+		},
+		{
+			// When the users table doesn't exist
+			name:     "#3 ERROR",
+			username: tUser.Username,
+			mock: func(username string) {
+				mock.ExpectQuery(queryMatch).WithArgs(username).
+					WillReturnError(errors.New("table users does not exist"))
+			},
+			wantTaken: false,
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.username)
+
+			taken, err := store.IsUsernameTaken(test.username)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("IsUsernameTaken() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if taken != test.wantTaken {
+				t.Errorf("IsUsernameTaken() = %v, want %v", taken, test.wantTaken)
+			}
+		})
+	}
+}
+
+// TestRestoreUser tests restoring a user from the trash.
+func TestRestoreUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &UserStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "UPDATE users SET deleted_at = NULL"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		userID    int
+		mock      func(userID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: tUser.UserID,
+			mock: func(userID int) {
+				mock.ExpectExec(queryMatch).WithArgs(userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When user with given user ID doesn't exist
+			name:   "#2 NOT FOUND",
+			userID: 0,
+			mock: func(userID int) {
+				mock.ExpectExec(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("user with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			err := store.RestoreUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("RestoreUser() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
+
+// TestPurgeUser tests permanently deleting a user in the trash.
+func TestPurgeUser(t *testing.T) {
+
+	// New mock database
+	db, mock := NewMock()
+	store := &UserStore{DB: db}
+	defer db.Close()
+
+	queryMatch := "DELETE FROM users"
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		userID    int
+		mock      func(userID int)
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:   "#1 OK",
+			userID: tUser.UserID,
+			mock: func(userID int) {
+				mock.ExpectExec(queryMatch).WithArgs(userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
+		{
+			// When user with given user ID doesn't exist
+			name:   "#2 NOT FOUND",
+			userID: 0,
+			mock: func(userID int) {
+				mock.ExpectExec(queryMatch).WithArgs(userID).
+					WillReturnError(errors.New("user with given id does not exist"))
+			},
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			test.mock(test.userID)
+
+			err := store.PurgeUser(test.userID)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("PurgeUser() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+}
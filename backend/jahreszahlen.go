@@ -9,40 +9,52 @@
 package backend
 
 import (
+	"errors"
 	"time"
 )
 
 // Topic represents a historical segment consisting of multiple events.
 type Topic struct {
-	TopicID     int     `db:"topic_id"`
-	Name        string  `db:"name"`
-	StartYear   int     `db:"start_year"`
-	EndYear     int     `db:"end_year"`
-	Description string  `db:"description"`
-	Image       string  `db:"image"`
-	Events      []Event `db:"events"`
-	ScoresCount int     `db:"scores_count"`
-	EventsCount int     `db:"events_count"`
+	TopicID      int           `db:"topic_id"`
+	ParentID     int           `db:"parent_id"` // ID of the parent topic, 0 for a top-level topic
+	Name         string        `db:"name"`
+	StartYear    int           `db:"start_year"`
+	EndYear      int           `db:"end_year"`
+	Description  string        `db:"description"`
+	Image        string        `db:"image"`
+	DeletedAt    *time.Time    `db:"deleted_at"` // moved to the trash at, nil if not deleted
+	Events       []Event       `db:"events"`
+	Translations []Translation `db:"translations"` // name and description in other languages
+	ScoresCount  int           `db:"scores_count"`
+	EventsCount  int           `db:"events_count"`
 }
 
 // Event represents a historical event associated with a specific year.
 type Event struct {
-	EventID int       `db:"event_id"`
-	TopicID int       `db:"topic_id"`
-	Name    string    `db:"name"`
-	Year    int       `db:"year"`
-	Date    time.Time `db:"date"` // for sorting the events in chronological order, if 2 events have the same year
+	EventID      int           `db:"event_id"`
+	TopicID      int           `db:"topic_id"`
+	Name         string        `db:"name"`
+	Year         int           `db:"year"`
+	Date         time.Time     `db:"date"`         // for sorting the events in chronological order, if 2 events have the same year
+	Description  string        `db:"description"`  // optional context, shown after having answered a question
+	Source       string        `db:"source"`       // optional URL to a source or citation
+	Image        string        `db:"image"`        // optional URL to an image
+	DeletedAt    *time.Time    `db:"deleted_at"`   // moved to the trash at, nil if not deleted
+	Translations []Translation `db:"translations"` // name and description in other languages
 }
 
 // User represents a person's account.
 type User struct {
-	UserID      int    `db:"user_id"`
-	Username    string `db:"username"`
-	Email       string `db:"email"`
-	Password    string `db:"password"`
-	Admin       bool   `db:"admin"`
-	Verified    bool   `db:"verified"`
-	ScoresCount int    `db:"scores_count"`
+	UserID      int        `db:"user_id"`
+	Username    string     `db:"username"`
+	Email       string     `db:"email"`
+	Password    string     `db:"password"`
+	Admin       bool       `db:"admin"`
+	Verified    bool       `db:"verified"`
+	Locale      string     `db:"locale"`     // preferred language, empty if none
+	Class       string     `db:"class"`      // school class (e.g. "4a"), empty if none
+	DeletedAt   *time.Time `db:"deleted_at"` // moved to the trash at, nil if not deleted
+	ScoresCount int        `db:"scores_count"`
 }
 
 // Score represents points scored by a user upon having successfully finished
@@ -55,8 +67,41 @@ type Score struct {
 	Date      time.Time `db:"date"`
 	TopicName string    `db:"topic_name"`
 	UserName  string    `db:"user_name"`
+	Rank      int       `db:"ranking"` // rank within a leaderboard, whereas equal points share a rank
 }
 
+// TopicStats represents the statistics of a user in a topic, aggregated from
+// all scores of the user in that topic.
+type TopicStats struct {
+	TopicID       int     `db:"topic_id"`
+	TopicName     string  `db:"topic_name"`
+	AttemptsCount int     `db:"attempts_count"`
+	BestPoints    int     `db:"best_points"`
+	AveragePoints float64 `db:"average_points"`
+	Percentile    int     `db:"percentile"` // percentage of other players of the topic with lower best points
+	PlayersCount  int     `db:"players_count"`
+}
+
+// LeaderboardFilter holds the criteria of a leaderboard, whereas empty values
+// mean no restriction.
+type LeaderboardFilter struct {
+	Mode    string // one of the leaderboard modes, every score if empty
+	TopicID int
+	Class   string    // school class of the users
+	Since   time.Time // scores achieved at or after, zero for all-time
+
+	Limit  int
+	Offset int
+}
+
+// Modes of a leaderboard, which determine what a row of the leaderboard is.
+const (
+	LeaderboardAll    = "all"    // every score
+	LeaderboardBest   = "best"   // best score of every user per topic
+	LeaderboardTotal  = "total"  // sum of the best scores of every user across topics
+	LeaderboardRating = "rating" // current rating of every user, overall or in the topic
+)
+
 // Token represents a token to be sent to the user by email in case of a
 // forgotten password.
 type Token struct {
@@ -65,22 +110,157 @@ type Token struct {
 	Expiry  time.Time `db:"expiry"`
 }
 
+// AuditEntry represents an administrative or content change, such as
+// deleting a user or editing an event. Audit entries are append-only and
+// contain a snapshot of the target before and after the change as JSON.
+type AuditEntry struct {
+	AuditID    int       `db:"audit_id"`
+	UserID     int       `db:"user_id"`     // user that made the change
+	Action     string    `db:"action"`      // e.g. 'create', 'update', 'delete'
+	TargetType string    `db:"target_type"` // e.g. 'topic', 'event', 'user'
+	TargetID   int       `db:"target_id"`
+	Before     string    `db:"snapshot_before"` // JSON snapshot of the target before the change (empty when created)
+	After      string    `db:"snapshot_after"`  // JSON snapshot of the target after the change (empty when deleted)
+	IP         string    `db:"ip"`
+	Date       time.Time `db:"date"`
+	UserName   string    `db:"user_name"`
+}
+
+// AuditFilter represents the criteria for browsing the audit log. Empty
+// values don't get filtered by.
+type AuditFilter struct {
+	UserName   string
+	Action     string
+	TargetType string
+	TargetID   int
+	From       time.Time
+	To         time.Time
+
+	Limit  int
+	Offset int
+}
+
+// Revision represents a version of an event or topic, which gets recorded with
+// every change, in order to be able to trace and revert changes.
+type Revision struct {
+	RevisionID int       `db:"revision_id"`
+	TargetType string    `db:"target_type"` // 'topic' or 'event'
+	TargetID   int       `db:"target_id"`
+	UserID     int       `db:"user_id"`  // user that made the change
+	Snapshot   string    `db:"snapshot"` // JSON snapshot of the target after the change
+	Date       time.Time `db:"date"`
+	UserName   string    `db:"user_name"`
+}
+
+// Suggestion represents a correction of the year or date of an event, or a new
+// event, proposed by a user. It needs to be accepted by an admin to be
+// applied.
+type Suggestion struct {
+	SuggestionID int       `db:"suggestion_id"`
+	TopicID      int       `db:"topic_id"`
+	EventID      int       `db:"event_id"` // 0 for a new event
+	UserID       int       `db:"user_id"`
+	Name         string    `db:"name"` // name of a new event, empty for a correction
+	Year         int       `db:"year"`
+	EventDate    time.Time `db:"event_date"`
+	Comment      string    `db:"comment"` // optional explanation, e.g. a source
+	Status       string    `db:"status"`  // 'pending', 'accepted' or 'rejected'
+	Reason       string    `db:"reason"`  // reason for a rejection
+	Date         time.Time `db:"date"`
+	TopicName    string    `db:"topic_name"`
+	EventName    string    `db:"event_name"`
+	EventYear    int       `db:"event_year"` // current year of the event of a correction
+	UserName     string    `db:"user_name"`
+}
+
+// Tag represents a category of topics (e.g. "Antike"). A topic can have
+// multiple tags and a tag can belong to multiple topics.
+type Tag struct {
+	TagID       int    `db:"tag_id"`
+	Name        string `db:"name"`
+	TopicsCount int    `db:"topics_count"`
+}
+
+// Rating represents a skill rating after a quiz, similar to the Elo rating
+// system. It is either the overall rating of a user, the rating of a user in a
+// topic or the difficulty of a topic.
+type Rating struct {
+	RatingID  int       `db:"rating_id"`
+	UserID    int       `db:"user_id"`  // 0 for the difficulty of a topic
+	TopicID   int       `db:"topic_id"` // 0 for the overall rating of a user
+	Rating    int       `db:"rating"`
+	Date      time.Time `db:"date"`
+	TopicName string    `db:"topic_name"`
+}
+
+// Achievement represents an achievement, which a user unlocked after a quiz.
+type Achievement struct {
+	UserID int       `db:"user_id"`
+	Key    string    `db:"achievement"` // key of the rule, which unlocked the achievement
+	Date   time.Time `db:"date"`        // unlocked at
+}
+
+// Activity represents the activity of a user on a day.
+type Activity struct {
+	UserID    int       `db:"user_id"`
+	Day       time.Time `db:"day"`
+	Quizzes   int       `db:"quizzes"`   // amount of quizzes played
+	Practices int       `db:"practices"` // amount of practice sessions, such as flashcards and worksheets
+}
+
+// Challenge represents the quiz of a day, which consists of the same events
+// for every user.
+type Challenge struct {
+	Day          time.Time `db:"day"`
+	PlayersCount int       `db:"players_count"`
+	BestPoints   int       `db:"best_points"`
+}
+
+// ChallengeResult represents points scored by a user in the quiz of a day.
+type ChallengeResult struct {
+	UserID   int       `db:"user_id"`
+	Day      time.Time `db:"day"`
+	Points   int       `db:"points"`
+	Date     time.Time `db:"date"`
+	UserName string    `db:"user_name"`
+	Rank     int       `db:"ranking"` // rank within the leaderboard of the day, whereas equal points share a rank
+}
+
+// Translation represents the name and description of a topic or event in
+// another language than German (e.g. "en").
+type Translation struct {
+	ID          int    `db:"id"` // ID of the topic or event
+	Locale      string `db:"locale"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
+
 // TopicStore stores functions using topics for the database-layer.
 type TopicStore interface {
 	GetTopic(topicID int) (Topic, error)
 	GetTopics() ([]Topic, error)
 	CreateTopic(topic *Topic) error
+	CreateTopicWithEvents(topic *Topic) error
 	UpdateTopic(topic *Topic) error
+	MoveTopic(topicID int, parentID int) error
 	DeleteTopic(topicID int) error
+	GetDeletedTopics() ([]Topic, error)
+	RestoreTopic(topicID int) error
+	PurgeTopic(topicID int) error
 }
 
 // EventStore stores functions using events for the database-layer.
 type EventStore interface {
 	GetEvent(eventID int) (Event, error)
+	GetEventsByTopics(topicIDs []int) ([]Event, error)
 	CountEvents() (int, error)
 	CreateEvent(event *Event) error
+	CreateEvents(events []Event) error
 	UpdateEvent(event *Event) error
 	DeleteEvent(eventID int) error
+	GetDeletedEvents() ([]Event, error)
+	RestoreEvent(eventID int) error
+	PurgeEvent(eventID int) error
 }
 
 // UserStore stores functions using users for the database-layer.
@@ -88,19 +268,30 @@ type UserStore interface {
 	GetUser(userID int) (User, error)
 	GetUserByUsername(username string) (User, error)
 	GetUserByEmail(email string) (User, error)
+	IsUsernameTaken(username string) (bool, error)
+	IsEmailTaken(email string) (bool, error)
 	GetUsers() ([]User, error)
 	CountUsers() (int, error)
 	CreateUser(user *User) error
 	UpdateUser(user *User) error
+	UpdateUserLocale(userID int, locale string) error
+	UpdateUserClass(userID int, class string) error
+	GetClasses() ([]string, error)
 	DeleteUser(userID int) error
+	GetDeletedUsers() ([]User, error)
+	RestoreUser(userID int) error
+	PurgeUser(userID int) error
 }
 
 // ScoreStore stores functions using scores for the database-layer.
 type ScoreStore interface {
-	GetScores() ([]Score, error)
-	GetScoresByTopic(topicID int) ([]Score, error)
-	GetScoresByTopicAndUser(topicID int, userID int) ([]Score, error)
+	GetScoresByUser(userID int) ([]Score, error)
+	GetTopicStatsByUser(userID int) ([]TopicStats, error)
+	GetLeaderboard(filter LeaderboardFilter) ([]Score, error)
+	CountLeaderboard(filter LeaderboardFilter) (int, error)
 	CountScores() (int, error)
+	CountLowerScoresByTopic(topicID int, points int) (int, int, error)
+	CountTopicsByUser(userID int) (int, error)
 	CountScoresByDate(start time.Time, end time.Time) (int, error)
 	CreateScore(score *Score) error
 }
@@ -112,11 +303,120 @@ type TokenStore interface {
 	DeleteTokensByUser(userID int) error
 }
 
-// Store combines TopicStore, EventStore, UserStore and ScoreStore.
+// AuditStore stores functions using the audit log for the database-layer.
+// There are deliberately no functions to update or delete audit entries.
+type AuditStore interface {
+	GetAuditEntries(filter AuditFilter) ([]AuditEntry, error)
+	CountAuditEntries(filter AuditFilter) (int, error)
+	CreateAuditEntry(entry *AuditEntry) error
+}
+
+// RevisionStore stores functions using revisions of events and topics for the
+// database-layer.
+type RevisionStore interface {
+	GetRevision(revisionID int) (Revision, error)
+	GetRevisionsByTarget(targetType string, targetID int) ([]Revision, error)
+	CreateRevision(revision *Revision) error
+	IsImageReferenced(imageURL string) (bool, error)
+}
+
+// SuggestionStore stores functions using suggestions for the database-layer.
+type SuggestionStore interface {
+	GetSuggestion(suggestionID int) (Suggestion, error)
+	GetSuggestionsByStatus(status string) ([]Suggestion, error)
+	GetSuggestionsByUser(userID int) ([]Suggestion, error)
+	CountSuggestionsByStatus(status string) (int, error)
+	CreateSuggestion(suggestion *Suggestion) error
+	UpdateSuggestion(suggestion *Suggestion) error
+	AcceptSuggestion(suggestion *Suggestion, event *Event) error
+}
+
+// TagStore stores functions using tags for the database-layer.
+type TagStore interface {
+	GetTags() ([]Tag, error)
+	GetTagsByTopic(topicID int) ([]Tag, error)
+	GetTagsOfTopics() (map[int][]Tag, error)
+	UpdateTopicTags(topicID int, names []string) error
+}
+
+// TranslationStore stores functions using translations of topics and events
+// for the database-layer.
+type TranslationStore interface {
+	GetTopicTranslations(topicID int) ([]Translation, error)
+	GetTopicTranslationsByLocale(locale string) (map[int]Translation, error)
+	SaveTopicTranslation(translation *Translation) error
+	DeleteTopicTranslation(topicID int, locale string) error
+	GetEventTranslations(eventID int) ([]Translation, error)
+	GetEventTranslationsByTopics(topicIDs []int, locale string) (map[int]Translation, error)
+	SaveEventTranslation(translation *Translation) error
+	DeleteEventTranslation(eventID int, locale string) error
+}
+
+// BlobStore stores functions using uploaded files, such as images of topics,
+// for the storage-layer.
+type BlobStore interface {
+	SaveBlob(name string, content []byte) (string, error)
+	DeleteBlob(url string) error
+	OwnsBlob(url string) bool
+}
+
+// RatingStore stores functions using ratings of users and difficulties of
+// topics for the database-layer.
+type RatingStore interface {
+	GetRatingsByUser(userID int) ([]Rating, error)
+	CreateRatedScore(score *Score, rate ScoreRater) error
+}
+
+// ScoreRater calculates the new ratings after a score, based on the current
+// overall rating of the user, the user's rating in the topic and the
+// difficulty of the topic, whereas 0 stands for no rating yet.
+type ScoreRater func(rating int, topicRating int, difficulty int) []Rating
+
+// AchievementStore stores functions using achievements of users for the
+// database-layer.
+type AchievementStore interface {
+	GetAchievementsByUser(userID int) ([]Achievement, error)
+	CreateAchievements(achievements []Achievement) error
+}
+
+// ActivityStore stores functions using the daily activity of users for the
+// database-layer.
+type ActivityStore interface {
+	GetActivitiesByUser(userID int) ([]Activity, error)
+	AddActivity(activity *Activity) error
+}
+
+// ChallengeStore stores functions using the quiz of the day for the
+// database-layer.
+type ChallengeStore interface {
+	GetChallenges() ([]Challenge, error)
+	GetChallengeEvents(day time.Time) ([]Event, error)
+	CreateChallenge(day time.Time, eventIDs []int) error
+	GetChallengeLeaderboard(day time.Time) ([]ChallengeResult, error)
+	HasPlayedChallenge(userID int, day time.Time) (bool, error)
+	CreateChallengeResult(result *ChallengeResult) error
+}
+
+// ErrChallengePlayed is returned when creating the result of a user in the
+// challenge of a day, which the user has already played.
+var ErrChallengePlayed = errors.New("challenge has already been played")
+
+// Store combines TopicStore, EventStore, UserStore, ScoreStore, TokenStore,
+// AuditStore, RevisionStore, SuggestionStore, TagStore, TranslationStore,
+// RatingStore, AchievementStore, ActivityStore and ChallengeStore.
 type Store interface {
 	TopicStore
 	EventStore
 	UserStore
 	ScoreStore
 	TokenStore
+	AuditStore
+	RevisionStore
+	SuggestionStore
+	TagStore
+	TranslationStore
+	RatingStore
+	AchievementStore
+	ActivityStore
+	ChallengeStore
 }
@@ -0,0 +1,73 @@
+// The blob store saving uploaded files, such as images of topics, on the local
+// file system. The files get saved in a directory of the static files, from
+// where they get served by the static file handler.
+
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// NewLocalStore initializes a new blob store in a certain directory, which
+// gets created if it doesn't exist yet. The URL is the path under which the
+// directory is served (e.g. '/frontend/static/uploads').
+func NewLocalStore(dir string, url string) (*LocalStore, error) {
+
+	// Create directory, if it doesn't exist yet
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory for uploads: %w", err)
+	}
+
+	return &LocalStore{
+		Dir: dir,
+		URL: strings.TrimSuffix(url, "/"),
+	}, nil
+}
+
+// LocalStore is the local file system access object.
+type LocalStore struct {
+	Dir string
+	URL string
+}
+
+// SaveBlob saves a file with a certain name and returns its URL.
+func (store *LocalStore) SaveBlob(name string, content []byte) (string, error) {
+
+	// Only allow plain filenames, so that no file outside of the directory
+	// can be overwritten
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("error saving file: invalid name '%v'", name)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(store.Dir, name), content, 0644); err != nil {
+		return "", fmt.Errorf("error saving file: %w", err)
+	}
+
+	return store.URL + "/" + name, nil
+}
+
+// DeleteBlob deletes a file by its URL. URLs of files that weren't saved by
+// this store (e.g. external images) get ignored.
+func (store *LocalStore) DeleteBlob(url string) error {
+
+	if !store.OwnsBlob(url) {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(store.Dir, path.Base(url)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting file: %w", err)
+	}
+
+	return nil
+}
+
+// OwnsBlob checks whether a URL belongs to a file saved by this store.
+func (store *LocalStore) OwnsBlob(url string) bool {
+	return strings.HasPrefix(url, store.URL+"/") && path.Base(url) != "" && !strings.Contains(url, "..")
+}
@@ -0,0 +1,133 @@
+// Collection of tests for the blob store saving files on the local file
+// system.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSaveBlob tests saving a file.
+func TestSaveBlob(t *testing.T) {
+
+	// New temporary directory
+	dir, err := ioutil.TempDir("", "uploads")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(dir, "/uploads/")
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		blobName  string
+		wantURL   string
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name:     "#1 OK",
+			blobName: "image.png",
+			wantURL:  "/uploads/image.png",
+		},
+		{
+			// When the name tries to escape the directory
+			name:      "#2 PATH TRAVERSAL",
+			blobName:  "../image.png",
+			wantError: true,
+		},
+		{
+			// When the name is missing
+			name:      "#3 NAME MISSING",
+			blobName:  "",
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			url, err := store.SaveBlob(test.blobName, []byte("content"))
+
+			if (err != nil) != test.wantError {
+				t.Errorf("SaveBlob() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil {
+				if url != test.wantURL {
+					t.Errorf("SaveBlob() = %v, want %v", url, test.wantURL)
+				}
+				if _, err = os.Stat(filepath.Join(dir, test.blobName)); err != nil {
+					t.Errorf("SaveBlob() file wasn't saved: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// TestDeleteBlob tests deleting a file by its URL.
+func TestDeleteBlob(t *testing.T) {
+
+	// New temporary directory
+	dir, err := ioutil.TempDir("", "uploads")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(dir, "/uploads")
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	if _, err = store.SaveBlob("image.png", []byte("content")); err != nil {
+		t.Fatalf("SaveBlob() error = %v", err)
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		url       string
+		wantError bool
+	}{
+		{
+			// When everything works as intended
+			name: "#1 OK",
+			url:  "/uploads/image.png",
+		},
+		{
+			// When the file doesn't exist (anymore)
+			name: "#2 NOT FOUND",
+			url:  "/uploads/image.png",
+		},
+		{
+			// When the URL belongs to an external image
+			name: "#3 EXTERNAL",
+			url:  "https://image.png",
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			err := store.DeleteBlob(test.url)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("DeleteBlob() error = %v, want error %v", err, test.wantError)
+			}
+		})
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "image.png")); !os.IsNotExist(err) {
+		t.Errorf("DeleteBlob() file wasn't deleted")
+	}
+}
@@ -0,0 +1,183 @@
+// Achievements, which users unlock after a quiz, in order to keep them
+// playing. Every achievement has a rule, which gets evaluated against the
+// progress of the user after every finished quiz. Unlocked achievements are
+// stored with their unlock date and never get locked again.
+
+package web
+
+import (
+	"fmt"
+	"time"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	achievementTopics = 10 // amount of different topics to be played for 'topics'
+	achievementStreak = 7  // amount of consecutive days to be played for 'streak'
+)
+
+// achievements are all achievements, which can be unlocked, in the order they
+// are displayed.
+var achievements = []achievement{
+	{
+		Key:         "perfect_phase3",
+		Name:        "Perfektionist",
+		Description: "Alle Ereignisse in Phase 3 richtig geordnet.",
+		unlocked: func(progress achievementProgress) bool {
+			return progress.PerfectPhase3
+		},
+	},
+	{
+		Key:         "topics",
+		Name:        "Weltenbummler",
+		Description: fmt.Sprintf("%v verschiedene Themen gespielt.", achievementTopics),
+		unlocked: func(progress achievementProgress) bool {
+			return progress.TopicsCount >= achievementTopics
+		},
+	},
+	{
+		Key:         "streak",
+		Name:        "Dranbleiber",
+		Description: fmt.Sprintf("An %v Tagen in Folge ein Quiz gespielt.", achievementStreak),
+		unlocked: func(progress achievementProgress) bool {
+			return progress.Streak >= achievementStreak
+		},
+	},
+	{
+		Key:         "first_place",
+		Name:        "Spitzenreiter",
+		Description: "Den ersten Platz im Leaderboard eines Themas erreicht.",
+		unlocked: func(progress achievementProgress) bool {
+			return progress.FirstPlace
+		},
+	},
+}
+
+// achievement is an achievement with the rule to unlock it.
+type achievement struct {
+	Key         string // key, which is stored in the database
+	Name        string
+	Description string
+	unlocked    func(progress achievementProgress) bool
+}
+
+// achievementProgress is the progress of a user after a quiz, against which
+// the rules of the achievements get evaluated.
+type achievementProgress struct {
+	PerfectPhase3 bool // whether every event of phase 3 was ordered correctly
+	TopicsCount   int  // amount of different topics played
+	Streak        int  // amount of consecutive days played, up to today
+	FirstPlace    bool // whether the user is first on the leaderboard of the topic
+}
+
+// achievementStatus is an achievement on the profile of a user, which is
+// either unlocked or still locked.
+type achievementStatus struct {
+	achievement
+	Unlocked bool
+	Date     time.Time // unlocked at
+}
+
+// unlockAchievements evaluates the rules of all achievements, which a user
+// hasn't unlocked yet, after a quiz in a topic and stores the newly unlocked
+// ones, which get returned.
+func unlockAchievements(store x.Store, userID int, topicID int, perfectPhase3 bool,
+	date time.Time) ([]achievement, error) {
+
+	// Execute SQL statement to get the user's achievements so far
+	unlocked, err := store.GetAchievementsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute SQL statements to get the user's progress
+	progress := achievementProgress{PerfectPhase3: perfectPhase3}
+	if progress.TopicsCount, err = store.CountTopicsByUser(userID); err != nil {
+		return nil, err
+	}
+	activities, err := store.GetActivitiesByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	progress.Streak = currentStreak(activeDays(activities), date)
+	leaderboard, err := store.GetLeaderboard(x.LeaderboardFilter{
+		Mode:    x.LeaderboardBest,
+		TopicID: topicID,
+		Limit:   1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	progress.FirstPlace = len(leaderboard) > 0 && leaderboard[0].UserID == userID
+
+	newAchievements := evaluateAchievements(progress, unlocked)
+	if len(newAchievements) == 0 {
+		return nil, nil
+	}
+
+	// Execute SQL statement to store the newly unlocked achievements
+	var rows []x.Achievement
+	for _, newAchievement := range newAchievements {
+		rows = append(rows, x.Achievement{UserID: userID, Key: newAchievement.Key, Date: date})
+	}
+	if err = store.CreateAchievements(rows); err != nil {
+		return nil, err
+	}
+
+	return newAchievements, nil
+}
+
+// evaluateAchievements evaluates the rules of all achievements, which haven't
+// been unlocked yet, and returns the ones, which are now unlocked.
+// (Tested in handler_test.go)
+func evaluateAchievements(progress achievementProgress, unlocked []x.Achievement) []achievement {
+	var newAchievements []achievement
+
+	unlockedKeys := make(map[string]bool)
+	for _, a := range unlocked {
+		unlockedKeys[a.Key] = true
+	}
+
+	for _, a := range achievements {
+		if !unlockedKeys[a.Key] && a.unlocked(progress) {
+			newAchievements = append(newAchievements, a)
+		}
+	}
+
+	return newAchievements
+}
+
+// achievementStatuses combines all achievements with the ones a user has
+// unlocked, in order to display them on the profile.
+// (Tested in handler_test.go)
+func achievementStatuses(unlocked []x.Achievement) []achievementStatus {
+	var statuses []achievementStatus
+
+	dates := make(map[string]time.Time)
+	for _, a := range unlocked {
+		dates[a.Key] = a.Date
+	}
+
+	for _, a := range achievements {
+		date, ok := dates[a.Key]
+		statuses = append(statuses, achievementStatus{
+			achievement: a,
+			Unlocked:    ok,
+			Date:        date,
+		})
+	}
+
+	return statuses
+}
+
+// achievementsFlash creates the flash message announcing newly unlocked
+// achievements.
+func achievementsFlash(newAchievements []string) string {
+	if len(newAchievements) == 1 {
+		return fmt.Sprintf("Neue Auszeichnung freigeschaltet: %v", newAchievements[0])
+	}
+
+	return fmt.Sprintf("%v neue Auszeichnungen freigeschaltet. Sie finden sie in Ihrem Profil.",
+		len(newAchievements))
+}
@@ -0,0 +1,149 @@
+// Daily activity of users, being the quizzes played and practice sessions
+// (flashcards and worksheets) per day, from which streaks of consecutive days
+// and a heatmap of the past year, similar to the one on GitHub, get created.
+
+package web
+
+import (
+	"time"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const heatmapWeeks = 53 // amount of weeks (columns) of the activity heatmap
+
+// heatmapDay is a cell of the activity heatmap.
+type heatmapDay struct {
+	Date      time.Time
+	Quizzes   int
+	Practices int
+	Level     int  // intensity of the color from 0 (no activity) to 4
+	Future    bool // whether the day is after today, thus not shown
+}
+
+// activityLevel converts the amount of activities on a day into the intensity
+// of its color on the heatmap.
+// (Tested in handler_test.go)
+func activityLevel(count int) int {
+	switch {
+	case count <= 0:
+		return 0
+	case count == 1:
+		return 1
+	case count <= 3:
+		return 2
+	case count <= 5:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// activityHeatmap creates the heatmap of the past year up to today out of the
+// activities of a user, as weeks (columns) from Monday to Sunday (rows).
+// (Tested in handler_test.go)
+func activityHeatmap(activities []x.Activity, today time.Time) [][]heatmapDay {
+
+	days := make(map[time.Time]x.Activity)
+	for _, activity := range activities {
+		days[dateOf(activity.Day)] = activity
+	}
+
+	// The last column is the current week, starting on Monday
+	today = dateOf(today)
+	weekday := (int(today.Weekday()) + 6) % 7 // 0 = Monday
+	start := today.AddDate(0, 0, -weekday-7*(heatmapWeeks-1))
+
+	heatmap := make([][]heatmapDay, heatmapWeeks)
+	for week := range heatmap {
+		heatmap[week] = make([]heatmapDay, 7)
+		for day := range heatmap[week] {
+			date := start.AddDate(0, 0, week*7+day)
+			activity := days[date]
+			heatmap[week][day] = heatmapDay{
+				Date:      date,
+				Quizzes:   activity.Quizzes,
+				Practices: activity.Practices,
+				Level:     activityLevel(activity.Quizzes + activity.Practices),
+				Future:    date.After(today),
+			}
+		}
+	}
+
+	return heatmap
+}
+
+// heatmapActiveDays counts the days with any activity on the heatmap.
+func heatmapActiveDays(heatmap [][]heatmapDay) int {
+	var count int
+
+	for _, week := range heatmap {
+		for _, day := range week {
+			if day.Level > 0 {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// activeDays converts the activities of a user, sorted by day descending,
+// into the days with any activity.
+func activeDays(activities []x.Activity) []time.Time {
+	var days []time.Time
+
+	for _, activity := range activities {
+		if activity.Quizzes+activity.Practices > 0 {
+			days = append(days, activity.Day)
+		}
+	}
+
+	return days
+}
+
+// currentStreak calculates the amount of consecutive days played up to today,
+// given the days played sorted by date descending. A streak, which ended
+// yesterday, still counts, since the user may still play today.
+// (Tested in handler_test.go)
+func currentStreak(days []time.Time, today time.Time) int {
+
+	// Compare calendar days only, regardless of time and time zone
+	day := dateOf(today)
+	if len(days) == 0 || dateOf(days[0]).Before(day.AddDate(0, 0, -1)) {
+		return 0
+	}
+
+	streak := 1
+	for i := 1; i < len(days); i++ {
+		if !dateOf(days[i]).Equal(dateOf(days[i-1]).AddDate(0, 0, -1)) {
+			break
+		}
+		streak++
+	}
+
+	return streak
+}
+
+// longestStreak calculates the highest amount of consecutive days played ever,
+// given the days played sorted by date descending.
+// (Tested in handler_test.go)
+func longestStreak(days []time.Time) int {
+	var longest, streak int
+
+	for i := range days {
+		if i > 0 && dateOf(days[i]).Equal(dateOf(days[i-1]).AddDate(0, 0, -1)) {
+			streak++
+		} else {
+			streak = 1
+		}
+		longest = max(longest, streak)
+	}
+
+	return longest
+}
+
+// dateOf gets the calendar day of a time as midnight UTC.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,321 @@
+// The web handler evolving around the audit log, with HTTP-handler functions
+// consisting of "GET"-methods. It utilizes session management and database
+// access.
+//
+// Every mutating admin action (e.g. editing an event or promoting a user) gets
+// recorded in the audit log, including a JSON-snapshot of the target before
+// and after the change, so that admins can later on trace who changed what.
+
+package web
+
+import (
+	"encoding/json"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/gorilla/csrf"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	auditShow = 25 // amount of audit entries per page
+
+	// Actions of audit entries
+	auditCreate    = "create"
+	auditUpdate    = "update"
+	auditDelete    = "delete"
+	auditImport    = "import"
+	auditPromote   = "promote"
+	auditRestore   = "restore"
+	auditPurge     = "purge"
+	auditRevert    = "revert"
+	auditTranslate = "translate"
+
+	// Types of targets of audit entries
+	auditTopic = "topic"
+	auditEvent = "event"
+	auditUser  = "user"
+)
+
+var (
+	// Parsed HTML-templates to be executed in their respective HTTP-handler
+	// functions when needed
+	auditListTemplate *template.Template
+
+	// Labels of actions and types of targets to be displayed
+	auditActions = map[string]string{
+		auditCreate:    "Erstellt",
+		auditUpdate:    "Bearbeitet",
+		auditDelete:    "Gelöscht",
+		auditImport:    "Importiert",
+		auditPromote:   "Befördert",
+		auditRestore:   "Wiederhergestellt",
+		auditPurge:     "Endgültig gelöscht",
+		auditRevert:    "Zurückgesetzt",
+		auditTranslate: "Übersetzt",
+	}
+	auditTargetTypes = map[string]string{
+		auditTopic: "Thema",
+		auditEvent: "Ereignis",
+		auditUser:  "Benutzer",
+	}
+)
+
+// init gets initialized with the package.
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
+func init() {
+	if _testing { // skip initialization of templates when running tests
+		return
+	}
+
+	auditListTemplate = template.Must(newTemplate().
+		Funcs(template.FuncMap{
+			"increment": func(num int) int {
+				return num + 1
+			},
+			"decrement": func(num int) int {
+				return num - 1
+			},
+			"label": func(labels map[string]string, key string) string {
+				if label, ok := labels[key]; ok {
+					return label
+				}
+				return key
+			},
+		}).
+		ParseFiles(layout, templatePath+"audit_list.html"))
+}
+
+// AuditHandler is the object for handlers to access sessions and database.
+type AuditHandler struct {
+	store    x.Store
+	sessions *scs.SessionManager
+}
+
+// List is a GET-method that is accessible to any admin.
+//
+// It lists the audit entries, sorted by date descending, with the ability to
+// filter them by user, action, type and ID of the target and a range of dates.
+func (h *AuditHandler) List() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Entries     []x.AuditEntry
+		Filter      auditFilterForm
+		Query       template.URL // URL query of the filter, for navigating between pages
+		Actions     map[string]string
+		TargetTypes map[string]string
+
+		ShowFrom int // first entry's number
+		ShowTo   int // last entry's number
+		ShowOf   int // total amount of entries
+
+		Page         int   // current page
+		Pages        []int // range of pages to be able to navigate to
+		PagePrevious bool  // whether there's a previous page
+		PageNext     bool  // whether there's a next page
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve values from URL query for filtering the audit log
+		form, filter := parseAuditFilter(req.URL.Query())
+
+		// Execute SQL statement to get amount of audit entries
+		entriesCount, err := h.store.CountAuditEntries(filter)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Calculate offset of the current page
+		_, page := inspectFilters(strconv.Itoa(auditShow), req.URL.Query().Get("page"), entriesCount)
+		filter.Limit = auditShow
+		filter.Offset = auditShow * (page - 1)
+
+		// Execute SQL statement to get audit entries of the current page
+		entries, err := h.store.GetAuditEntries(filter)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Page numbers to be shown below the audit log in order to navigate
+		// to different pages
+		pages := createPages(auditShow, page, entriesCount)
+		if len(pages) == 0 {
+			pages = []int{1}
+		}
+
+		// Execute HTML-templates with data
+		if err = auditListTemplate.Execute(res, data{
+			SessionData:  GetSessionData(h.sessions, req.Context()),
+			CSRF:         csrf.TemplateField(req),
+			Entries:      entries,
+			Filter:       form,
+			Query:        template.URL(form.query()),
+			Actions:      auditActions,
+			TargetTypes:  auditTargetTypes,
+			ShowFrom:     min(filter.Offset+1, entriesCount),
+			ShowTo:       filter.Offset + len(entries),
+			ShowOf:       entriesCount,
+			Page:         page,
+			Pages:        pages,
+			PagePrevious: page != pages[0],
+			PageNext:     page != pages[len(pages)-1],
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// auditFilterForm holds the values of the filter of the audit log, as entered
+// in the form.
+type auditFilterForm struct {
+	User     string
+	Action   string
+	Type     string
+	TargetID string
+	From     string // formatted as 'yyyy-mm-dd'
+	To       string // formatted as 'yyyy-mm-dd'
+}
+
+// query converts the filter back to a URL query.
+func (form auditFilterForm) query() string {
+
+	values := neturl.Values{}
+	for key, value := range map[string]string{
+		"user":   form.User,
+		"action": form.Action,
+		"type":   form.Type,
+		"target": form.TargetID,
+		"from":   form.From,
+		"to":     form.To,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	return values.Encode()
+}
+
+// parseAuditFilter converts the URL query into a filter of the audit log,
+// ignoring invalid values. The end date is inclusive.
+// (Tested in handler_test.go)
+func parseAuditFilter(query neturl.Values) (auditFilterForm, x.AuditFilter) {
+	var form auditFilterForm
+	var filter x.AuditFilter
+
+	form.User = strings.TrimSpace(query.Get("user"))
+	filter.UserName = form.User
+
+	if _, ok := auditActions[query.Get("action")]; ok {
+		form.Action = query.Get("action")
+		filter.Action = form.Action
+	}
+
+	if _, ok := auditTargetTypes[query.Get("type")]; ok {
+		form.Type = query.Get("type")
+		filter.TargetType = form.Type
+	}
+
+	if targetID, err := strconv.Atoi(query.Get("target")); err == nil && targetID > 0 {
+		form.TargetID = strconv.Itoa(targetID)
+		filter.TargetID = targetID
+	}
+
+	if from, err := time.Parse("2006-01-02", query.Get("from")); err == nil {
+		form.From = query.Get("from")
+		filter.From = from
+	}
+
+	if to, err := time.Parse("2006-01-02", query.Get("to")); err == nil {
+		form.To = query.Get("to")
+		filter.To = to.AddDate(0, 0, 1) // include the whole day
+	}
+
+	return form, filter
+}
+
+// audit records a mutating admin action in the audit log. The snapshots of
+// the target before and after the change may be nil. An error doesn't abort
+// the action, since it has already been executed, but gets logged.
+func audit(store x.AuditStore, req *http.Request, action string, targetType string, targetID int,
+	before interface{}, after interface{}) {
+
+	entry := x.AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     auditSnapshot(before),
+		After:      auditSnapshot(after),
+		IP:         clientIP(req, os.Getenv("ENVIRONMENT") == "production"),
+		Date:       time.Now(),
+	}
+	if user, ok := req.Context().Value("user").(x.User); ok {
+		entry.UserID = user.UserID
+	}
+
+	if err := store.CreateAuditEntry(&entry); err != nil {
+		log.Printf("error recording %v of %v %v in audit log: %v", action, targetType, targetID, err)
+	}
+}
+
+// auditSnapshot converts the target of an audit entry to JSON. Passwords of
+// users get omitted.
+// (Tested in handler_test.go)
+func auditSnapshot(target interface{}) string {
+
+	if target == nil {
+		return ""
+	}
+	if user, ok := target.(x.User); ok {
+		user.Password = ""
+		target = user
+	}
+
+	snapshot, err := json.Marshal(target)
+	if err != nil {
+		log.Printf("error creating snapshot for audit log: %v", err)
+		return ""
+	}
+
+	return string(snapshot)
+}
+
+// clientIP returns the IP-address of the client of a request. Behind a trusted
+// proxy (e.g. the router of Heroku in production) the original address is the
+// last of the header 'X-Forwarded-For', since the proxy appends it, whereas
+// any entries before it may be forged by the client. Without a trusted proxy
+// the header is ignored altogether.
+// (Tested in handler_test.go)
+func clientIP(req *http.Request, trustProxy bool) string {
+
+	if forwarded := req.Header.Get("X-Forwarded-For"); trustProxy && forwarded != "" {
+		addresses := strings.Split(forwarded, ",")
+		return strings.TrimSpace(addresses[len(addresses)-1])
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,514 @@
+// The web handler evolving around the quiz of the day, with HTTP-handler
+// functions consisting of "GET"- and "POST"-methods. It utilizes session
+// management and database access.
+//
+// The quiz of the day consists of events of all topics, where the user has to
+// guess the exact year of every event. The events get chosen with a seed
+// derived from the date, so that every user gets the same events on a day.
+// They get stored on the first request of the day, which keeps past challenges
+// unchanged in the archive. Every user can play the quiz of a day once and
+// gets ranked in the leaderboard of that day.
+
+package web
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/csrf"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	challengeQuestions = 8            // amount of events in the quiz of the day
+	challengeDayFormat = "2006-01-02" // format of the day in the URL of a challenge
+)
+
+var (
+	// Parsed HTML-templates to be executed in their respective HTTP-handler
+	// functions when needed
+	challengePlayTemplate, challengeShowTemplate, challengeArchiveTemplate *template.Template
+)
+
+// init gets initialized with the package.
+//
+// It registers the challenge data to the session.
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
+func init() {
+	gob.Register(ChallengeData{})
+
+	if _testing { // skip initialization of templates when running tests
+		return
+	}
+
+	challengePlayTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"challenge_play.html"))
+	challengeShowTemplate = parseReviewTemplate("challenge_show.html")
+	challengeArchiveTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"challenge_archive.html"))
+}
+
+// ChallengeHandler is the object for handlers to access sessions and database.
+type ChallengeHandler struct {
+	store    x.Store
+	sessions *scs.SessionManager
+}
+
+// ChallengeData contains the questions of the quiz of a day and the points
+// of the user, as well as the day and time stamp in order to validate the
+// submission.
+type ChallengeData struct {
+	Day       time.Time
+	Questions []phase2Question
+	Points    int
+	Submitted bool      // whether the guesses were submitted, in order to show them in the review
+	TimeStamp time.Time // ensures a user can't submit the quiz after n minutes
+}
+
+// Play is a GET-method that is accessible to any user, who hasn't played the
+// quiz of the day yet.
+//
+// It consists of a form with 8 questions, where the user has to guess the
+// exact year of a given event.
+func (h *ChallengeHandler) Play() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Day       time.Time
+		Questions []phase2Question
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve user from session
+		user := req.Context().Value("user").(x.User)
+
+		day := challengeDay(time.Now())
+
+		// Execute SQL statement to check whether the user has already played
+		// the quiz of the day
+		played, err := h.store.HasPlayedChallenge(user.UserID, day)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if played {
+			h.sessions.Put(req.Context(), "flash_info",
+				"Sie haben das Quiz des Tages bereits gespielt. Morgen gibt es ein neues.")
+			http.Redirect(res, req, "/challenge/"+day.Format(challengeDayFormat), http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statements to get the events of the quiz of the day
+		events, err := challengeEvents(h.store, day)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Check if there is a quiz of the day
+		if len(events) == 0 {
+			h.sessions.Put(req.Context(), "flash_error",
+				"Es gibt noch nicht genügend Ereignisse für das Quiz des Tages.")
+			http.Redirect(res, req, "/", http.StatusSeeOther)
+			return
+		}
+
+		// Localize events to the language of the user
+		if events, err = withEventTranslations(h.store, events, localeOf(req.Context())); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		challenge := ChallengeData{
+			Day:       day,
+			Questions: createChallengeQuestions(events),
+			TimeStamp: time.Now(),
+		}
+
+		// Pass challenge data to session
+		h.sessions.Put(req.Context(), "challenge", challenge)
+
+		// Execute HTML-templates with data
+		if err = challengePlayTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
+			Day:         day,
+			Questions:   challenge.Questions,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// PlaySubmit is a POST-method that is accessible to any user after Play.
+//
+// It calculates the points, stores the result and redirects to Show.
+func (h *ChallengeHandler) PlaySubmit() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve challenge data from session
+		challenge, ok := h.sessions.Get(req.Context(), "challenge").(ChallengeData)
+
+		// Validate the challenge data, so that the user can't submit the quiz
+		// of another day or submit it again after having seen the solutions
+		msg := challenge.validate(ok, challengeDay(time.Now()))
+
+		// If 'msg' isn't empty, an error occurred
+		if msg != "" {
+			h.sessions.Put(req.Context(), "flash_error", msg)
+			http.Redirect(res, req, "/challenge", http.StatusSeeOther)
+			return
+		}
+
+		// Retrieve user from session
+		user := req.Context().Value("user").(x.User)
+
+		// Execute SQL statement to check whether the user has already played
+		// the quiz of the day, e.g. in another browser
+		played, err := h.store.HasPlayedChallenge(user.UserID, challenge.Day)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if played {
+			h.sessions.Put(req.Context(), "flash_info",
+				"Sie haben das Quiz des Tages bereits gespielt. Morgen gibt es ein neues.")
+			http.Redirect(res, req, "/challenge/"+challenge.Day.Format(challengeDayFormat), http.StatusSeeOther)
+			return
+		}
+
+		// Update challenge data
+		challenge.Submitted = true
+		challenge.TimeStamp = time.Now()
+
+		// Loop through the input fields to calculate the points
+		for num := range challenge.Questions {
+			challenge.Questions[num].UserGuess, _ = strconv.Atoi(req.FormValue(strconv.Itoa(num)))
+			challenge.Points += yearGuessPoints(challenge.Questions[num].EventYear,
+				challenge.Questions[num].UserGuess)
+		}
+
+		// Add result of the quiz of the day to database, unless it has been
+		// submitted simultaneously, e.g. in another tab
+		err = h.store.CreateChallengeResult(&x.ChallengeResult{
+			UserID: user.UserID,
+			Day:    challenge.Day,
+			Points: challenge.Points,
+			Date:   challenge.TimeStamp,
+		})
+		if errors.Is(err, x.ErrChallengePlayed) {
+			h.sessions.Put(req.Context(), "flash_info",
+				"Sie haben das Quiz des Tages bereits gespielt. Morgen gibt es ein neues.")
+			http.Redirect(res, req, "/challenge/"+challenge.Day.Format(challengeDayFormat), http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Add quiz to the user's activity of the day, which isn't essential
+		// for the quiz, so that a failure only gets logged
+		if err = h.store.AddActivity(&x.Activity{
+			UserID:  user.UserID,
+			Day:     challenge.TimeStamp,
+			Quizzes: 1,
+		}); err != nil {
+			log.Printf("error adding quiz of the day to activity of user %v: %v", user.UserID, err)
+		}
+
+		// Pass challenge data to session again, in order to show the guesses
+		// in the review
+		h.sessions.Put(req.Context(), "challenge", challenge)
+
+		h.sessions.Put(req.Context(), "flash_success",
+			fmt.Sprintf("Sie haben im Quiz des Tages %v Punkte erreicht.", challenge.Points))
+
+		// Redirect to the leaderboard of the day
+		http.Redirect(res, req, "/challenge/"+challenge.Day.Format(challengeDayFormat), http.StatusSeeOther)
+	}
+}
+
+// Show is a GET-method that is accessible to any user.
+//
+// It displays the leaderboard of the quiz of a day and the solutions, which
+// are only shown for today's quiz after having played it.
+func (h *ChallengeHandler) Show() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+
+		Day         time.Time
+		Today       bool
+		Result      x.ChallengeResult // result of the user, empty if not played
+		Leaderboard []x.ChallengeResult
+		Questions   []phase2Question
+		Guessed     bool // whether the questions contain the user's guesses
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve day from URL parameters
+		day, err := time.Parse(challengeDayFormat, chi.URLParam(req, "day"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Retrieve user from session
+		user := req.Context().Value("user").(x.User)
+
+		// Check if the day is in the future
+		today := challengeDay(time.Now())
+		if day.After(today) {
+			http.Error(res, "Das Quiz dieses Tages ist noch nicht verfügbar.", http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get the leaderboard of the day
+		leaderboard, err := h.store.GetChallengeLeaderboard(day)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var result x.ChallengeResult
+		for _, r := range leaderboard {
+			if r.UserID == user.UserID {
+				result = r
+			}
+		}
+
+		// Redirect to today's quiz, if the user hasn't played it yet, so that
+		// the solutions don't get revealed beforehand
+		if day.Equal(today) && result.UserID == 0 {
+			http.Redirect(res, req, "/challenge", http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to get the events of the quiz of the day
+		events, err := h.store.GetChallengeEvents(day)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(events) == 0 {
+			http.Error(res, "Für diesen Tag gibt es kein Quiz des Tages.", http.StatusNotFound)
+			return
+		}
+
+		// Localize events to the language of the user
+		if events, err = withEventTranslations(h.store, events, localeOf(req.Context())); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Show the user's guesses, if the quiz of that day was just played
+		questions := createChallengeQuestions(events)
+		challenge, ok := h.sessions.Get(req.Context(), "challenge").(ChallengeData)
+		guessed := ok && challenge.Submitted && challenge.Day.Equal(day)
+		if guessed {
+			questions = challenge.Questions
+		}
+
+		// Execute HTML-templates with data
+		if err = challengeShowTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			Day:         day,
+			Today:       day.Equal(today),
+			Result:      result,
+			Leaderboard: leaderboard,
+			Questions:   questions,
+			Guessed:     guessed,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Archive is a GET-method that is accessible to any user.
+//
+// It displays a list of all past quizzes of the day.
+func (h *ChallengeHandler) Archive() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+
+		Today      time.Time
+		Challenges []x.Challenge
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Execute SQL statement to get all quizzes of the day
+		challenges, err := h.store.GetChallenges()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute HTML-templates with data
+		if err = challengeArchiveTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			Today:       challengeDay(time.Now()),
+			Challenges:  challenges,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// validate validates the challenge data upon submitting the quiz of a day. It
+// returns an error message if the challenge data is invalid.
+func (challenge ChallengeData) validate(ok bool, today time.Time) string {
+
+	msg := "Ein Fehler ist aufgetreten im Quiz des Tages. "
+
+	// Check for invalid conversion from interface to challenge-data struct
+	if !ok {
+		// Occurs when a user submits the form without having started the
+		// quiz of the day
+		return msg + "Bitte starten Sie das Quiz des Tages nur über die Seitenleiste."
+	}
+
+	// Check for submitted challenge data
+	if challenge.Submitted {
+		// Occurs when a user goes back after having seen the solutions
+		return msg + "Womöglich haben Sie versucht, das Quiz des Tages zu wiederholen."
+	}
+
+	// Check for invalid day
+	if !challenge.Day.Equal(today) {
+		// Occurs when a user starts the quiz of a day before midnight and
+		// submits it afterwards
+		return msg + "Das Quiz des Tages ist inzwischen abgelaufen."
+	}
+
+	// Check for invalid time stamp
+	if time.Now().After(challenge.TimeStamp.Add(time.Minute * timeExpiry)) {
+		return msg + fmt.Sprintf("Womöglich haben Sie das Quiz verlassen und dann versucht, "+
+			"nach über %v Minuten zurückzukehren.", timeExpiry)
+	}
+
+	return ""
+}
+
+// challengeEvents gets the events of the quiz of a day, which get chosen and
+// stored on the first request of the day. It returns no events, if there
+// aren't enough events yet.
+func challengeEvents(store x.Store, day time.Time) ([]x.Event, error) {
+
+	// Execute SQL statement to get the events of the quiz of the day
+	events, err := store.GetChallengeEvents(day)
+	if err != nil || len(events) > 0 {
+		return events, err
+	}
+
+	// Execute SQL statements to get all events of all topics
+	topics, err := store.GetTopics()
+	if err != nil {
+		return nil, err
+	}
+	var topicIDs []int
+	for _, topic := range topics {
+		topicIDs = append(topicIDs, topic.TopicID)
+	}
+	allEvents, err := store.GetEventsByTopics(topicIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	events = selectChallengeEvents(allEvents, day)
+	if len(events) < challengeQuestions {
+		return nil, nil
+	}
+
+	// Execute SQL statement to store the events of the quiz of the day
+	var eventIDs []int
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.EventID)
+	}
+	if err = store.CreateChallenge(day, eventIDs); err != nil {
+		return nil, err
+	}
+
+	// Execute SQL statement to get the stored events again, in case they
+	// were stored by a simultaneous request beforehand
+	return store.GetChallengeEvents(day)
+}
+
+// selectChallengeEvents chooses the events of the quiz of a day among all
+// events. They get shuffled with a seed derived from the day, so that the
+// same events get chosen for every request on that day.
+// (Tested in handler_test.go)
+func selectChallengeEvents(events []x.Event, day time.Time) []x.Event {
+
+	// Sort a copy of the events by ID, so that the choice doesn't depend on
+	// the order of the given events
+	events = append([]x.Event(nil), events...)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].EventID < events[j].EventID
+	})
+
+	// Shuffle events with an RNG based off of the day
+	random := rand.New(rand.NewSource(challengeSeed(day)))
+	random.Shuffle(len(events), func(n1, n2 int) {
+		events[n1], events[n2] = events[n2], events[n1]
+	})
+
+	if len(events) > challengeQuestions {
+		events = events[:challengeQuestions]
+	}
+
+	return events
+}
+
+// challengeSeed derives the seed of the RNG from a day (e.g. 20210302 for
+// March 2nd 2021).
+func challengeSeed(day time.Time) int64 {
+	return int64(day.Year()*10000 + int(day.Month())*100 + day.Day())
+}
+
+// challengeDay gets the day of a point in time, as it is stored in the
+// database.
+func challengeDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// createChallengeQuestions generates a phase2Question struct for every event
+// of the quiz of a day.
+func createChallengeQuestions(events []x.Event) []phase2Question {
+	var questions []phase2Question
+
+	for _, event := range events {
+		questions = append(questions, phase2Question{
+			EventID:      event.EventID,
+			EventName:    event.Name,
+			EventYear:    event.Year,
+			EventDetails: newEventDetails(event),
+		})
+	}
+
+	return questions
+}
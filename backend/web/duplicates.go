@@ -0,0 +1,205 @@
+// Responsible for detecting duplicate and near-duplicate events within a
+// topic, since the same event appearing twice breaks the choices of phase 1
+// and the chronological order of phase 3.
+//
+// Names of events get normalized (case, umlauts, punctuation) and compared by
+// their edit distance. Events with the same year and a similar name count as
+// duplicates, as well as events with the same name regardless of the year.
+
+package web
+
+import (
+	"strings"
+	"unicode"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// duplicateSimilarity is the minimum similarity (0-1) of two names of events
+// in the same year to count as near-duplicates.
+const duplicateSimilarity = 0.8
+
+// nameReplacer replaces umlauts and accented letters, which are commonly
+// typed without them (e.g. "Zurich" for "Zürich").
+var nameReplacer = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"à", "a", "á", "a", "â", "a", "ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n", "ò", "o", "ó", "o", "ô", "o",
+	"ù", "u", "ú", "u", "û", "u",
+)
+
+// eventDuplicate is an existing event (or another row of an import), which
+// an event to be created seems to duplicate.
+type eventDuplicate struct {
+	EventID int // ID of the existing event, 0 for another row of an import
+	Row     int // number of the row of an import, 0 for an existing event
+	Name    string
+	Year    int
+	Exact   bool // whether name and year are identical, rather than similar
+}
+
+// eventDuplicatePair is a pair of events of a topic, which seem to duplicate
+// each other, to be displayed in the report of duplicates.
+type eventDuplicatePair struct {
+	First      x.Event
+	Second     x.Event
+	Exact      bool
+	Similarity int // similarity of the names in percent
+}
+
+// findDuplicates finds all events that a new event seems to duplicate.
+// (Tested in handler_test.go)
+func findDuplicates(name string, year int, events []x.Event) []eventDuplicate {
+	var duplicates []eventDuplicate
+
+	for _, event := range events {
+		if duplicate, exact, _ := isDuplicate(name, year, event.Name, event.Year); duplicate {
+			duplicates = append(duplicates, eventDuplicate{
+				EventID: event.EventID,
+				Name:    event.Name,
+				Year:    event.Year,
+				Exact:   exact,
+			})
+		}
+	}
+
+	return duplicates
+}
+
+// markImportDuplicates adds the duplicates of every valid row of an import,
+// which are either existing events of the topic or previous rows of the
+// import.
+// (Tested in handler_test.go)
+func markImportDuplicates(rows []EventForm, events []x.Event) {
+
+	for i := range rows {
+		if len(rows[i].Errors) > 0 {
+			continue
+		}
+
+		rows[i].Duplicates = findDuplicates(rows[i].Name, rows[i].Year, events)
+
+		for j, previous := range rows[:i] {
+			if len(previous.Errors) > 0 {
+				continue
+			}
+			if duplicate, exact, _ := isDuplicate(rows[i].Name, rows[i].Year, previous.Name,
+				previous.Year); duplicate {
+				rows[i].Duplicates = append(rows[i].Duplicates, eventDuplicate{
+					Row:   j + 1,
+					Name:  previous.Name,
+					Year:  previous.Year,
+					Exact: exact,
+				})
+			}
+		}
+	}
+}
+
+// findDuplicatePairs finds all pairs of events of a topic, which seem to
+// duplicate each other, exact duplicates first.
+// (Tested in handler_test.go)
+func findDuplicatePairs(events []x.Event) []eventDuplicatePair {
+	var exact, similar []eventDuplicatePair
+
+	for i, first := range events {
+		for _, second := range events[i+1:] {
+			duplicate, isExact, similarity := isDuplicate(first.Name, first.Year, second.Name, second.Year)
+			if !duplicate {
+				continue
+			}
+
+			pair := eventDuplicatePair{
+				First:      first,
+				Second:     second,
+				Exact:      isExact,
+				Similarity: int(similarity * 100),
+			}
+			if isExact {
+				exact = append(exact, pair)
+			} else {
+				similar = append(similar, pair)
+			}
+		}
+	}
+
+	return append(exact, similar...)
+}
+
+// isDuplicate checks whether two events seem to be the same, which is the case
+// if they have the same normalized name or if they happened in the same year
+// and have similar names. It returns whether they are duplicates, whether
+// they are exact duplicates (same name and year) and the similarity of their
+// names.
+func isDuplicate(name1 string, year1 int, name2 string, year2 int) (bool, bool, float64) {
+
+	normalized1, normalized2 := normalizeName(name1), normalizeName(name2)
+	if normalized1 == normalized2 {
+		return true, year1 == year2, 1
+	}
+
+	similarity := nameSimilarity(normalized1, normalized2)
+
+	return year1 == year2 && similarity >= duplicateSimilarity, false, similarity
+}
+
+// normalizeName normalizes the name of an event to compare it with others, by
+// converting it to lowercase, replacing umlauts and accents and removing
+// punctuation (e.g. "Fall der Berliner Mauer!" -> "fall der berliner mauer").
+// (Tested in handler_test.go)
+func normalizeName(name string) string {
+
+	name = nameReplacer.Replace(strings.ToLower(name))
+
+	// Replace everything but letters and digits with spaces
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, name)
+
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// nameSimilarity calculates the similarity of two names between 0 (entirely
+// different) and 1 (identical), based on their edit distance relative to the
+// length of the longer name.
+// (Tested in handler_test.go)
+func nameSimilarity(name1 string, name2 string) float64 {
+
+	runes1, runes2 := []rune(name1), []rune(name2)
+	length := max(len(runes1), len(runes2))
+	if length == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(runes1, runes2))/float64(length)
+}
+
+// levenshtein calculates the edit distance of two strings, which is the
+// minimum amount of insertions, deletions and substitutions of characters
+// needed to turn one into the other.
+func levenshtein(runes1 []rune, runes2 []rune) int {
+
+	// Only the previous row of the matrix is needed to calculate the next one
+	previous := make([]int, len(runes2)+1)
+	current := make([]int, len(runes2)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runes1); i++ {
+		current[0] = i
+		for j := 1; j <= len(runes2); j++ {
+			cost := 1
+			if runes1[i-1] == runes2[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(runes2)]
+}
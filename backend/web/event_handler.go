@@ -5,9 +5,20 @@
 package web
 
 import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi"
@@ -19,22 +30,48 @@ import (
 var (
 	// Parsed HTML-templates to be executed in their respective HTTP-handler
 	// functions when needed
-	eventsListTemplate, eventsCreateTemplate, eventsEditTemplate *template.Template
+	eventsListTemplate, eventsCreateTemplate, eventsEditTemplate, eventsImportTemplate,
+	eventsDuplicatesTemplate *template.Template
 )
 
+const (
+	importMaxSize = 1 << 20 // max size of an uploaded import file (1 MB)
+	importMaxRows = 500     // max amount of events in a single import
+)
+
+// init gets initialized with the package.
+//
+// It registers certain types to the session, because by default the session
+// can only contain basic data types (int, bool, string, etc.).
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
 func init() {
+	gob.Register(eventsImport{})
+	gob.Register([]EventForm{})
+
 	if _testing { // skip initialization of templates when running tests
 		return
 	}
 
-	eventsListTemplate = template.Must(template.ParseFiles(layout, templatePath+"events_list.html"))
-	eventsCreateTemplate = template.Must(template.ParseFiles(layout, templatePath+"events_create.html"))
-	eventsEditTemplate = template.Must(template.ParseFiles(layout, templatePath+"events_edit.html"))
+	eventsListTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"events_list.html"))
+	eventsCreateTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"events_create.html"))
+	eventsEditTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"events_edit.html",
+		templatePath+"revision_history.html", templatePath+"translations_form.html"))
+	eventsImportTemplate = template.Must(newTemplate().
+		Funcs(template.FuncMap{ // Add custom HTML-template-function to increment a number
+			"increment": func(num int) int {
+				return num + 1
+			},
+		}).
+		ParseFiles(layout, templatePath+"events_import.html"))
+	eventsDuplicatesTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"events_duplicates.html"))
 }
 
 // EventHandler is the object for handlers to access sessions and database.
 type EventHandler struct {
 	store    x.Store
+	blobs    x.BlobStore
 	sessions *scs.SessionManager
 }
 
@@ -55,16 +92,6 @@ func (h *EventHandler) List() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", "Unzureichende Berechtigung. "+
-				"Sie müssen als Benutzer eingeloggt sein, um all Ereignisse eines Themas aufzulisten.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve topic ID from URL parameters
 		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
 		if err != nil {
@@ -79,6 +106,13 @@ func (h *EventHandler) List() http.HandlerFunc {
 			return
 		}
 
+		// Localize the topic and its events to the language of the user
+		topic, err = withTranslations(h.store, topic, localeOf(req.Context()))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
 		// Execute HTML-templates with data
 		if err = eventsListTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
@@ -106,17 +140,6 @@ func (h *EventHandler) Create() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if an admin is logged in
-		user := req.Context().Value("user")
-		if user == nil || !user.(x.User).Admin {
-			// If no user is logged in or logged in user isn't an admin,
-			// then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", "Unzureichende Berechtigung. "+
-				"Sie müssen als Admin eingeloggt sein, um ein neues Ereignis zu erstellen.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve topic ID from URL parameters
 		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
 		if err != nil {
@@ -152,12 +175,22 @@ func (h *EventHandler) CreateStore() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
+		// Retrieve uploaded image from form, if any
+		upload, uploadError := readImageUpload(res, req)
+
 		// Retrieve values from form
 		form := EventForm{
-			Name:       req.FormValue("name"),
-			YearOrDate: req.FormValue("year"),
+			Name:        req.FormValue("name"),
+			YearOrDate:  req.FormValue("year"),
+			Description: strings.TrimSpace(req.FormValue("description")),
+			Source:      strings.TrimSpace(req.FormValue("source")),
+			Image:       strings.TrimSpace(req.FormValue("image")),
+			upload:      upload,
+			uploadError: uploadError,
 		}
 
+		form.IgnoreDuplicates = req.FormValue("ignore_duplicates") == "true"
+
 		// Validate form
 		if !form.Validate() {
 			h.sessions.Put(req.Context(), "form", form)
@@ -169,17 +202,54 @@ func (h *EventHandler) CreateStore() http.HandlerFunc {
 		topicIDstr := chi.URLParam(req, "topicID")
 		topicID, _ := strconv.Atoi(topicIDstr)
 
+		// Execute SQL statement to get the topic, in order to check whether
+		// the event seems to duplicate one of its events. In that case the
+		// admin gets warned and has to confirm creating the event anyway.
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if form.Duplicates = findDuplicates(form.Name, form.Year, topic.Events); len(form.Duplicates) > 0 &&
+			!form.IgnoreDuplicates {
+			h.sessions.Put(req.Context(), "form", form)
+			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+			return
+		}
+
+		// Save uploaded image, which replaces the URL of the image
+		if form.upload != nil {
+			imageURL, err := saveImage(h.blobs, form.upload)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			form.Image = imageURL
+		}
+
 		// Execute SQL statement to create an event
-		if err := h.store.CreateEvent(&x.Event{
+		event := x.Event{
 			TopicID: topicID,
 			Name:    form.Name,
 			Year:    form.Year,
 			Date:    form.Date,
-		}); err != nil {
+
+			Description: form.Description,
+			Source:      form.Source,
+			Image:       form.Image,
+		}
+		if err = h.store.CreateEvent(&event); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Record creation in audit log and first revision
+		audit(h.store, req, auditCreate, auditEvent, event.EventID, nil, event)
+		revise(h.store, req, auditEvent, event.EventID, nil, event)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
 		// Adds flash message
 		h.sessions.Put(req.Context(), "flash_success", "Ereignis wurde erfolgreich erstellt.")
 
@@ -190,7 +260,7 @@ func (h *EventHandler) CreateStore() http.HandlerFunc {
 
 // Delete is a POST-method that is accessible to any admin after List.
 //
-// It deletes an event and redirects to List.
+// It moves an event to the trash and redirects to List.
 func (h *EventHandler) Delete() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
@@ -201,13 +271,29 @@ func (h *EventHandler) Delete() http.HandlerFunc {
 		// Retrieve event ID from URL parameters
 		eventID, _ := strconv.Atoi(chi.URLParam(req, "eventID"))
 
-		// Execute SQL statement to delete an event
-		if err := h.store.DeleteEvent(eventID); err != nil {
+		// Execute SQL statement to get the event, for the audit log
+		event, err := h.store.GetEvent(eventID)
+		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Redirect to list of topics
+		// Execute SQL statement to move an event to the trash
+		if err = h.store.DeleteEvent(eventID); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record deletion in audit log
+		audit(h.store, req, auditDelete, auditEvent, eventID, event, nil)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Ereignis wurde in den Papierkorb verschoben.")
+
+		// Redirect to list of events
 		http.Redirect(res, req, "/topics/"+topicID+"/events", http.StatusSeeOther)
 	}
 }
@@ -223,22 +309,17 @@ func (h *EventHandler) Edit() http.HandlerFunc {
 		SessionData
 		CSRF template.HTML
 
-		Event x.Event
+		Event     x.Event
+		Revisions []revisionView
+		RevertURL string // URL to revert to a revision, without the revision ID
+
+		Translations    []translationInput // translation per language other than German
+		TranslationForm TranslationForm    // invalid translation, if any
+		TranslateURL    string             // URL to save a translation
 	}
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if an admin is logged in
-		user := req.Context().Value("user")
-		if user == nil || !user.(x.User).Admin {
-			// If no user is logged in or logged in user isn't an admin,
-			// then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", "Unzureichende Berechtigung. "+
-				"Sie müssen als Admin eingeloggt sein, um ein Ereignis zu bearbeiten.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve event ID from URL parameters
 		eventID, err := strconv.Atoi(chi.URLParam(req, "eventID"))
 		if err != nil {
@@ -253,11 +334,43 @@ func (h *EventHandler) Edit() http.HandlerFunc {
 			return
 		}
 
+		// Execute SQL statement to get the translations of the event
+		event.Translations, err = h.store.GetEventTranslations(eventID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get revisions of the event, in order to
+		// display its history
+		revisions, err := h.store.GetRevisionsByTarget(auditEvent, eventID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		history, err := revisionHistory(revisions)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Separate an invalid translation from the form of the event
+		sessionData := GetSessionData(h.sessions, req.Context())
+		translationForm, _ := sessionData.Form.(TranslationForm)
+		if translationForm.Errors != nil {
+			sessionData.Form = map[string]string{}
+		}
+
 		// Execute HTML-templates with data
 		if err = eventsEditTemplate.Execute(res, data{
-			SessionData: GetSessionData(h.sessions, req.Context()),
-			CSRF:        csrf.TemplateField(req),
-			Event:       event,
+			SessionData:     sessionData,
+			CSRF:            csrf.TemplateField(req),
+			Event:           event,
+			Revisions:       history,
+			RevertURL:       fmt.Sprintf("/topics/%v/events/%v/revisions/", event.TopicID, event.EventID),
+			Translations:    translationInputs(event.Translations),
+			TranslationForm: translationForm,
+			TranslateURL:    fmt.Sprintf("/topics/%v/events/%v/translations", event.TopicID, event.EventID),
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
@@ -274,10 +387,18 @@ func (h *EventHandler) EditStore() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
+		// Retrieve uploaded image from form, if any
+		upload, uploadError := readImageUpload(res, req)
+
 		// Retrieve values from form
 		form := EventForm{
-			Name:       req.FormValue("name"),
-			YearOrDate: req.FormValue("year"),
+			Name:        req.FormValue("name"),
+			YearOrDate:  req.FormValue("year"),
+			Description: strings.TrimSpace(req.FormValue("description")),
+			Source:      strings.TrimSpace(req.FormValue("source")),
+			Image:       strings.TrimSpace(req.FormValue("image")),
+			upload:      upload,
+			uploadError: uploadError,
 		}
 
 		// Validate form
@@ -287,21 +408,52 @@ func (h *EventHandler) EditStore() http.HandlerFunc {
 			return
 		}
 
+		// Save uploaded image, which replaces the URL of the image
+		if form.upload != nil {
+			imageURL, err := saveImage(h.blobs, form.upload)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			form.Image = imageURL
+		}
+
 		// Retrieve topic ID from URL parameters
 		topicID := chi.URLParam(req, "topicID")
 		eventID, _ := strconv.Atoi(chi.URLParam(req, "eventID"))
 
+		// Execute SQL statement to get the event before updating it
+		previous, err := h.store.GetEvent(eventID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
 		// Execute SQL statement to update event
-		if err := h.store.UpdateEvent(&x.Event{
+		event := x.Event{
 			EventID: eventID,
+			TopicID: previous.TopicID,
 			Name:    form.Name,
 			Year:    form.Year,
 			Date:    form.Date,
-		}); err != nil {
+
+			Description: form.Description,
+			Source:      form.Source,
+			Image:       form.Image,
+		}
+		if err = h.store.UpdateEvent(&event); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Record update in audit log and new revision. A replaced image
+		// doesn't get deleted, since previous revisions still refer to it.
+		audit(h.store, req, auditUpdate, auditEvent, eventID, previous, event)
+		revise(h.store, req, auditEvent, eventID, previous, event)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
 		// Add flash message to session
 		h.sessions.Put(req.Context(), "flash_success", "Ereignis wurde erfolgreich bearbeitet.")
 
@@ -309,3 +461,561 @@ func (h *EventHandler) EditStore() http.HandlerFunc {
 		http.Redirect(res, req, "/topics/"+topicID+"/events", http.StatusSeeOther)
 	}
 }
+
+// Revert is a POST-method that is accessible to any admin after Edit.
+//
+// It reverts an event to a previous revision, which gets recorded as a new
+// revision, and redirects to Edit.
+func (h *EventHandler) Revert() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID, event ID and revision ID from URL parameters
+		topicID := chi.URLParam(req, "topicID")
+		eventID, _ := strconv.Atoi(chi.URLParam(req, "eventID"))
+		revisionID, _ := strconv.Atoi(chi.URLParam(req, "revisionID"))
+
+		// Execute SQL statement to get the revision
+		revision, err := h.store.GetRevision(revisionID)
+		if err != nil || revision.TargetType != auditEvent || revision.TargetID != eventID {
+			http.NotFound(res, req)
+			return
+		}
+
+		// Execute SQL statement to get the event before reverting it
+		previous, err := h.store.GetEvent(eventID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Restore the event from the snapshot of the revision
+		var event x.Event
+		if err = json.Unmarshal([]byte(revision.Snapshot), &event); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		event.EventID, event.TopicID, event.DeletedAt = eventID, previous.TopicID, nil
+
+		// Execute SQL statement to update event
+		if err = h.store.UpdateEvent(&event); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record reversion in audit log and new revision
+		audit(h.store, req, auditRevert, auditEvent, eventID, previous, event)
+		revise(h.store, req, auditEvent, eventID, previous, event)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Ereignis wurde auf die Version vom "+
+			formatDateTime(localeOf(req.Context()), revision.Date)+" zurückgesetzt.")
+
+		// Redirect to Edit
+		http.Redirect(res, req, "/topics/"+topicID+"/events/"+strconv.Itoa(eventID)+"/edit", http.StatusSeeOther)
+	}
+}
+
+// Translate is a POST-method that is accessible to any admin after Edit.
+//
+// It validates the form of a translation from Edit and redirects to Edit in
+// case of an invalid input with the corresponding error message. In case of a
+// valid form, it stores the translation of the event into another language in
+// the database, or deletes it if the name and description are empty, and
+// redirects to Edit.
+func (h *EventHandler) Translate() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve values from form
+		form := TranslationForm{
+			Locale:      req.FormValue("locale"),
+			Name:        strings.TrimSpace(req.FormValue("name")),
+			Description: strings.TrimSpace(req.FormValue("description")),
+			maxName:     150,
+		}
+
+		// Validate form
+		if !form.Validate() {
+			h.sessions.Put(req.Context(), "form", form)
+			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+			return
+		}
+
+		// Retrieve topic ID and event ID from URL parameters
+		topicID := chi.URLParam(req, "topicID")
+		eventID, _ := strconv.Atoi(chi.URLParam(req, "eventID"))
+
+		// Execute SQL statement to get the translations of the event before
+		// changing them
+		translations, err := h.store.GetEventTranslations(eventID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		previous := translationOf(translations, form.Locale)
+
+		// Execute SQL statement to delete the translation, in case of an empty
+		// form, or to save it
+		translation := x.Translation{
+			ID:          eventID,
+			Locale:      form.Locale,
+			Name:        form.Name,
+			Description: form.Description,
+		}
+		var current interface{} = translation
+		if form.Name == "" {
+			err = h.store.DeleteEventTranslation(eventID, form.Locale)
+			current = nil
+		} else {
+			err = h.store.SaveEventTranslation(&translation)
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record translation in audit log
+		audit(h.store, req, auditTranslate, auditEvent, eventID, previous, current)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Übersetzung wurde erfolgreich gespeichert.")
+
+		// Redirect to Edit
+		http.Redirect(res, req, "/topics/"+topicID+"/events/"+strconv.Itoa(eventID)+"/edit", http.StatusSeeOther)
+	}
+}
+
+// Duplicates is a GET-method that is accessible to any admin.
+//
+// It reports all pairs of events of a topic, which seem to duplicate each
+// other, with the ability to edit or delete either event.
+func (h *EventHandler) Duplicates() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Topic x.Topic
+		Pairs []eventDuplicatePair
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get a topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute HTML-templates with data
+		if err = eventsDuplicatesTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
+			Topic:       topic,
+			Pairs:       findDuplicatePairs(topic.Events),
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Import is a GET-method that is accessible to any admin.
+//
+// It displays a form, in which a CSV- or JSON-file of events can be uploaded
+// or pasted. After having submitted the form, it additionally displays a
+// preview of all parsed events, including potential errors per row.
+func (h *EventHandler) Import() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Topic          x.Topic
+		Rows           []EventForm
+		ValidCount     int
+		DuplicateCount int
+		ParseError     string
+		HasPreview     bool
+		ImportValid    bool
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get a topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Retrieve preview of a previously submitted import from session,
+		// which only counts if it belongs to this topic
+		preview, ok := h.sessions.Get(req.Context(), "import").(eventsImport)
+		ok = ok && preview.TopicID == topicID
+
+		// Execute HTML-templates with data
+		if err = eventsImportTemplate.Execute(res, data{
+			SessionData:    GetSessionData(h.sessions, req.Context()),
+			CSRF:           csrf.TemplateField(req),
+			Topic:          topic,
+			Rows:           preview.Rows,
+			ValidCount:     preview.validCount(),
+			DuplicateCount: preview.duplicateCount(),
+			ParseError:     preview.ParseError,
+			HasPreview:     ok,
+			ImportValid:    ok && preview.validCount() > 0,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// ImportSubmit is a POST-method that is accessible to any admin after Import.
+//
+// It parses the uploaded or pasted CSV- or JSON-file and validates every row
+// the same way as when creating a single event. The result gets stored in the
+// session and it redirects back to Import, which displays the preview.
+func (h *EventHandler) ImportSubmit() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicIDstr := chi.URLParam(req, "topicID")
+		topicID, err := strconv.Atoi(topicIDstr)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get the topic
+		topic, err := h.store.GetTopic(topicID)
+		if errors.Is(err, sql.ErrNoRows) { // topic doesn't exist or is in the trash
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Retrieve file or pasted content from form
+		content, filename, err := readImportContent(res, req)
+		if err != nil {
+			h.sessions.Put(req.Context(), "flash_error", err.Error())
+			http.Redirect(res, req, "/topics/"+topicIDstr+"/events/import", http.StatusSeeOther)
+			return
+		}
+
+		// Parse and validate events
+		preview := eventsImport{TopicID: topicID}
+		preview.Rows, err = parseEventsImport(content, importFormat(req.FormValue("format"), filename, content))
+		if err != nil {
+			preview.ParseError = err.Error()
+		}
+
+		// Warn about rows that seem to duplicate events of the topic or other
+		// rows
+		markImportDuplicates(preview.Rows, topic.Events)
+
+		// Pass preview to session
+		h.sessions.Put(req.Context(), "import", preview)
+
+		// Redirect to import with preview
+		http.Redirect(res, req, "/topics/"+topicIDstr+"/events/import", http.StatusSeeOther)
+	}
+}
+
+// ImportStore is a POST-method that is accessible to any admin after
+// ImportSubmit.
+//
+// It stores all valid events of the previewed import in the database, within
+// a single transaction, and redirects to List.
+func (h *EventHandler) ImportStore() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicIDstr := chi.URLParam(req, "topicID")
+		topicID, err := strconv.Atoi(topicIDstr)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get the topic, which may have been moved to
+		// the trash since the preview
+		_, err = h.store.GetTopic(topicID)
+		if errors.Is(err, sql.ErrNoRows) { // topic doesn't exist or is in the trash
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Retrieve preview from session
+		preview, ok := h.sessions.Pop(req.Context(), "import").(eventsImport)
+		if !ok || preview.TopicID != topicID || preview.validCount() == 0 {
+			h.sessions.Put(req.Context(), "flash_error", "Es gibt keine gültigen Ereignisse zum Importieren.")
+			http.Redirect(res, req, "/topics/"+topicIDstr+"/events/import", http.StatusSeeOther)
+			return
+		}
+
+		// Only import valid rows, optionally without the ones that seem to be
+		// duplicates
+		skipDuplicates := req.FormValue("skip_duplicates") == "true"
+		var events []x.Event
+		for _, row := range preview.Rows {
+			if len(row.Errors) == 0 && !(skipDuplicates && len(row.Duplicates) > 0) {
+				events = append(events, x.Event{
+					TopicID: topicID,
+					Name:    row.Name,
+					Year:    row.Year,
+					Date:    row.Date,
+				})
+			}
+		}
+
+		if len(events) == 0 {
+			h.sessions.Put(req.Context(), "flash_error", "Es gibt keine Ereignisse ohne Duplikate zum Importieren.")
+			http.Redirect(res, req, "/topics/"+topicIDstr+"/events", http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to create events
+		if err = h.store.CreateEvents(events); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record import in audit log, with the topic as target, since the IDs
+		// of the imported events are unknown
+		audit(h.store, req, auditImport, auditTopic, topicID, nil, events)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success",
+			fmt.Sprintf("%v Ereignisse wurden erfolgreich importiert.", len(events)))
+
+		// Redirect to list of events
+		http.Redirect(res, req, "/topics/"+topicIDstr+"/events", http.StatusSeeOther)
+	}
+}
+
+// eventsImport holds the parsed rows of an import, to be previewed before
+// storing them.
+type eventsImport struct {
+	TopicID    int
+	Rows       []EventForm
+	ParseError string
+}
+
+// validCount returns the amount of rows without any errors.
+func (preview eventsImport) validCount() int {
+	var count int
+	for _, row := range preview.Rows {
+		if len(row.Errors) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// duplicateCount returns the amount of valid rows, which seem to be
+// duplicates.
+func (preview eventsImport) duplicateCount() int {
+	var count int
+	for _, row := range preview.Rows {
+		if len(row.Errors) == 0 && len(row.Duplicates) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// readImportContent retrieves the content of an import, which is either an
+// uploaded file or text pasted into the form. It also returns the name of
+// the uploaded file, if any.
+func readImportContent(res http.ResponseWriter, req *http.Request) ([]byte, string, error) {
+
+	// Limit size of request body
+	req.Body = http.MaxBytesReader(res, req.Body, importMaxSize+1024)
+	if err := req.ParseMultipartForm(importMaxSize); err != nil && err != http.ErrNotMultipart {
+		return nil, "", errors.New("Die Datei ist zu gross (max. 1 MB).")
+	}
+
+	// Check for uploaded file
+	file, header, err := req.FormFile("file")
+	if err == nil {
+		defer file.Close()
+		content, err := ioutil.ReadAll(file)
+		if err != nil {
+			return nil, "", errors.New("Die Datei konnte nicht gelesen werden.")
+		}
+		if len(bytes.TrimSpace(content)) > 0 {
+			return content, header.Filename, nil
+		}
+	}
+
+	// Otherwise use pasted text
+	content := []byte(req.FormValue("content"))
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, "", errors.New("Bitte laden Sie eine Datei hoch oder fügen Sie den Inhalt ein.")
+	}
+
+	return content, "", nil
+}
+
+// importFormat determines the format of an import ("csv" or "json"), either
+// by the format chosen in the form, the file extension or the content itself.
+// (Tested in handler_test.go)
+func importFormat(format string, filename string, content []byte) string {
+
+	format = strings.ToLower(format)
+	if format == "csv" || format == "json" {
+		return format
+	}
+
+	if ext := strings.ToLower(filepath.Ext(filename)); ext == ".csv" || ext == ".json" {
+		return ext[1:]
+	}
+
+	// JSON always starts with an array or object
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return "json"
+	}
+
+	return "csv"
+}
+
+// parseEventsImport parses the content of an import into event forms and
+// validates each of them. Invalid rows keep their error messages in order to
+// be displayed in the preview.
+// Example CSV: 'Mauerfall;09.11.1989'
+// Example JSON: '[{"name": "Mauerfall", "year": "09.11.1989"}]'
+// (Tested in handler_test.go)
+func parseEventsImport(content []byte, format string) ([]EventForm, error) {
+	var rows [][2]string
+
+	switch format {
+	case "json":
+		var entries []struct {
+			Name string      `json:"name"`
+			Year interface{} `json:"year"`
+			Date interface{} `json:"date"`
+		}
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, errors.New("Ungültiges JSON. Erwartet wird eine Liste von Objekten mit 'name' und 'year'.")
+		}
+		for _, entry := range entries {
+			yearOrDate := entry.Year
+			if yearOrDate == nil {
+				yearOrDate = entry.Date // 'date' is accepted as an alias of 'year'
+			}
+			rows = append(rows, [2]string{entry.Name, jsonValueToString(yearOrDate)})
+		}
+
+	default:
+		reader := csv.NewReader(bytes.NewReader(content))
+		reader.Comma = csvDelimiter(content)
+		reader.FieldsPerRecord = -1 // rows with a wrong amount of columns get an error message instead
+		reader.TrimLeadingSpace = true
+		for num := 0; ; num++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Ungültiges CSV in Zeile %v.", num+1)
+			}
+			// Skip optional header row
+			if num == 0 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "name") {
+				continue
+			}
+			row := [2]string{}
+			copy(row[:], record)
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("Es wurden keine Ereignisse gefunden.")
+	}
+	if len(rows) > importMaxRows {
+		return nil, fmt.Errorf("Es können höchstens %v Ereignisse auf einmal importiert werden.", importMaxRows)
+	}
+
+	// Validate every row like a single event
+	var forms []EventForm
+	for _, row := range rows {
+		form := EventForm{
+			Name:       strings.TrimSpace(row[0]),
+			YearOrDate: strings.TrimSpace(row[1]),
+		}
+		form.Validate()
+		forms = append(forms, form)
+	}
+
+	return forms, nil
+}
+
+// csvDelimiter guesses the delimiter of a CSV-file by its first line, since
+// spreadsheets with German settings use ';' instead of ','.
+func csvDelimiter(content []byte) rune {
+
+	firstLine := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		firstLine = content[:i]
+	}
+
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+
+	return ','
+}
+
+// jsonValueToString converts a year or date from a JSON-file, which may be a
+// number or a string, to a string.
+func jsonValueToString(value interface{}) string {
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.Itoa(int(v))
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
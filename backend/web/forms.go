@@ -20,11 +20,14 @@ import (
 func init() {
 	gob.Register(TopicForm{})
 	gob.Register(EventForm{})
+	gob.Register(TranslationForm{})
+	gob.Register(SuggestionForm{})
 	gob.Register(RegisterForm{})
 	gob.Register(LoginForm{})
 	gob.Register(EditUsernameForm{})
 	gob.Register(EditEmailForm{})
 	gob.Register(EditPasswordForm{})
+	gob.Register(EditClassForm{})
 	gob.Register(ResetPasswordForm{})
 	gob.Register(ForgotPasswordForm{})
 	gob.Register(FormErrors{})
@@ -46,6 +49,11 @@ type TopicForm struct {
 	EndYear     int
 	Description string
 	Image       string
+	Tags        string // comma-separated names of tags
+	ParentID    int    // ID of the parent topic, 0 for a top-level topic
+
+	upload      []byte // content of an uploaded image, which replaces the URL of the image
+	uploadError string // error that occurred while receiving the uploaded image
 
 	Errors FormErrors
 }
@@ -80,28 +88,60 @@ func (form *TopicForm) Validate() bool {
 		form.Errors["Description"] = "Beschreibung darf 1000 Buchstaben nicht überschreiten."
 	}
 
+	// Validate tags (optional)
+	tags := parseTags(form.Tags)
+	if len(tags) > 10 {
+		form.Errors["Tags"] = "Es sind höchstens 10 Tags erlaubt."
+	}
+	for _, tag := range tags {
+		if len([]rune(tag)) > 30 {
+			form.Errors["Tags"] = fmt.Sprintf("Tag '%v' darf 30 Zeichen nicht überschreiten.", tag)
+		}
+	}
+
 	// Validate image
-	if form.Image == "" {
+	if form.Image == "" && form.upload == nil && form.uploadError == "" {
 		form.Errors["Image"] = "URL des Fotos darf nicht leer sein."
-	} else if len(form.Image) > 5000 {
-		form.Errors["Image"] = "URL des Fotos darf 5000 Buchstaben nicht überschreiten."
-	} else if !regex(form.Image, "(?i)^(https?://).*$") {
-		form.Errors["Image"] = "URL des Fotos muss mit HTTP:// oder HTTPS:// beginnen."
-	} else if !regex(form.Image, "(?i)^.*\\.(png|jpe?g|gif)$") {
-		form.Errors["Image"] = "URL des Fotos muss auf '.PNG', '.JPG', '.JPEG' oder '.GIF' enden."
-	} else if strings.Contains(form.Image, " ") {
-		form.Errors["Image"] = "URL des Fotos ist ungültig."
+	} else if msg := validateImage(form.Image, form.upload, form.uploadError); msg != "" {
+		form.Errors["Image"] = msg
 	}
 
 	return len(form.Errors) == 0
 }
 
+// parseTags splits comma-separated names of tags, ignoring empty names and
+// duplicates regardless of case.
+func parseTags(tags string) []string {
+	var names []string
+
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(tags, ",") {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
 // EventForm holds values of the form input when creating or editing an event.
 type EventForm struct {
-	Name       string
-	Year       int
-	Date       time.Time
-	YearOrDate string
+	Name        string
+	Year        int
+	Date        time.Time
+	YearOrDate  string
+	Description string
+	Source      string
+	Image       string
+
+	upload      []byte // content of an uploaded image, which replaces the URL of the image
+	uploadError string // error that occurred while receiving the uploaded image
+
+	Duplicates       []eventDuplicate // existing events, which the event seems to duplicate
+	IgnoreDuplicates bool             // whether the admin confirmed to create the event anyway
 
 	Errors FormErrors
 }
@@ -118,42 +158,174 @@ func (form *EventForm) Validate() bool {
 	}
 
 	// Validate date or year
-	if form.YearOrDate == "" {
-		form.Errors["Year"] = "Jahr/Datum darf nicht leer sein."
+	var msg string
+	if form.Year, form.Date, msg = validateYearOrDate(form.YearOrDate); msg != "" {
+		form.Errors["Year"] = msg
+	}
+
+	// Validate description (optional)
+	if len(form.Description) > 1000 {
+		form.Errors["Description"] = "Beschreibung darf 1000 Buchstaben nicht überschreiten."
+	}
+
+	// Validate source (optional)
+	if form.Source != "" {
+		if len(form.Source) > 5000 {
+			form.Errors["Source"] = "URL der Quelle darf 5000 Buchstaben nicht überschreiten."
+		} else if !regex(form.Source, "(?i)^https?://.+$") {
+			form.Errors["Source"] = "URL der Quelle muss mit HTTP:// oder HTTPS:// beginnen."
+		} else if strings.Contains(form.Source, " ") {
+			form.Errors["Source"] = "URL der Quelle ist ungültig."
+		}
+	}
+
+	// Validate image (optional)
+	if msg := validateImage(form.Image, form.upload, form.uploadError); msg != "" {
+		form.Errors["Image"] = msg
+	}
+
+	return len(form.Errors) == 0
+}
+
+// TranslationForm holds values of the form input when translating a topic or
+// an event into another language.
+type TranslationForm struct {
+	Locale      string
+	Name        string
+	Description string
+
+	maxName int // maximum length of the name, which differs between topics and events
+
+	Errors FormErrors
+}
+
+// Validate validates the form input when translating a topic or an event. An
+// empty name and description are valid, since they delete the translation.
+func (form *TranslationForm) Validate() bool {
+	form.Errors = FormErrors{}
+
+	// Validate locale
+	if !supportedLocale(form.Locale) || form.Locale == defaultLocale {
+		form.Errors["Locale"] = "Sprache wird nicht unterstützt."
+	}
+
+	// Validate name (only optional when deleting the translation)
+	if form.Name == "" && form.Description != "" {
+		form.Errors["Name"] = "Name darf nicht leer sein."
+	} else if len(form.Name) > form.maxName {
+		form.Errors["Name"] = fmt.Sprintf("Name darf %v Zeichen nicht überschreiten.", form.maxName)
+	}
+
+	// Validate description (optional)
+	if len(form.Description) > 1000 {
+		form.Errors["Description"] = "Beschreibung darf 1000 Buchstaben nicht überschreiten."
 	}
-	year, err := strconv.Atoi(form.YearOrDate) // convert to int
-	if err == nil {                            // if no error occurs, admin entered a year (not a date)
-		form.Year = year
-		form.Date, _ = time.Parse("2006", form.YearOrDate) // date = year + default values (e.g. 1969-01-01 00:00:00)
+
+	return len(form.Errors) == 0
+}
+
+// SuggestionForm holds values of the form input when suggesting a correction
+// of an event or a new event.
+type SuggestionForm struct {
+	EventID    int // ID of the event to be corrected or 0 for a new event
+	Name       string
+	Year       int
+	Date       time.Time
+	YearOrDate string
+	Comment    string
+
+	Errors FormErrors
+}
+
+// Validate validates the form input when suggesting a correction of an event
+// or a new event.
+func (form *SuggestionForm) Validate() bool {
+	form.Errors = FormErrors{}
+
+	// Validate name (optional for corrections)
+	if form.Name == "" && form.EventID == 0 {
+		form.Errors["Name"] = "Name darf nicht leer sein."
+	} else if len(form.Name) > 150 {
+		form.Errors["Name"] = "Name darf 150 Zeichen nicht überschreiten."
+	}
+
+	// Validate date or year
+	var msg string
+	if form.Year, form.Date, msg = validateYearOrDate(form.YearOrDate); msg != "" {
+		form.Errors["Year"] = msg
+	}
+
+	// Validate comment (optional)
+	if len(form.Comment) > 1000 {
+		form.Errors["Comment"] = "Kommentar darf 1000 Buchstaben nicht überschreiten."
+	}
+
+	return len(form.Errors) == 0
+}
+
+// validateYearOrDate validates the year or date of an event, which may be
+// entered as year (e.g. 1969), month and year (e.g. 07.1969) or day, month and
+// year (e.g. 20.07.1969). It returns the year, the date (with default values for
+// missing day and month) and an error message or an empty string if valid.
+func validateYearOrDate(yearOrDate string) (int, time.Time, string) {
+
+	if yearOrDate == "" {
+		return 0, time.Time{}, "Jahr/Datum darf nicht leer sein."
+	}
+
+	year, err := strconv.Atoi(yearOrDate) // convert to int
+	if err == nil {                       // if no error occurs, user entered a year (not a date)
+		date, _ := time.Parse("2006", yearOrDate) // date = year + default values (e.g. 1969-01-01 00:00:00)
 
 		// Validate year
-		if form.Year <= 0 {
-			form.Errors["Year"] = "Jahr muss positiv sein."
-		} else if form.Year > time.Now().Year() {
-			form.Errors["Year"] = "Wird hier die Zukunft vorausgesagt?"
+		if year <= 0 {
+			return year, date, "Jahr muss positiv sein."
+		} else if year > time.Now().Year() {
+			return year, date, "Wird hier die Zukunft vorausgesagt?"
 		}
-	} else { // admin entered (day, ) month & year (e.g. 08.1969 or 13.19.69)
+		return year, date, ""
+	}
 
-		date, err := time.Parse("02.01.2006", form.YearOrDate) // check if admin entered valid date as 'dd.mm.yyyy'
-		now := time.Now()
+	// User entered (day, ) month & year (e.g. 08.1969 or 13.19.69)
+	now := time.Now()
+	date, err := time.Parse("02.01.2006", yearOrDate) // check if user entered valid date as 'dd.mm.yyyy'
+	if err != nil {
+		date, err = time.Parse("01.2006", yearOrDate) // check if user entered valid date as 'mm.yy'
 		if err != nil {
-			date, err = time.Parse("01.2006", form.YearOrDate) // check if admin entered valid date as 'mm.yy'
-			if err != nil {
-				form.Errors["Year"] = fmt.Sprintf("Ungültiges Format. Erlaubte Formate: '%v', '%s', '%s'",
-					now.Year(), now.Format("01.2006"), now.Format("02.01.2006"))
-			}
+			return 0, time.Time{}, fmt.Sprintf("Ungültiges Format. Erlaubte Formate: '%v', '%s', '%s'",
+				now.Year(), now.Format("01.2006"), now.Format("02.01.2006"))
 		}
+	}
 
-		if form.Errors["Year"] == "" { // admin entered valid date
-			if date.After(now) {
-				form.Errors["Year"] = "Wird hier die Zukunft vorausgesagt?"
-			}
-			form.Date = date
-			form.Year = date.Year()
-		}
+	if date.After(now) {
+		return date.Year(), date, "Wird hier die Zukunft vorausgesagt?"
 	}
 
-	return len(form.Errors) == 0
+	return date.Year(), date, ""
+}
+
+// validateImage validates either an uploaded image or the URL of an image,
+// whereas an upload replaces the URL. It returns an error message or an empty
+// string if the image is valid or both are empty.
+func validateImage(image string, upload []byte, uploadError string) string {
+
+	if uploadError != "" {
+		return uploadError
+	} else if upload != nil {
+		return validateImageUpload(upload)
+	} else if image == "" {
+		return ""
+	} else if len(image) > 5000 {
+		return "URL des Fotos darf 5000 Buchstaben nicht überschreiten."
+	} else if !regex(image, "(?i)^(https?://|/"+uploadPath+"/).*$") {
+		return "URL des Fotos muss mit HTTP:// oder HTTPS:// beginnen oder auf ein hochgeladenes Foto verweisen."
+	} else if !regex(image, "(?i)^.*\\.(png|jpe?g|gif)$") {
+		return "URL des Fotos muss auf '.PNG', '.JPG', '.JPEG' oder '.GIF' enden."
+	} else if strings.Contains(image, " ") {
+		return "URL des Fotos ist ungültig."
+	}
+
+	return ""
 }
 
 // ============================================================================
@@ -314,6 +486,29 @@ func (form *EditPasswordForm) Validate() bool {
 	return len(form.Errors) == 0
 }
 
+// EditClassForm holds values of the form input when editing the school class
+// of a user.
+type EditClassForm struct {
+	Class string
+
+	Errors FormErrors
+}
+
+// Validate validates the form input when editing a school class. An empty
+// class is valid, since it removes the user from their class.
+func (form *EditClassForm) Validate() bool {
+	form.Errors = FormErrors{}
+
+	// Validate class (optional)
+	if len([]rune(form.Class)) > 20 {
+		form.Errors["Class"] = "Klasse darf 20 Zeichen nicht überschreiten."
+	} else if !regex(form.Class, `^[\p{L}0-9 ._-]*$`) {
+		form.Errors["Class"] = "Klasse darf nur Buchstaben, Zahlen, Leerzeichen, '.', '_' und '-' enthalten."
+	}
+
+	return len(form.Errors) == 0
+}
+
 // ForgotPasswordForm holds values of the form input when entering an email to
 // reset a password.
 type ForgotPasswordForm struct {
@@ -3,6 +3,11 @@
 package web
 
 import (
+	"bytes"
+	"image"
+	"image/png"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,6 +24,11 @@ var _ = func() interface{} {
 // TestValidateTopicForm tests the validation of a TopicForm.
 func TestValidateTopicForm(t *testing.T) {
 
+	// Mock uploaded image
+	var buffer bytes.Buffer
+	_ = png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	tPNG := buffer.Bytes()
+
 	// Mock input form of user
 	type input struct {
 		name        string
@@ -26,6 +36,9 @@ func TestValidateTopicForm(t *testing.T) {
 		endYear     int
 		description string
 		image       string
+		tags        string
+		upload      []byte
+		uploadError string
 	}
 
 	// Declare test cases
@@ -289,6 +302,80 @@ func TestValidateTopicForm(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "#19 OK (IMAGE UPLOADED)",
+			form: input{
+				name:      "Topic 1",
+				startYear: 1800,
+				endYear:   1900,
+				upload:    tPNG,
+			},
+			want: true,
+		},
+		{
+			name: "#20 OK (IMAGE PREVIOUSLY UPLOADED)",
+			form: input{
+				name:      "Topic 1",
+				startYear: 1800,
+				endYear:   1900,
+				image:     "/" + uploadPath + "/abc.png",
+			},
+			want: true,
+		},
+		{
+			name: "#21 IMAGE UPLOADED INVALID",
+			form: input{
+				name:      "Topic 1",
+				startYear: 1800,
+				endYear:   1900,
+				upload:    []byte("not an image"),
+			},
+			want: false,
+		},
+		{
+			name: "#22 IMAGE UPLOAD FAILED",
+			form: input{
+				name:        "Topic 1",
+				startYear:   1800,
+				endYear:     1900,
+				image:       "https://image.png",
+				uploadError: "Das Bild darf höchstens 5 MB gross sein.",
+			},
+			want: false,
+		},
+		{
+			name: "#23 OK (TAGS)",
+			form: input{
+				name:      "Topic 1",
+				startYear: 1800,
+				endYear:   1900,
+				image:     "https://image.png",
+				tags:      "Antike, Schweizer Geschichte, ,antike",
+			},
+			want: true,
+		},
+		{
+			name: "#24 TAG TOO LONG",
+			form: input{
+				name:      "Topic 1",
+				startYear: 1800,
+				endYear:   1900,
+				image:     "https://image.png",
+				tags:      "Antike, " + strings.Repeat("a", 31),
+			},
+			want: false,
+		},
+		{
+			name: "#25 TOO MANY TAGS",
+			form: input{
+				name:      "Topic 1",
+				startYear: 1800,
+				endYear:   1900,
+				image:     "https://image.png",
+				tags:      "a,b,c,d,e,f,g,h,i,j,k",
+			},
+			want: false,
+		},
 	}
 
 	// Run tests
@@ -301,6 +388,9 @@ func TestValidateTopicForm(t *testing.T) {
 				EndYear:     test.form.endYear,
 				Description: test.form.description,
 				Image:       test.form.image,
+				Tags:        test.form.tags,
+				upload:      test.form.upload,
+				uploadError: test.form.uploadError,
 				Errors:      FormErrors{},
 			}
 
@@ -311,13 +401,47 @@ func TestValidateTopicForm(t *testing.T) {
 	}
 }
 
+// TestParseTags tests splitting comma-separated names of tags.
+func TestParseTags(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		tags string
+		want []string
+	}{
+		{name: "#1 OK", tags: "Antike,Mittelalter", want: []string{"Antike", "Mittelalter"}},
+		{name: "#2 WHITESPACE", tags: "  20.   Jahrhundert , Antike ", want: []string{"20. Jahrhundert", "Antike"}},
+		{name: "#3 DUPLICATES", tags: "Antike, antike,ANTIKE", want: []string{"Antike"}},
+		{name: "#4 EMPTY", tags: " , ,", want: nil},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseTags(test.tags); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseTags() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 // TestValidateEventForm tests the validation of an event form.
 func TestValidateEventForm(t *testing.T) {
 
+	// Mock uploaded image
+	var buffer bytes.Buffer
+	_ = png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	tPNG := buffer.Bytes()
+
 	// Mock input form of user
 	type input struct {
-		name       string
-		yearOrDate string
+		name        string
+		yearOrDate  string
+		description string
+		source      string
+		image       string
+		upload      []byte
 	}
 
 	future := time.Now().AddDate(1, 1, 1)
@@ -447,6 +571,62 @@ func TestValidateEventForm(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "#16 VALID (DETAILS)",
+			form: input{
+				name:        "Event 1",
+				yearOrDate:  "1800",
+				description: "Description 1",
+				source:      "https://source.com/event-1",
+				image:       "https://image.png",
+			},
+			want: true,
+		},
+		{
+			name: "#17 VALID (IMAGE UPLOADED)",
+			form: input{
+				name:       "Event 1",
+				yearOrDate: "1800",
+				upload:     tPNG,
+			},
+			want: true,
+		},
+		{
+			name: "#18 DESCRIPTION TOO LONG",
+			form: input{
+				name:        "Event 1",
+				yearOrDate:  "1800",
+				description: strings.Repeat("a", 1001),
+			},
+			want: false,
+		},
+		{
+			name: "#19 SOURCE INVALID",
+			form: input{
+				name:       "Event 1",
+				yearOrDate: "1800",
+				source:     "source.com",
+			},
+			want: false,
+		},
+		{
+			name: "#20 IMAGE INVALID",
+			form: input{
+				name:       "Event 1",
+				yearOrDate: "1800",
+				image:      "https://image.pdf",
+			},
+			want: false,
+		},
+		{
+			name: "#21 IMAGE UPLOADED INVALID",
+			form: input{
+				name:       "Event 1",
+				yearOrDate: "1800",
+				upload:     []byte("not an image"),
+			},
+			want: false,
+		},
 	}
 
 	// Run tests
@@ -459,6 +639,200 @@ func TestValidateEventForm(t *testing.T) {
 				Date:       time.Time{},
 				YearOrDate: test.form.yearOrDate,
 				Errors:     FormErrors{},
+
+				Description: test.form.description,
+				Source:      test.form.source,
+				Image:       test.form.image,
+				upload:      test.form.upload,
+			}
+
+			if got := form.Validate(); got != test.want {
+				t.Errorf("Validate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestValidateTranslationForm tests the validation of a TranslationForm.
+func TestValidateTranslationForm(t *testing.T) {
+
+	// Mock input form of user
+	type input struct {
+		locale      string
+		name        string
+		description string
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		form input
+		want bool
+	}{
+		{
+			name: "#1 VALID",
+			form: input{
+				locale:      "en",
+				name:        "Second World War",
+				description: "The war from 1939 to 1945.",
+			},
+			want: true,
+		},
+		{
+			name: "#2 VALID (DELETION)",
+			form: input{
+				locale: "en",
+			},
+			want: true,
+		},
+		{
+			name: "#3 LOCALE UNSUPPORTED",
+			form: input{
+				locale: "fr",
+				name:   "Seconde Guerre mondiale",
+			},
+			want: false,
+		},
+		{
+			name: "#4 LOCALE GERMAN",
+			form: input{
+				locale: "de",
+				name:   "Zweiter Weltkrieg",
+			},
+			want: false,
+		},
+		{
+			name: "#5 NAME MISSING",
+			form: input{
+				locale:      "en",
+				description: "The war from 1939 to 1945.",
+			},
+			want: false,
+		},
+		{
+			name: "#6 NAME TOO LONG",
+			form: input{
+				locale: "en",
+				name:   strings.Repeat("a", 51),
+			},
+			want: false,
+		},
+		{
+			name: "#7 DESCRIPTION TOO LONG",
+			form: input{
+				locale:      "en",
+				name:        "Second World War",
+				description: strings.Repeat("a", 1001),
+			},
+			want: false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			form := &TranslationForm{
+				Locale:      test.form.locale,
+				Name:        test.form.name,
+				Description: test.form.description,
+				maxName:     50,
+				Errors:      FormErrors{},
+			}
+
+			if got := form.Validate(); got != test.want {
+				t.Errorf("Validate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestValidateSuggestionForm tests the validation of a SuggestionForm.
+func TestValidateSuggestionForm(t *testing.T) {
+
+	// Mock input form of user
+	type input struct {
+		eventID    int
+		name       string
+		yearOrDate string
+		comment    string
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		form input
+		want bool
+	}{
+		{
+			name: "#1 VALID (CORRECTION)",
+			form: input{
+				eventID:    1,
+				yearOrDate: "25.10.1800",
+				comment:    "Siehe Schulbuch S. 42",
+			},
+			want: true,
+		},
+		{
+			name: "#2 VALID (NEW EVENT)",
+			form: input{
+				name:       "Event 1",
+				yearOrDate: "1800",
+			},
+			want: true,
+		},
+		{
+			name: "#3 NAME MISSING (NEW EVENT)",
+			form: input{
+				yearOrDate: "1800",
+			},
+			want: false,
+		},
+		{
+			name: "#4 NAME TOO LONG",
+			form: input{
+				eventID:    1,
+				name:       strings.Repeat("a", 151),
+				yearOrDate: "1800",
+			},
+			want: false,
+		},
+		{
+			name: "#5 YEAR MISSING",
+			form: input{
+				eventID: 1,
+			},
+			want: false,
+		},
+		{
+			name: "#6 DATE INVALID",
+			form: input{
+				eventID:    1,
+				yearOrDate: "1800-10-25",
+			},
+			want: false,
+		},
+		{
+			name: "#7 COMMENT TOO LONG",
+			form: input{
+				eventID:    1,
+				yearOrDate: "1800",
+				comment:    strings.Repeat("a", 1001),
+			},
+			want: false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			form := &SuggestionForm{
+				EventID:    test.form.eventID,
+				Name:       test.form.name,
+				YearOrDate: test.form.yearOrDate,
+				Comment:    test.form.comment,
+				Errors:     FormErrors{},
 			}
 
 			if got := form.Validate(); got != test.want {
@@ -928,6 +1302,38 @@ func TestValidateEditPasswordForm(t *testing.T) {
 	}
 }
 
+// TestValidateEditClassForm tests the validation of an EditClassForm.
+func TestValidateEditClassForm(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name  string
+		class string
+		want  bool
+	}{
+		{name: "#1 VALID", class: "4a", want: true},
+		{name: "#2 VALID (UMLAUT AND SPACE)", class: "gym 3ä", want: true},
+		{name: "#3 VALID (NO CLASS)", class: "", want: true},
+		{name: "#4 TOO LONG", class: strings.Repeat("a", 21), want: false},
+		{name: "#5 INVALID CHARACTERS", class: "4a<script>", want: false},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			form := &EditClassForm{
+				Class:  test.class,
+				Errors: FormErrors{},
+			}
+
+			if got := form.Validate(); got != test.want {
+				t.Errorf("Validate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 // TestValidateForgotPasswordForm tests the validation of a ForgotPasswordForm.
 func TestValidateForgotPasswordForm(t *testing.T) {
 
@@ -7,11 +7,11 @@ package web
 
 import (
 	"context"
+	"encoding/json"
 	"html/template"
 	"log"
 	"net/http"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -25,15 +25,17 @@ import (
 
 const (
 	staticPath   = "frontend/static"
+	uploadPath   = staticPath + "/uploads" // uploaded images get served as static files
 	templatePath = "frontend/html/templates/"
 	layout       = "frontend/html/layout.html"
 
-	topicsURL   = "/topics"
-	scoresURL   = "/scores"
-	profileURL  = "/users/profile"
-	loginURL    = "/users/login"
-	registerURL = "/users/login"
-	usersURL    = "/users"
+	topicsURL    = "/topics"
+	scoresURL    = "/scores"
+	challengeURL = "/challenge"
+	profileURL   = "/users/profile"
+	loginURL     = "/users/login"
+	registerURL  = "/users/register"
+	usersURL     = "/users"
 )
 
 var (
@@ -42,9 +44,10 @@ var (
 
 	// Parsed HTML-templates to be executed in their respective HTTP-handler
 	// functions when needed
-	homeTemplate, http404Template, http405Template *template.Template
+	homeTemplate, searchTemplate, http404Template, http405Template *template.Template
 
-	// Possible search result matches from the navigation bar search input
+	// Keywords of the search in German and English, which lead to certain
+	// pages
 	searchKeywords = map[string]string{
 		"quiz":       topicsURL,
 		"thema":      topicsURL,
@@ -52,6 +55,11 @@ var (
 		"ereignisse": topicsURL,
 		"spielen":    topicsURL,
 		"ereignis":   topicsURL,
+		"topic":      topicsURL,
+		"topics":     topicsURL,
+		"event":      topicsURL,
+		"events":     topicsURL,
+		"play":       topicsURL,
 
 		"leaderboard": scoresURL,
 		"ranking":     scoresURL,
@@ -59,6 +67,17 @@ var (
 		"resultat":    scoresURL,
 		"resultate":   scoresURL,
 		"punkte":      scoresURL,
+		"bestenliste": scoresURL,
+		"scores":      scoresURL,
+		"results":     scoresURL,
+		"points":      scoresURL,
+
+		"tagesquiz": challengeURL,
+		"täglich":   challengeURL,
+		"heute":     challengeURL,
+		"challenge": challengeURL,
+		"daily":     challengeURL,
+		"today":     challengeURL,
 
 		"account":      profileURL,
 		"konto":        profileURL,
@@ -70,13 +89,17 @@ var (
 		"e-mail":       profileURL,
 		"username":     profileURL,
 		"benutzername": profileURL,
+		"profile":      profileURL,
+		"password":     profileURL,
 
 		"login":     loginURL,
 		"einloggen": loginURL,
 		"anmelden":  loginURL,
+		"signin":    loginURL,
 
 		"register":     registerURL,
 		"registrieren": registerURL,
+		"signup":       registerURL,
 
 		"user":      usersURL,
 		"users":     usersURL,
@@ -84,6 +107,8 @@ var (
 		"verwalten": usersURL,
 		"befördern": usersURL,
 		"admin":     usersURL,
+		"manage":    usersURL,
+		"promote":   usersURL,
 	}
 )
 
@@ -96,67 +121,121 @@ func init() {
 		return
 	}
 
-	homeTemplate = template.Must(template.New("layout.html").
+	homeTemplate = template.Must(newTemplate().
 		Funcs(template.FuncMap{ // Add custom HTML-template-function to increment a number
 			"increment": func(num int) int {
 				return num + 1
 			},
 		}).
 		ParseFiles(layout, templatePath+"home.html"))
-	http404Template = template.Must(template.ParseFiles(layout, templatePath+"http_not_found.html"))
-	http405Template = template.Must(template.ParseFiles(layout, templatePath+"http_method_not_allowed.html"))
+	searchTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"search.html"))
+	http404Template = template.Must(newTemplate().ParseFiles(layout, templatePath+"http_not_found.html"))
+	http405Template = template.Must(newTemplate().ParseFiles(layout, templatePath+"http_method_not_allowed.html"))
 }
 
+// UploadPath is the directory, in which uploaded files get stored by the local
+// blob store, in order to be served as static files.
+const UploadPath = uploadPath
+
 // NewHandler initializes HTTP-handlers, including router and middleware.
-func NewHandler(store x.Store, sessions *scs.SessionManager, csrfKey []byte) *Handler {
+func NewHandler(store x.Store, blobs x.BlobStore, sessions *scs.SessionManager, csrfKey []byte) *Handler {
 	handler := &Handler{
 		Mux:      chi.NewMux(),
 		store:    store,
 		sessions: sessions,
 	}
 
-	topics := TopicHandler{store: store, sessions: sessions}
-	events := EventHandler{store: store, sessions: sessions}
-	scores := ScoreHandler{store: store, sessions: sessions}
-	quiz := QuizHandler{store: store, sessions: sessions}
-	users := UserHandler{store: store, sessions: sessions}
-
 	// Use middleware
 	handler.Use(middleware.Logger)
 	handler.Use(csrf.Protect(csrfKey, csrf.Secure(false)))
 	handler.Use(sessions.LoadAndSave)
 	handler.Use(handler.withUser)
+	handler.Use(handler.withLocale)
 
 	// Serve static files
 	handler.fileServer("/"+staticPath+"/", http.Dir(staticPath))
 
+	// Register routes
+	handler.routes(blobs)
+
+	// Handler for when a non-existing URL is called
+	handler.NotFound(handler.HTTP404())
+	handler.MethodNotAllowed(handler.HTTP405())
+
+	return handler
+}
+
+// routes registers the HTTP-handlers of all routes. Routes that require
+// certain permissions are grouped and protected by the corresponding
+// middleware (RequireLogin, RequireAdmin, RequireVerified).
+func (h *Handler) routes(blobs x.BlobStore) {
+
+	topics := TopicHandler{store: h.store, blobs: blobs, sessions: h.sessions}
+	events := EventHandler{store: h.store, blobs: blobs, sessions: h.sessions}
+	scores := ScoreHandler{store: h.store, sessions: h.sessions}
+	quiz := QuizHandler{store: h.store, sessions: h.sessions}
+	challenges := ChallengeHandler{store: h.store, sessions: h.sessions}
+	users := UserHandler{store: h.store, sessions: h.sessions}
+	audits := AuditHandler{store: h.store, sessions: h.sessions}
+	trash := TrashHandler{store: h.store, blobs: blobs, sessions: h.sessions}
+	suggestions := SuggestionHandler{store: h.store, sessions: h.sessions}
+
 	// Home
-	handler.Get("/", handler.Home())
-	handler.Get("/search", handler.Search())
+	h.Get("/", h.Home())
+	h.Get("/search", h.Search())
+	h.Get("/search/suggestions", h.Suggestions())
 
 	// Topics
-	handler.Route("/topics", func(r chi.Router) {
-		r.Get("/", topics.List())
-		r.Get("/{topicID}", topics.Show())
-		r.Get("/new", topics.Create())
-		r.Post("/", topics.CreateStore())
-		r.Post("/{topicID}/delete", topics.Delete())
-		r.Get("/{topicID}/edit", topics.Edit())
-		r.Post("/{topicID}/edit", topics.EditStore())
+	h.Route("/topics", func(router chi.Router) {
+		router.Get("/", topics.List())
+		router.Get("/{topicID}", topics.Show())
+
+		router.Group(func(router chi.Router) {
+			router.Use(h.RequireLogin)
+			router.Get("/{topicID}/anki", topics.Anki())
+			router.Get("/{topicID}/worksheet", topics.Worksheet())
+			router.Get("/{topicID}/suggest", suggestions.Create())
+			router.Post("/{topicID}/suggest", suggestions.CreateStore())
+		})
+
+		router.Group(func(router chi.Router) {
+			router.Use(h.RequireAdmin)
+			router.Get("/new", topics.Create())
+			router.Post("/", topics.CreateStore())
+			router.Post("/{topicID}/delete", topics.Delete())
+			router.Get("/{topicID}/edit", topics.Edit())
+			router.Post("/{topicID}/edit", topics.EditStore())
+			router.Post("/{topicID}/revisions/{revisionID}/revert", topics.Revert())
+			router.Post("/{topicID}/translations", topics.Translate())
+			router.Get("/{topicID}/export", topics.Export())
+			router.Get("/import", topics.Import())
+			router.Post("/import", topics.ImportSubmit())
+		})
 	})
 
 	// Events
-	handler.Route("/topics/{topicID}/events", func(router chi.Router) {
-		router.Get("/", events.List())
-		router.Get("/new", events.Create())
-		router.Post("/", events.CreateStore())
-		router.Post("/{eventID}/delete", events.Delete())
-		router.Get("/{eventID}/edit", events.Edit())
-		router.Post("/{eventID}/edit", events.EditStore())
+	h.Route("/topics/{topicID}/events", func(router chi.Router) {
+		router.With(h.RequireLogin).Get("/", events.List())
+
+		router.Group(func(router chi.Router) {
+			router.Use(h.RequireAdmin)
+			router.Get("/new", events.Create())
+			router.Post("/", events.CreateStore())
+			router.Post("/{eventID}/delete", events.Delete())
+			router.Get("/{eventID}/edit", events.Edit())
+			router.Post("/{eventID}/edit", events.EditStore())
+			router.Post("/{eventID}/revisions/{revisionID}/revert", events.Revert())
+			router.Post("/{eventID}/translations", events.Translate())
+			router.Get("/duplicates", events.Duplicates())
+			router.Get("/import", events.Import())
+			router.Post("/import", events.ImportSubmit())
+			router.Post("/import/store", events.ImportStore())
+		})
 	})
 
 	// Quiz
-	handler.Route("/topics/{topicID}/quiz", func(router chi.Router) {
+	h.Route("/topics/{topicID}/quiz", func(router chi.Router) {
+		router.Use(h.RequireLogin)
 		router.Get("/1", quiz.Phase1())
 		router.Post("/1", quiz.Phase1Submit())
 		router.Get("/1/review", quiz.Phase1Review())
@@ -171,41 +250,73 @@ func NewHandler(store x.Store, sessions *scs.SessionManager, csrfKey []byte) *Ha
 		router.Get("/summary", quiz.Summary())
 	})
 
+	// Quiz of the day
+	h.Route("/challenge", func(router chi.Router) {
+		router.Use(h.RequireLogin)
+		router.Get("/", challenges.Play())
+		router.Post("/", challenges.PlaySubmit())
+		router.Get("/archive", challenges.Archive())
+		router.Get("/{day}", challenges.Show())
+	})
+
 	// Scores
-	handler.Get("/scores", scores.List())
+	h.With(h.RequireLogin).Get("/scores", scores.List())
 
 	// Users
-	handler.Route("/users", func(router chi.Router) {
+	h.Route("/users", func(router chi.Router) {
 		router.Get("/register", users.Register())
 		router.Post("/register", users.RegisterSubmit())
 		router.Get("/login", users.Login())
 		router.Post("/login", users.LoginSubmit())
-		router.Get("/logout", users.Logout())
-		router.Get("/profile", users.Profile())
-		router.Get("/", users.List())
-		router.Post("/{userID}/delete", users.Delete())
-		router.Post("/{userID}/promote", users.Promote())
-
-		router.Get("/edit/username", users.EditUsername())
-		router.Post("/edit/username", users.EditUsernameSubmit())
-		router.Get("/edit/email", users.EditEmail())
-		router.Post("/edit/email", users.EditEmailSubmit())
-		router.Get("/edit/password", users.EditPassword())
-		router.Post("/edit/password", users.EditPasswordSubmit())
-
 		router.Get("/verify/email", users.VerifyEmail())
-		router.Post("/resend/email", users.ResendVerifyEmail())
 		router.Get("/forgot/password", users.ForgotPassword())
 		router.Post("/forgot/password", users.ForgotPasswordSubmit())
 		router.Get("/reset/password", users.ResetPassword())
 		router.Post("/reset/password", users.ResetPasswordSubmit())
+
+		router.Group(func(router chi.Router) {
+			router.Use(h.RequireLogin)
+			router.Get("/logout", users.Logout())
+			router.Get("/profile", users.Profile())
+			router.Get("/edit/username", users.EditUsername())
+			router.Post("/edit/username", users.EditUsernameSubmit())
+			router.Get("/edit/email", users.EditEmail())
+			router.Post("/edit/email", users.EditEmailSubmit())
+			router.Get("/edit/password", users.EditPassword())
+			router.Post("/edit/password", users.EditPasswordSubmit())
+			router.Get("/edit/class", users.EditClass())
+			router.Post("/edit/class", users.EditClassSubmit())
+			router.Post("/resend/email", users.ResendVerifyEmail())
+		})
+
+		// Managing other users additionally requires a verified email
+		router.Group(func(router chi.Router) {
+			router.Use(h.RequireAdmin, h.RequireVerified)
+			router.Get("/", users.List())
+			router.Post("/{userID}/delete", users.Delete())
+			router.Post("/{userID}/promote", users.Promote())
+		})
 	})
 
-	// Handler for when a non-existing URL is called
-	handler.NotFound(handler.HTTP404())
-	handler.MethodNotAllowed(handler.HTTP405())
+	// Suggestions
+	h.Route("/suggestions", func(router chi.Router) {
+		router.Use(h.RequireAdmin)
+		router.Get("/", suggestions.List())
+		router.Post("/{suggestionID}/accept", suggestions.Accept())
+		router.Post("/{suggestionID}/reject", suggestions.Reject())
+	})
 
-	return handler
+	// Audit log
+	h.With(h.RequireAdmin, h.RequireVerified).Get("/audit", audits.List())
+
+	// Trash
+	h.Route("/trash", func(router chi.Router) {
+		router.Use(h.RequireAdmin, h.RequireVerified)
+		router.Get("/", trash.List())
+		router.Post("/topics/{topicID}/restore", trash.RestoreTopic())
+		router.Post("/events/{eventID}/restore", trash.RestoreEvent())
+		router.Post("/users/{userID}/restore", trash.RestoreUser())
+	})
 }
 
 // Handler consists of the chi-multiplexer, a store interface and sessions.
@@ -292,46 +403,102 @@ func (h *Handler) Home() http.HandlerFunc {
 
 // Search is a GET-method that is accessible to anyone.
 //
-// It examines the search-query in the navigation bar and redirects user to a
-// fitting handler, if any.
+// It searches topics, events and pages for the search-query in the navigation
+// bar and displays the ranked results. JSON-clients (or '?format=json')
+// receive the results as JSON, e.g. for an autocompletion.
 func (h *Handler) Search() http.HandlerFunc {
 
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+
+		Query   string
+		Parsed  searchQuery
+		Results []searchResult
+	}
+
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Retrieve search result from form
-		searchQuery := req.URL.Query().Get("search")
-		searchQueries := strings.Split(strings.ToLower(searchQuery), " ")
+		// Retrieve search-query from URL query
+		rawQuery := strings.TrimSpace(req.URL.Query().Get("search"))
+		query := parseSearchQuery(rawQuery)
 
-		// Loop through possible search results to get redirected
+		// Execute SQL statements to get topics and their events
 		topics, err := h.store.GetTopics()
 		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		topicIDs := make([]int, len(topics))
+		for i, topic := range topics {
+			topicIDs[i] = topic.TopicID
+		}
+		events, err := h.store.GetEventsByTopics(topicIDs)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Only find pages, which are accessible to the user
+		user, loggedIn := req.Context().Value("user").(x.User)
+		pages := searchPagesOf(user, loggedIn)
 
-		// Add topics to search results
-		searchResults := searchKeywords
-		for _, topic := range topics {
-			topicSplit := strings.Split(strings.ToLower(topic.Name), " ")
-			for _, t := range topicSplit {
-				if t != "der" && t != "die" && t != "das" {
-					searchResults[t] = "/topics/" + strconv.Itoa(topic.TopicID)
-				}
+		results := localizeSearchResults(localeOf(req.Context()), search(query, topics, events, pages))
+
+		// Respond with JSON
+		if req.URL.Query().Get("format") == "json" || wantsJSON(req) {
+			if results == nil {
+				results = []searchResult{}
 			}
+			res.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_ = json.NewEncoder(res).Encode(map[string]interface{}{
+				"query":   rawQuery,
+				"results": results,
+			})
+			return
 		}
 
-		// Loop through possible search results
-		for _, search := range searchQueries {
-			if searchResults[search] != "" { // redirect in case of match
-				http.Redirect(res, req, searchResults[search], http.StatusSeeOther)
-				return
-			}
+		// Execute HTML-templates with data
+		if err = searchTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			Query:       rawQuery,
+			Parsed:      query,
+			Results:     results,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
 		}
+	}
+}
+
+// Suggestions is a GET-method that is accessible to anyone.
+//
+// It responds with topics, events and pages as JSON, of which every word of
+// the search-query (e.g. '?q=berliner ma') is the beginning of a word, in
+// order to suggest them while typing in the navigation bar.
+func (h *Handler) Suggestions() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Search query didn't find a match
-		h.sessions.Put(req.Context(), "flash_info",
-			"Es wurde kein Suchergebnis gefunden. Versuchen Sie es genauer und in ganzen Worten.")
-		http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+		// Retrieve search-query from URL query
+		query := req.URL.Query().Get("q")
+
+		// Look up suggestions in the index, which gets rebuilt if outdated,
+		// whereas only pages accessible to the user get suggested
+		user, loggedIn := req.Context().Value("user").(x.User)
+		suggestions, err := suggestIndex.suggest(h.store, query, searchPagesOf(user, loggedIn))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		suggestions = localizeSearchResults(localeOf(req.Context()), suggestions)
+
+		// Respond with JSON
+		res.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(res).Encode(map[string]interface{}{
+			"query":       query,
+			"suggestions": suggestions,
+		})
 	}
 }
 
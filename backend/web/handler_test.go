@@ -5,22 +5,29 @@
 package web
 
 import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
 )
 
-var (
-	// tScores is a mock array of scores for testing purposes
-	tScores []x.Score
-
-	// nilScores is a nil slice of scores, since "var s []Score" is a nil slice
-	// but "s := []Score{}" is an empty slice (so we can't use the latter for
-	// this use case)
-	nilScores []x.Score
-)
-
 // Skip other init functions in the package, which includes parsing templates,
 // which would resolve in an error. Package level variables get initialized
 // before the init function, thus the init function gets skipped when running
@@ -30,82 +37,6 @@ var _ = func() interface{} {
 	return nil
 }()
 
-func init() {
-	// Create mock scores
-	for i := 100; i > 0; i -= 3 {
-		score := x.Score{
-			Points: i,
-		}
-		tScores = append(tScores, score)
-	}
-}
-
-// TestQuizBinarySearchForPoints (from quiz_handler) tests searching for the index at which the user's
-// points would rank in, if all current scores from the same given topic were
-// sorted by points in descending order.
-func TestQuizBinarySearchForPoints(t *testing.T) {
-
-	// Declare test cases
-	tests := []struct {
-		name   string
-		points int
-		scores []x.Score
-		want   int
-	}{
-		{
-			name:   "#1 30 POINTS",
-			points: 30,
-			scores: tScores,
-			want:   24,
-		},
-		{
-			name:   "#2 HIGHEST POINTS",
-			points: 1000,
-			scores: tScores,
-			want:   0,
-		},
-		{
-			name:   "#3 LOWEST POINTS",
-			points: 0,
-			scores: tScores,
-			want:   len(tScores),
-		},
-		{
-			name:   "#4 MIDDLE POINTS",
-			points: 50,
-			scores: tScores,
-			want:   len(tScores)/2 - 1,
-		},
-		{
-			name:   "#5 NO SCORES",
-			points: 50,
-			scores: nilScores,
-			want:   0,
-		},
-		{
-			name:   "#6 1 HIGHER SCORE",
-			points: 50,
-			scores: tScores[:1],
-			want:   1,
-		},
-		{
-			name:   "#7 1 LOWER SCORE",
-			points: 50,
-			scores: tScores[len(tScores)-1:],
-			want:   0,
-		},
-	}
-
-	// Run tests
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			if got := binarySearchForPoints(test.points, test.scores, 0, len(test.scores)); got != test.want {
-				t.Errorf("binarySearchForPoints() = %v, want %v", got, test.want)
-			}
-		})
-	}
-}
-
 // TestScoreInspectFilters (from score_handler) tests changing the filters to
 // values that are possible for the leaderboard to display.
 func TestScoreInspectFilters(t *testing.T) {
@@ -348,3 +279,2252 @@ func TestGenerateRandomString(t *testing.T) {
 		})
 	}
 }
+
+// TestEventImportFormat (from event_handler) tests determining the format of
+// an import of events.
+func TestEventImportFormat(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name     string
+		format   string
+		filename string
+		content  string
+		want     string
+	}{
+		{
+			name:    "#1 CHOSEN FORMAT",
+			format:  "JSON",
+			content: "Mauerfall,1989",
+			want:    "json",
+		},
+		{
+			name:     "#2 FILE EXTENSION",
+			filename: "events.json",
+			content:  "",
+			want:     "json",
+		},
+		{
+			name:    "#3 JSON CONTENT",
+			content: "  [{\"name\": \"Mauerfall\", \"year\": 1989}]",
+			want:    "json",
+		},
+		{
+			name:    "#4 CSV CONTENT",
+			content: "Mauerfall;1989",
+			want:    "csv",
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := importFormat(test.format, test.filename, []byte(test.content)); got != test.want {
+				t.Errorf("importFormat() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestEventParseEventsImport (from event_handler) tests parsing and validating
+// the content of an import of events.
+func TestEventParseEventsImport(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name        string
+		content     string
+		format      string
+		wantRows    int
+		wantInvalid int
+		wantError   bool
+	}{
+		{
+			name:        "#1 CSV WITH HEADER",
+			content:     "name,year\nMauerfall,09.11.1989\nMondlandung,1969\n",
+			format:      "csv",
+			wantRows:    2,
+			wantInvalid: 0,
+		},
+		{
+			name:        "#2 CSV WITH SEMICOLON",
+			content:     "Mauerfall;11.1989\nMondlandung;1969",
+			format:      "csv",
+			wantRows:    2,
+			wantInvalid: 0,
+		},
+		{
+			name:        "#3 CSV WITH INVALID ROWS",
+			content:     "Mauerfall,1989\n,1969\nZukunft,3000\nOhne Jahr",
+			format:      "csv",
+			wantRows:    4,
+			wantInvalid: 3,
+		},
+		{
+			name:        "#4 JSON",
+			content:     `[{"name": "Mauerfall", "year": 1989}, {"name": "Mondlandung", "date": "20.07.1969"}]`,
+			format:      "json",
+			wantRows:    2,
+			wantInvalid: 0,
+		},
+		{
+			name:        "#5 JSON WITH INVALID ROWS",
+			content:     `[{"name": "Mauerfall", "year": "abc"}, {"year": 1969}]`,
+			format:      "json",
+			wantRows:    2,
+			wantInvalid: 2,
+		},
+		{
+			name:      "#6 INVALID JSON",
+			content:   `{"name": "Mauerfall"`,
+			format:    "json",
+			wantError: true,
+		},
+		{
+			name:      "#7 EMPTY",
+			content:   "name,year\n",
+			format:    "csv",
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, err := parseEventsImport([]byte(test.content), test.format)
+
+			if (err != nil) != test.wantError {
+				t.Errorf("parseEventsImport() error = %v, want error %v", err, test.wantError)
+				return
+			}
+
+			var invalid int
+			for _, row := range rows {
+				if len(row.Errors) > 0 {
+					invalid++
+				}
+			}
+			if len(rows) != test.wantRows || invalid != test.wantInvalid {
+				t.Errorf("parseEventsImport() rows, invalid = %v, %v, want %v, %v", len(rows), invalid,
+					test.wantRows, test.wantInvalid)
+			}
+		})
+	}
+}
+
+// TestTopicParseTopicBundle (from topic_handler) tests parsing and validating
+// an exported topic, including a round trip through newTopicBundle.
+func TestTopicParseTopicBundle(t *testing.T) {
+
+	// Mock topic to be exported
+	topic := x.Topic{
+		Name:      "Kalter Krieg",
+		StartYear: 1947,
+		EndYear:   1991,
+		Image:     "https://image.png",
+		Events: []x.Event{
+			{Name: "Mauerbau", Year: 1961, Date: time.Date(1961, 8, 13, 0, 0, 0, 0, time.UTC),
+				Description: "Bau der Berliner Mauer", Source: "https://source.com/mauerbau"},
+			{Name: "Mauerfall", Year: 1989, Date: time.Date(1989, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	exported, _ := json.Marshal(newTopicBundle(topic))
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		content    string
+		wantEvents []x.Event
+		wantError  bool
+	}{
+		{
+			name:       "#1 ROUND TRIP",
+			content:    string(exported),
+			wantEvents: topic.Events,
+		},
+		{
+			name:      "#2 WRONG VERSION",
+			content:   `{"version": 99, "topic": {"name": "Kalter Krieg"}}`,
+			wantError: true,
+		},
+		{
+			name:      "#3 INVALID TOPIC",
+			content:   `{"version": 1, "topic": {"name": "", "start_year": 1947, "end_year": 1991}}`,
+			wantError: true,
+		},
+		{
+			name: "#4 INVALID EVENT",
+			content: `{"version": 1, "topic": {"name": "Kalter Krieg", "start_year": 1947, "end_year": 1991, ` +
+				`"image": "https://image.png"}, "events": [{"name": "", "year": 1961, "date": "1961-08-13"}]}`,
+			wantError: true,
+		},
+		{
+			name:      "#5 INVALID JSON",
+			content:   `{"version": 1`,
+			wantError: true,
+		},
+		{
+			name: "#6 INVALID SOURCE OF EVENT",
+			content: `{"version": 1, "topic": {"name": "Kalter Krieg", "start_year": 1947, "end_year": 1991, ` +
+				`"image": "https://image.png"}, "events": [{"name": "Mauerbau", "year": 1961, "source": "source"}]}`,
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseTopicBundle([]byte(test.content))
+
+			if (err != nil) != test.wantError {
+				t.Errorf("parseTopicBundle() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got.Events, test.wantEvents) {
+				t.Errorf("parseTopicBundle() events = %v, want %v", got.Events, test.wantEvents)
+			}
+		})
+	}
+}
+
+// TestTopicUniqueTopicName (from topic_handler) tests avoiding name conflicts
+// when importing a topic.
+func TestTopicUniqueTopicName(t *testing.T) {
+
+	topics := []x.Topic{
+		{Name: "Mittelalter"},
+		{Name: "Mittelalter (2)"},
+		{Name: "Antike"},
+		{Name: "Lorem ipsum dolor sit amet, consectetuer adipisci"},
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "#1 NO CONFLICT",
+			input: "Neuzeit",
+			want:  "Neuzeit",
+		},
+		{
+			name:  "#2 CONFLICT",
+			input: "Antike",
+			want:  "Antike (2)",
+		},
+		{
+			name:  "#3 MULTIPLE CONFLICTS",
+			input: "mittelalter",
+			want:  "mittelalter (3)",
+		},
+		{
+			name:  "#4 MAX LENGTH",
+			input: "Lorem ipsum dolor sit amet, consectetuer adipisci",
+			want:  "Lorem ipsum dolor sit amet, consectetuer adipi (2)",
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := uniqueTopicName(test.input, topics); got != test.want {
+				t.Errorf("uniqueTopicName() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestTopicCreateAnkiDeck (from topic_study_handler) tests generating a deck of
+// flashcards in the plain text format of Anki.
+func TestTopicCreateAnkiDeck(t *testing.T) {
+
+	topic := x.Topic{
+		Name: "Kalter Krieg",
+		Events: []x.Event{
+			{Name: "Mauerbau", Year: 1961, Date: time.Date(1961, 8, 13, 0, 0, 0, 0, time.UTC),
+				Description: "Bau der Berliner Mauer", Source: "https://source.com/mauerbau"},
+			{Name: "Mauerfall", Year: 1989, Date: time.Date(1989, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	want := "#separator:tab\n#html:false\n#notetype:Basic\n#deck:Jahreszahlen::Kalter Krieg\n#tags column:3\n" +
+		"Mauerbau\t13.08.1961\tKalter_Krieg\n" +
+		"Mauerfall\t1989\tKalter_Krieg\n"
+
+	got, err := createAnkiDeck(topic)
+	if err != nil {
+		t.Errorf("createAnkiDeck() error = %v", err)
+		return
+	}
+	if string(got) != want {
+		t.Errorf("createAnkiDeck() = %q, want %q", got, want)
+	}
+}
+
+// TestTopicCreateWorksheetAnswers (from topic_study_handler) tests generating
+// the answer key of a worksheet.
+func TestTopicCreateWorksheetAnswers(t *testing.T) {
+
+	var events []x.Event
+	for year := 1900; year < 1920; year++ {
+		events = append(events, x.Event{Name: strconv.Itoa(year), Year: year, Date: time.Date(year, 1, 1, 0, 0, 0,
+			0, time.UTC)})
+	}
+
+	questions, events := createTimelineQuestions(events, 12)
+	answers := createWorksheetAnswers(questions, events)
+
+	if len(answers) != 12 {
+		t.Errorf("createWorksheetAnswers() len = %v, want %v", len(answers), 12)
+		return
+	}
+
+	// Answers must be in chronological order and each letter must point to
+	// the correct event of the exercise
+	for i, answer := range answers {
+		if i > 0 && answer.YearOrDate <= answers[i-1].YearOrDate {
+			t.Errorf("createWorksheetAnswers() not in chronological order: %v", answers)
+			return
+		}
+		question := questions[answer.Letter[0]-'A']
+		if question.EventName != answer.EventName || question.Order != i {
+			t.Errorf("createWorksheetAnswers() letter %v = %v, want %v", answer.Letter, question.EventName,
+				answer.EventName)
+		}
+	}
+}
+
+// TestImageValidateImageUpload (from images) tests validating the content of
+// an uploaded image.
+func TestImageValidateImageUpload(t *testing.T) {
+
+	// Mock images
+	var pngImage, gifImage, hugeImage bytes.Buffer
+	_ = png.Encode(&pngImage, image.NewRGBA(image.Rect(0, 0, 800, 600)))
+	_ = gif.Encode(&gifImage, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.Black}), nil)
+	_ = png.Encode(&hugeImage, image.NewGray(image.Rect(0, 0, imageMaxDimension+1, 1)))
+
+	// Declare test cases
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{
+			name:    "#1 OK (PNG)",
+			content: pngImage.Bytes(),
+			want:    true,
+		},
+		{
+			name:    "#2 OK (GIF)",
+			content: gifImage.Bytes(),
+			want:    true,
+		},
+		{
+			name:    "#3 EMPTY",
+			content: []byte{},
+			want:    false,
+		},
+		{
+			name:    "#4 WRONG TYPE",
+			content: []byte("<html><body>not an image</body></html>"),
+			want:    false,
+		},
+		{
+			name:    "#5 CORRUPTED",
+			content: pngImage.Bytes()[:20],
+			want:    false,
+		},
+		{
+			name:    "#6 DIMENSIONS TOO BIG",
+			content: hugeImage.Bytes(),
+			want:    false,
+		},
+		{
+			name:    "#7 FILE TOO BIG",
+			content: append(pngImage.Bytes(), make([]byte, imageMaxSize)...),
+			want:    false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := validateImageUpload(test.content); (got == "") != test.want {
+				t.Errorf("validateImageUpload() = %v, want valid %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestImageCreateThumbnail (from images) tests generating a downscaled
+// thumbnail of an image.
+func TestImageCreateThumbnail(t *testing.T) {
+
+	// Mock images
+	var wideImage, smallImage bytes.Buffer
+	_ = png.Encode(&wideImage, image.NewRGBA(image.Rect(0, 0, 1600, 800)))
+	_ = png.Encode(&smallImage, image.NewRGBA(image.Rect(0, 0, 100, 50)))
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		content    []byte
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:       "#1 DOWNSCALED",
+			content:    wideImage.Bytes(),
+			wantWidth:  thumbnailWidth,
+			wantHeight: thumbnailWidth / 2,
+		},
+		{
+			name:       "#2 NOT UPSCALED",
+			content:    smallImage.Bytes(),
+			wantWidth:  100,
+			wantHeight: 50,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			thumbnail, err := createThumbnail(test.content)
+			if err != nil {
+				t.Errorf("createThumbnail() error = %v", err)
+				return
+			}
+
+			config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+			if err != nil || format != "jpeg" || config.Width != test.wantWidth || config.Height != test.wantHeight {
+				t.Errorf("createThumbnail() = %v %vx%v, want jpeg %vx%v", format, config.Width, config.Height,
+					test.wantWidth, test.wantHeight)
+			}
+		})
+	}
+}
+
+// TestImageThumbnailURL (from images) tests getting the URL of the thumbnail
+// of an image.
+func TestImageThumbnailURL(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{
+			name:  "#1 UPLOADED",
+			image: "/" + uploadPath + "/abc.png",
+			want:  "/" + uploadPath + "/abc_thumb.jpg",
+		},
+		{
+			name:  "#2 EXTERNAL",
+			image: "https://image.png",
+			want:  "https://image.png",
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := thumbnailURL(test.image); got != test.want {
+				t.Errorf("thumbnailURL() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestAuditParseAuditFilter (from audit) tests converting a URL query into a
+// filter of the audit log.
+func TestAuditParseAuditFilter(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		query      string
+		wantForm   auditFilterForm
+		wantFilter x.AuditFilter
+	}{
+		{
+			name:  "#1 OK",
+			query: "user=+admin+&action=update&type=event&target=5&from=2021-03-01&to=2021-03-31",
+			wantForm: auditFilterForm{User: "admin", Action: "update", Type: "event", TargetID: "5",
+				From: "2021-03-01", To: "2021-03-31"},
+			wantFilter: x.AuditFilter{UserName: "admin", Action: "update", TargetType: "event", TargetID: 5,
+				From: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "#2 EMPTY",
+			query:      "",
+			wantForm:   auditFilterForm{},
+			wantFilter: x.AuditFilter{},
+		},
+		{
+			name:       "#3 INVALID VALUES",
+			query:      "action=hack&type=score&target=-1&from=01.03.2021&to=tomorrow",
+			wantForm:   auditFilterForm{},
+			wantFilter: x.AuditFilter{},
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, _ := neturl.ParseQuery(test.query)
+
+			form, filter := parseAuditFilter(query)
+			if form != test.wantForm {
+				t.Errorf("parseAuditFilter() form = %v, want %v", form, test.wantForm)
+			}
+			if !reflect.DeepEqual(filter, test.wantFilter) {
+				t.Errorf("parseAuditFilter() filter = %v, want %v", filter, test.wantFilter)
+			}
+		})
+	}
+}
+
+// TestAuditSnapshot (from audit) tests converting the target of an audit entry
+// to JSON.
+func TestAuditSnapshot(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name   string
+		target interface{}
+		want   string
+	}{
+		{
+			name:   "#1 NIL",
+			target: nil,
+			want:   "",
+		},
+		{
+			name:   "#2 EVENT",
+			target: x.Event{EventID: 1, TopicID: 2, Name: "Event", Year: 1500},
+			want: `{"EventID":1,"TopicID":2,"Name":"Event","Year":1500,"Date":"0001-01-01T00:00:00Z",` +
+				`"Description":"","Source":"","Image":"","DeletedAt":null,"Translations":null}`,
+		},
+		{
+			name:   "#3 USER WITHOUT PASSWORD",
+			target: x.User{UserID: 1, Username: "user", Password: "$2a$10$hash"},
+			want: `{"UserID":1,"Username":"user","Email":"","Password":"","Admin":false,"Verified":false,` +
+				`"Locale":"","Class":"","DeletedAt":null,"ScoresCount":0}`,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := auditSnapshot(test.target); got != test.want {
+				t.Errorf("auditSnapshot() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestAuditClientIP (from audit) tests getting the IP-address of the client
+// of a request.
+func TestAuditClientIP(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		trustProxy bool
+		want       string
+	}{
+		{
+			name:       "#1 REMOTE ADDRESS",
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "#2 FORWARDED",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "203.0.113.7",
+			trustProxy: true,
+			want:       "203.0.113.7",
+		},
+		{
+			// When the client sends a forged header, to which the proxy
+			// appends the actual address
+			name:       "#3 FORGED FORWARDED",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "198.51.100.9, 203.0.113.7",
+			trustProxy: true,
+			want:       "203.0.113.7",
+		},
+		{
+			// When the client sends a forged header without a proxy
+			name:       "#4 UNTRUSTED FORWARDED",
+			remoteAddr: "192.0.2.1:1234",
+			forwarded:  "198.51.100.9",
+			trustProxy: false,
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "#5 NO PORT",
+			remoteAddr: "192.0.2.1",
+			want:       "192.0.2.1",
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", test.forwarded)
+			}
+
+			if got := clientIP(req, test.trustProxy); got != test.want {
+				t.Errorf("clientIP() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestTrashDaysLeft (from trash) tests calculating the remaining days until an
+// item in the trash gets purged.
+func TestTrashDaysLeft(t *testing.T) {
+
+	now := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	deletedNow := now
+	deletedHalfDayAgo := now.Add(-12 * time.Hour)
+	deletedExpired := now.Add(-trashRetention - time.Hour)
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		deletedAt *time.Time
+		want      int
+	}{
+		{
+			name:      "#1 JUST DELETED",
+			deletedAt: &deletedNow,
+			want:      30,
+		},
+		{
+			name:      "#2 ROUNDED UP",
+			deletedAt: &deletedHalfDayAgo,
+			want:      30,
+		},
+		{
+			name:      "#3 EXPIRED",
+			deletedAt: &deletedExpired,
+			want:      0,
+		},
+		{
+			name:      "#4 NOT DELETED",
+			deletedAt: nil,
+			want:      0,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := trashDaysLeft(test.deletedAt, now); got != test.want {
+				t.Errorf("trashDaysLeft() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRevisionSnapshot (from revisions) tests converting an event or topic to
+// the snapshot of a revision.
+func TestRevisionSnapshot(t *testing.T) {
+
+	deletedAt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		target    interface{}
+		want      string
+		wantError bool
+	}{
+		{
+			name:   "#1 EVENT",
+			target: x.Event{EventID: 1, TopicID: 2, Name: "Event", Year: 1500, DeletedAt: &deletedAt},
+			want: `{"EventID":1,"TopicID":2,"Name":"Event","Year":1500,"Date":"0001-01-01T00:00:00Z",` +
+				`"Description":"","Source":"","Image":"","DeletedAt":null,"Translations":null}`,
+			wantError: false,
+		},
+		{
+			name: "#2 TOPIC WITHOUT EVENTS",
+			target: x.Topic{TopicID: 2, ParentID: 3, Name: "Topic", StartYear: 1400, EndYear: 1600,
+				Events: []x.Event{{EventID: 1}}, ScoresCount: 5, EventsCount: 1},
+			want: `{"TopicID":2,"ParentID":0,"Name":"Topic","StartYear":1400,"EndYear":1600,"Description":"","Image":"",` +
+				`"DeletedAt":null,"Events":null,"Translations":null,"ScoresCount":0,"EventsCount":0}`,
+			wantError: false,
+		},
+		{
+			name:      "#3 UNSUPPORTED",
+			target:    x.User{UserID: 1},
+			want:      "",
+			wantError: true,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := revisionSnapshot(test.target)
+			if (err != nil) != test.wantError {
+				t.Errorf("revisionSnapshot() error = %v, want error %v", err, test.wantError)
+				return
+			}
+			if got != test.want {
+				t.Errorf("revisionSnapshot() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRevisionDiff (from revisions) tests comparing the fields of two
+// revisions.
+func TestRevisionDiff(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name     string
+		previous []revisionField
+		current  []revisionField
+		want     []revisionChange
+	}{
+		{
+			name:     "#1 CHANGED",
+			previous: []revisionField{{"Ereignis", "Event"}, {"Jahr", "1515"}, {"Quelle", ""}},
+			current:  []revisionField{{"Ereignis", "Event"}, {"Jahr", "1515"}, {"Quelle", "https://source.ch"}},
+			want:     []revisionChange{{Label: "Quelle", Previous: "", Current: "https://source.ch"}},
+		},
+		{
+			name:     "#2 UNCHANGED",
+			previous: []revisionField{{"Ereignis", "Event"}, {"Jahr", "1515"}},
+			current:  []revisionField{{"Ereignis", "Event"}, {"Jahr", "1515"}},
+			want:     nil,
+		},
+		{
+			name:     "#3 FIRST REVISION",
+			previous: nil,
+			current:  []revisionField{{"Ereignis", "Event"}, {"Beschreibung", ""}},
+			want:     []revisionChange{{Label: "Ereignis", Previous: "", Current: "Event"}},
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := revisionDiff(test.previous, test.current); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("revisionDiff() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRevisionHistory (from revisions) tests comparing every revision of an
+// event with the revision before it.
+func TestRevisionHistory(t *testing.T) {
+
+	revisions := []x.Revision{
+		{RevisionID: 3, TargetType: auditEvent, Snapshot: `{"Name":"Event","Year":1515,"Date":"1515-09-13T00:00:00Z"}`},
+		{RevisionID: 2, TargetType: auditEvent, Snapshot: `{"Name":"Event","Year":1515,"Date":"1515-09-13T00:00:00Z"}`},
+		{RevisionID: 1, TargetType: auditEvent, Snapshot: `{"Name":"Event","Year":1551,"Date":"1551-09-13T00:00:00Z"}`},
+	}
+
+	history, err := revisionHistory(revisions)
+	if err != nil {
+		t.Fatalf("revisionHistory() error = %v", err)
+	}
+
+	if len(history) != 3 || !history[0].Current || history[1].Current || history[2].Current {
+		t.Fatalf("revisionHistory() = %v, want 3 revisions with the first being current", history)
+	}
+	if history[0].Changes != nil {
+		t.Errorf("revisionHistory() changes of #3 = %v, want none", history[0].Changes)
+	}
+	if want := []revisionChange{
+		{Label: "Jahr", Previous: "1551", Current: "1515"},
+		{Label: "Datum", Previous: "13.09.1551", Current: "13.09.1515"},
+	}; !reflect.DeepEqual(history[1].Changes, want) {
+		t.Errorf("revisionHistory() changes of #2 = %v, want %v", history[1].Changes, want)
+	}
+	if len(history[2].Changes) != 3 { // name, year and date of the first revision
+		t.Errorf("revisionHistory() changes of #1 = %v, want 3 changes", history[2].Changes)
+	}
+
+	if _, err = revisionHistory([]x.Revision{{TargetType: auditEvent, Snapshot: "{"}}); err == nil {
+		t.Errorf("revisionHistory() error = nil, want error for invalid snapshot")
+	}
+}
+
+// TestFindEvent (from suggestion) tests finding an event by ID in an array of
+// events.
+func TestFindEvent(t *testing.T) {
+
+	events := []x.Event{{EventID: 1, Name: "Event 1"}, {EventID: 2, Name: "Event 2"}}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		eventID   int
+		wantEvent x.Event
+		wantFound bool
+	}{
+		{
+			name:      "#1 FOUND",
+			eventID:   2,
+			wantEvent: events[1],
+			wantFound: true,
+		},
+		{
+			name:      "#2 NOT FOUND",
+			eventID:   3,
+			wantEvent: x.Event{},
+			wantFound: false,
+		},
+		{
+			name:      "#3 NO EVENT",
+			eventID:   0,
+			wantEvent: x.Event{},
+			wantFound: false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event, found := findEvent(events, test.eventID)
+			if found != test.wantFound || !reflect.DeepEqual(event, test.wantEvent) {
+				t.Errorf("findEvent() = %v, %v, want %v, %v", event, found, test.wantEvent, test.wantFound)
+			}
+		})
+	}
+}
+
+// TestNormalizeName (from duplicates) tests normalizing the name of an event.
+func TestNormalizeName(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "#1 LOWERCASE", input: "Mauerfall", want: "mauerfall"},
+		{name: "#2 UMLAUTS", input: "Schlacht bei Marignano (Rückzug)", want: "schlacht bei marignano rueckzug"},
+		{name: "#3 ESZETT", input: "Großer Krieg", want: "grosser krieg"},
+		{name: "#4 PUNCTUATION", input: "  Fall der Berliner   Mauer! ", want: "fall der berliner mauer"},
+		{name: "#5 EMPTY", input: "", want: ""},
+		{name: "#6 ACCENTS", input: "Französische Révolution", want: "franzoesische revolution"},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := normalizeName(test.input); got != test.want {
+				t.Errorf("normalizeName() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestNameSimilarity (from duplicates) tests calculating the similarity of two
+// names.
+func TestNameSimilarity(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name  string
+		name1 string
+		name2 string
+		want  float64
+	}{
+		{name: "#1 IDENTICAL", name1: "mauerfall", name2: "mauerfall", want: 1},
+		{name: "#2 TYPO", name1: "mauerfall", name2: "mauerfal", want: 1 - 1.0/9},
+		{name: "#3 DIFFERENT", name1: "abc", name2: "xyz", want: 0},
+		{name: "#4 EMPTY", name1: "", name2: "", want: 1},
+		{name: "#5 UNICODE", name1: "é", name2: "e", want: 0},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := nameSimilarity(test.name1, test.name2); got != test.want {
+				t.Errorf("nameSimilarity() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestFindDuplicates (from duplicates) tests finding existing events, which a
+// new event seems to duplicate.
+func TestFindDuplicates(t *testing.T) {
+
+	events := []x.Event{
+		{EventID: 1, Name: "Mauerfall", Year: 1989},
+		{EventID: 2, Name: "Fall der Berliner Mauer", Year: 1989},
+		{EventID: 3, Name: "Mondlandung", Year: 1969},
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		eventName string
+		eventYear int
+		want      []eventDuplicate
+	}{
+		{
+			name:      "#1 EXACT",
+			eventName: "mauerfall!",
+			eventYear: 1989,
+			want:      []eventDuplicate{{EventID: 1, Name: "Mauerfall", Year: 1989, Exact: true}},
+		},
+		{
+			name:      "#2 SIMILAR",
+			eventName: "Fall der Berliner Mauern",
+			eventYear: 1989,
+			want:      []eventDuplicate{{EventID: 2, Name: "Fall der Berliner Mauer", Year: 1989}},
+		},
+		{
+			name:      "#3 SAME NAME, DIFFERENT YEAR",
+			eventName: "Mondlandung",
+			eventYear: 1970,
+			want:      []eventDuplicate{{EventID: 3, Name: "Mondlandung", Year: 1969}},
+		},
+		{
+			name:      "#4 SIMILAR NAME, DIFFERENT YEAR",
+			eventName: "Mondlandungen",
+			eventYear: 1970,
+			want:      nil,
+		},
+		{
+			name:      "#5 NO DUPLICATE",
+			eventName: "Französische Revolution",
+			eventYear: 1789,
+			want:      nil,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := findDuplicates(test.eventName, test.eventYear, events); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("findDuplicates() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestFindDuplicatePairs (from duplicates) tests finding all pairs of events
+// of a topic, which seem to duplicate each other.
+func TestFindDuplicatePairs(t *testing.T) {
+
+	events := []x.Event{
+		{EventID: 1, Name: "Mauerfall", Year: 1989},
+		{EventID: 2, Name: "Mondlandung", Year: 1969},
+		{EventID: 3, Name: "Mauerfal", Year: 1989},
+		{EventID: 4, Name: "Mondlandung", Year: 1969},
+	}
+
+	pairs := findDuplicatePairs(events)
+
+	if len(pairs) != 2 {
+		t.Fatalf("findDuplicatePairs() = %v, want 2 pairs", pairs)
+	}
+	if !pairs[0].Exact || pairs[0].First.EventID != 2 || pairs[0].Second.EventID != 4 ||
+		pairs[0].Similarity != 100 {
+		t.Errorf("findDuplicatePairs()[0] = %v, want exact pair of events 2 and 4", pairs[0])
+	}
+	if pairs[1].Exact || pairs[1].First.EventID != 1 || pairs[1].Second.EventID != 3 ||
+		pairs[1].Similarity != 88 {
+		t.Errorf("findDuplicatePairs()[1] = %v, want similar pair of events 1 and 3", pairs[1])
+	}
+}
+
+// TestMarkImportDuplicates (from duplicates) tests adding the duplicates of
+// every valid row of an import.
+func TestMarkImportDuplicates(t *testing.T) {
+
+	events := []x.Event{{EventID: 1, Name: "Mauerfall", Year: 1989}}
+	rows := []EventForm{
+		{Name: "Mauerfall", Year: 1989},
+		{Name: "Mondlandung", Year: 1969},
+		{Name: "Mondlandung", Year: 1969},
+		{Name: "Mondlandung", Year: 0, Errors: FormErrors{"Year": "Jahr/Datum darf nicht leer sein."}},
+	}
+
+	markImportDuplicates(rows, events)
+
+	want := [][]eventDuplicate{
+		{{EventID: 1, Name: "Mauerfall", Year: 1989, Exact: true}},
+		nil,
+		{{Row: 2, Name: "Mondlandung", Year: 1969, Exact: true}},
+		nil,
+	}
+	for i, row := range rows {
+		if !reflect.DeepEqual(row.Duplicates, want[i]) {
+			t.Errorf("markImportDuplicates() row %v = %v, want %v", i+1, row.Duplicates, want[i])
+		}
+	}
+}
+
+// TestFilterTopics (from topic) tests filtering topics by a tag and by a range
+// of years.
+func TestFilterTopics(t *testing.T) {
+
+	topics := []x.Topic{
+		{TopicID: 1, Name: "Antike", StartYear: 100, EndYear: 476},
+		{TopicID: 2, Name: "Reformation", StartYear: 1517, EndYear: 1648},
+		{TopicID: 3, Name: "Kalter Krieg", StartYear: 1947, EndYear: 1991},
+	}
+	topicTags := map[int][]x.Tag{
+		2: {{TagID: 1, Name: "Schweizer Geschichte"}},
+		3: {{TagID: 1, Name: "Schweizer Geschichte"}, {TagID: 2, Name: "20. Jahrhundert"}},
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name    string
+		filter  topicFilter
+		wantIDs []int
+	}{
+		{name: "#1 NO FILTER", filter: topicFilter{}, wantIDs: []int{1, 2, 3}},
+		{name: "#2 TAG", filter: topicFilter{TagID: 1}, wantIDs: []int{2, 3}},
+		{name: "#3 FROM", filter: topicFilter{From: 1600}, wantIDs: []int{2, 3}},
+		{name: "#4 TO", filter: topicFilter{To: 1517}, wantIDs: []int{1, 2}},
+		{name: "#5 RANGE", filter: topicFilter{From: 1900, To: 1950}, wantIDs: []int{3}},
+		{name: "#6 TAG AND RANGE", filter: topicFilter{TagID: 2, To: 1900}, wantIDs: nil},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ids []int
+			for _, topic := range filterTopics(topics, topicTags, test.filter) {
+				ids = append(ids, topic.TopicID)
+			}
+			if !reflect.DeepEqual(ids, test.wantIDs) {
+				t.Errorf("filterTopics() = %v, want %v", ids, test.wantIDs)
+			}
+		})
+	}
+}
+
+// tTopicTree is a hierarchy of mock topics for testing purposes:
+// Zweiter Weltkrieg (1) > Ostfront (2) > Stalingrad (3), Zweiter Weltkrieg (1)
+// > Pazifikkrieg (4), Kalter Krieg (5), as well as Mondlandung (6), whose
+// parent topic is missing.
+var tTopicTree = []x.Topic{
+	{TopicID: 1, Name: "Zweiter Weltkrieg", EventsCount: 10},
+	{TopicID: 2, ParentID: 1, Name: "Ostfront", EventsCount: 5},
+	{TopicID: 4, ParentID: 1, Name: "Pazifikkrieg", EventsCount: 3},
+	{TopicID: 5, Name: "Kalter Krieg", EventsCount: 8},
+	{TopicID: 3, ParentID: 2, Name: "Stalingrad", EventsCount: 2},
+	{TopicID: 6, ParentID: 9, Name: "Mondlandung", EventsCount: 1},
+}
+
+// TestTopicTree (from topic_tree) tests flattening the hierarchy of topics.
+func TestTopicTree(t *testing.T) {
+
+	type node struct {
+		TopicID     int
+		Depth       int
+		EventsTotal int
+		HasChildren bool
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name   string
+		topics []x.Topic
+		want   []node
+	}{
+		{
+			name:   "#1 HIERARCHY",
+			topics: tTopicTree,
+			want: []node{
+				{1, 0, 20, true}, {2, 1, 7, true}, {3, 2, 2, false}, {4, 1, 3, false}, {5, 0, 8, false},
+				{6, 0, 1, false},
+			},
+		},
+		{
+			name: "#2 CYCLE",
+			topics: []x.Topic{
+				{TopicID: 1, ParentID: 2, EventsCount: 1},
+				{TopicID: 2, ParentID: 1, EventsCount: 1},
+			},
+			want: []node{{1, 0, 2, true}, {2, 1, 2, true}},
+		},
+		{
+			name:   "#3 NO TOPICS",
+			topics: nil,
+			want:   nil,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []node
+			for _, n := range topicTree(test.topics) {
+				got = append(got, node{n.TopicID, n.Depth, n.EventsTotal, n.HasChildren})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("topicTree() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestTopicSubtree (from topic_tree) tests getting the tree of all descendants
+// of a topic.
+func TestTopicSubtree(t *testing.T) {
+
+	var got [][]int
+	for _, n := range topicSubtree(tTopicTree, 1) {
+		got = append(got, []int{n.TopicID, n.Depth})
+	}
+
+	want := [][]int{{2, 0}, {3, 1}, {4, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("topicSubtree() = %v, want %v", got, want)
+	}
+	if subtree := topicSubtree(tTopicTree, 5); subtree != nil {
+		t.Errorf("topicSubtree() = %v, want nil", subtree)
+	}
+}
+
+// TestTopicEventsTotals (from topic_tree) tests aggregating the amount of
+// events of topics and their descendants.
+func TestTopicEventsTotals(t *testing.T) {
+
+	want := map[int]int{1: 20, 2: 7, 3: 2, 4: 3, 5: 8, 6: 1}
+	if got := topicEventsTotals(tTopicTree); !reflect.DeepEqual(got, want) {
+		t.Errorf("topicEventsTotals() = %v, want %v", got, want)
+	}
+}
+
+// TestTopicDescendantIDs (from topic_tree) tests getting the IDs of all
+// sub-topics of a topic.
+func TestTopicDescendantIDs(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name    string
+		topicID int
+		want    []int
+	}{
+		{name: "#1 PARENT", topicID: 1, want: []int{2, 4, 3}},
+		{name: "#2 SUB-TOPIC", topicID: 2, want: []int{3}},
+		{name: "#3 NO SUB-TOPICS", topicID: 5, want: nil},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := topicDescendantIDs(tTopicTree, test.topicID); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("topicDescendantIDs() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestTopicAncestors (from topic_tree) tests getting the parent topics of a
+// topic for breadcrumbs.
+func TestTopicAncestors(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name    string
+		topicID int
+		want    []string
+	}{
+		{name: "#1 NESTED", topicID: 3, want: []string{"Zweiter Weltkrieg", "Ostfront"}},
+		{name: "#2 TOP-LEVEL", topicID: 1, want: nil},
+		{name: "#3 PARENT MISSING", topicID: 6, want: nil},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, ancestor := range topicAncestors(tTopicTree, topicByID(tTopicTree, test.topicID)) {
+				got = append(got, ancestor.Name)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("topicAncestors() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestValidParent (from topic_tree) tests validating the parent topic of a
+// topic, which mustn't cause a cycle.
+func TestValidParent(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name     string
+		topicID  int
+		parentID int
+		want     bool
+	}{
+		{name: "#1 TOP-LEVEL", topicID: 2, parentID: 0, want: true},
+		{name: "#2 OTHER TOPIC", topicID: 5, parentID: 3, want: true},
+		{name: "#3 NEW TOPIC", topicID: 0, parentID: 1, want: true},
+		{name: "#4 ITSELF", topicID: 2, parentID: 2, want: false},
+		{name: "#5 DESCENDANT", topicID: 1, parentID: 3, want: false},
+		{name: "#6 MISSING", topicID: 5, parentID: 9, want: false},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := validParent(tTopicTree, test.topicID, test.parentID); got != test.want {
+				t.Errorf("validParent() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestParseSearchQuery (from search) tests parsing a search-query into words
+// and a range of years.
+func TestParseSearchQuery(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		raw  string
+		want searchQuery
+	}{
+		{name: "#1 WORDS", raw: "Der Zweite Weltkrieg", want: searchQuery{Terms: []string{"zweite", "weltkrieg"}}},
+		{name: "#2 UMLAUTS", raw: "Französische Révolution", want: searchQuery{
+			Terms: []string{"franzoesische", "revolution"}}},
+		{name: "#3 RANGE", raw: "Krieg 1914-1918", want: searchQuery{Terms: []string{"krieg"}, From: 1914,
+			To: 1918}},
+		{name: "#4 RANGE WITH WORD", raw: "1918 bis 1914", want: searchQuery{From: 1914, To: 1918}},
+		{name: "#5 SINGLE YEAR", raw: "Mauerfall 1989", want: searchQuery{Terms: []string{"mauerfall"},
+			From: 1989, To: 1989}},
+		{name: "#6 EMPTY", raw: "  ", want: searchQuery{}},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseSearchQuery(test.raw); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseSearchQuery() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestMatchTerm (from search) tests matching a word of a search-query.
+func TestMatchTerm(t *testing.T) {
+
+	words := []string{"zweiter", "weltkrieg"}
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		term string
+		want float64
+	}{
+		{name: "#1 EXACT", term: "weltkrieg", want: 1},
+		{name: "#2 PREFIX", term: "welt", want: 0.8},
+		{name: "#3 COMPOUND", term: "krieg", want: 0.6},
+		{name: "#4 TYPO", term: "weltkreig", want: 0.5},
+		{name: "#5 PREFIX WITH TYPO", term: "wdltk", want: 0.4},
+		{name: "#6 SHORT WORD WITHOUT TYPOS", term: "zwi", want: 0},
+		{name: "#7 NO MATCH", term: "mauerfall", want: 0},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := matchTerm(test.term, words); got != test.want {
+				t.Errorf("matchTerm() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestSearch (from search) tests finding and ranking topics, events and pages.
+func TestSearch(t *testing.T) {
+
+	topics := []x.Topic{
+		{TopicID: 1, Name: "Erster Weltkrieg", StartYear: 1914, EndYear: 1918},
+		{TopicID: 2, Name: "Kalter Krieg", StartYear: 1947, EndYear: 1991, Description: "Konflikt der Supermächte"},
+	}
+	events := []x.Event{
+		{EventID: 1, TopicID: 1, Name: "Attentat von Sarajevo", Year: 1914},
+		{EventID: 2, TopicID: 2, Name: "Bau der Berliner Mauer", Year: 1961},
+		{EventID: 3, TopicID: 2, Name: "Fall der Berliner Mauer", Year: 1989},
+	}
+
+	user := searchPagesOf(x.User{UserID: 1}, true)
+	visitor := searchPagesOf(x.User{}, false)
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		query      string
+		pages      map[string]bool
+		wantTitles []string
+	}{
+		{name: "#1 TOPIC NAME BEFORE DESCRIPTION", query: "krieg", pages: user,
+			wantTitles: []string{"Kalter Krieg", "Erster Weltkrieg"}},
+		{name: "#2 DESCRIPTION WITH UMLAUT", query: "supermaechte", pages: user, wantTitles: []string{"Kalter Krieg"}},
+		{name: "#3 EVENTS WITH TYPO", query: "berlinner maur", pages: user,
+			wantTitles: []string{"Bau der Berliner Mauer", "Fall der Berliner Mauer"}},
+		{name: "#4 YEAR RANGE", query: "1960-1990", pages: user,
+			wantTitles: []string{"Kalter Krieg", "Bau der Berliner Mauer", "Fall der Berliner Mauer"}},
+		{name: "#5 WORDS AND YEAR", query: "mauer 1989", pages: user, wantTitles: []string{"Fall der Berliner Mauer"}},
+		{name: "#6 PAGE", query: "profil", pages: user, wantTitles: []string{"Profil"}},
+		{name: "#7 INACCESSIBLE PAGE", query: "profil", pages: visitor, wantTitles: nil},
+		{name: "#8 NO RESULTS", query: "mondlandung", pages: user, wantTitles: nil},
+		{name: "#9 EMPTY", query: "", pages: user, wantTitles: nil},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var titles []string
+			for _, result := range search(parseSearchQuery(test.query), topics, events, test.pages) {
+				titles = append(titles, result.Title)
+			}
+			if !reflect.DeepEqual(titles, test.wantTitles) {
+				t.Errorf("search() = %v, want %v", titles, test.wantTitles)
+			}
+		})
+	}
+}
+
+// TestSearchIndex (from search_index) tests building the index of the
+// autocompletion and looking up suggestions by prefixes.
+func TestSearchIndex(t *testing.T) {
+
+	index := &searchIndex{}
+	index.build([]x.Topic{
+		{TopicID: 1, Name: "Kalter Krieg", StartYear: 1947, EndYear: 1991},
+		{TopicID: 2, Name: "Mauerbau und Mauerfall", StartYear: 1961, EndYear: 1989},
+	}, []x.Event{
+		{EventID: 1, TopicID: 1, Name: "Bau der Berliner Mauer", Year: 1961},
+		{EventID: 2, TopicID: 1, Name: "Fall der Berliner Mauer", Year: 1989},
+		{EventID: 3, TopicID: 1, Name: "Kubakrise", Year: 1962},
+	})
+
+	user := searchPagesOf(x.User{UserID: 1}, true)
+	visitor := searchPagesOf(x.User{}, false)
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		query      string
+		pages      map[string]bool
+		wantTitles []string
+	}{
+		{name: "#1 PREFIX", query: "kub", pages: user, wantTitles: []string{"Kubakrise"}},
+		{name: "#2 WHOLE WORD FIRST", query: "mauer", pages: user,
+			wantTitles: []string{"Bau der Berliner Mauer", "Fall der Berliner Mauer", "Mauerbau und Mauerfall"}},
+		{name: "#3 ALL WORDS", query: "fall berl", pages: user, wantTitles: []string{"Fall der Berliner Mauer"}},
+		{name: "#4 CASE", query: "KALTER kr", pages: user, wantTitles: []string{"Kalter Krieg"}},
+		{name: "#5 PAGE", query: "profi", pages: user, wantTitles: []string{"Profil"}},
+		{name: "#6 INACCESSIBLE PAGE", query: "profi", pages: visitor, wantTitles: nil},
+		{name: "#7 EMPTY", query: " ", pages: user, wantTitles: nil},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var titles []string
+			for _, suggestion := range index.lookup(test.query, test.pages) {
+				titles = append(titles, suggestion.Title)
+			}
+			if !reflect.DeepEqual(titles, test.wantTitles) {
+				t.Errorf("lookup() = %v, want %v", titles, test.wantTitles)
+			}
+		})
+	}
+
+	// Outdated index
+	index.invalidate()
+	if index.built {
+		t.Errorf("invalidate() built = true, want false")
+	}
+}
+
+// TestTranslate (from i18n) tests translating messages and inserting values.
+func TestTranslate(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name    string
+		locale  string
+		message string
+		values  []interface{}
+		want    string
+	}{
+		{name: "#1 OK", locale: "en", message: "Themen", want: "Topics"},
+		{name: "#2 OK (GERMAN)", locale: "de", message: "Themen", want: "Themen"},
+		{name: "#3 OK (VALUES)", locale: "en", message: "Zeigt %v bis %v von %v", values: []interface{}{1, 10, 42},
+			want: "Showing 1 to 10 of 42"},
+		{name: "#4 OK (VALUES, GERMAN)", locale: "de", message: "Zeigt %v bis %v von %v",
+			values: []interface{}{1, 10, 42}, want: "Zeigt 1 bis 10 von 42"},
+		{name: "#5 UNTRANSLATED", locale: "en", message: "Kalter Krieg", want: "Kalter Krieg"},
+		{name: "#6 UNSUPPORTED LOCALE", locale: "fr", message: "Themen", want: "Themen"},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := translate(test.locale, test.message, test.values...); got != test.want {
+				t.Errorf("translate() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestTranslateText (from i18n) tests translating texts, in which values have
+// already been inserted, such as flash messages and form errors.
+func TestTranslateText(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name   string
+		locale string
+		text   string
+		want   string
+	}{
+		{name: "#1 OK", locale: "en", text: "Name darf nicht leer sein.", want: "Name must not be empty."},
+		{name: "#2 OK (VALUE)", locale: "en", text: "Hallo mqrc81! Sie sind nun eingeloggt.",
+			want: "Hello mqrc81! You are now logged in."},
+		{name: "#3 OK (VALUES)", locale: "en",
+			text: "Da bereits ein Thema 'Antike' existiert, wurde das importierte Thema in 'Antike (2)' umbenannt.",
+			want: "Since a topic 'Antike' already exists, the imported topic was renamed to 'Antike (2)'."},
+		{name: "#4 OK (NESTED)", locale: "en", text: "Ungültiges Thema: Name darf nicht leer sein.",
+			want: "Invalid topic: Name must not be empty."},
+		{name: "#5 GERMAN", locale: "de", text: "Name darf nicht leer sein.", want: "Name darf nicht leer sein."},
+		{name: "#6 UNTRANSLATED", locale: "en", text: "Etwas anderes.", want: "Etwas anderes."},
+		{name: "#7 EMPTY", locale: "en", text: "", want: ""},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := translateText(test.locale, test.text); got != test.want {
+				t.Errorf("translateText() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestNegotiateLocale (from i18n) tests choosing the locale of a request.
+func TestNegotiateLocale(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name           string
+		query          string
+		preference     string
+		session        string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "#1 QUERY", query: "en", preference: "de", session: "de", acceptLanguage: "de", want: "en"},
+		{name: "#2 PREFERENCE", preference: "en", session: "de", acceptLanguage: "de", want: "en"},
+		{name: "#3 SESSION", session: "en", acceptLanguage: "de", want: "en"},
+		{name: "#4 ACCEPT LANGUAGE", acceptLanguage: "en-US,en;q=0.9,de;q=0.8", want: "en"},
+		{name: "#5 ACCEPT LANGUAGE (QUALITY)", acceptLanguage: "fr-CH, fr;q=0.9, en;q=0.5, de;q=0.8", want: "de"},
+		{name: "#6 UNSUPPORTED", query: "fr", preference: "it", acceptLanguage: "fr-CH", want: "de"},
+		{name: "#7 EMPTY", want: "de"},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := negotiateLocale(test.query, test.preference, test.session, test.acceptLanguage); got != test.want {
+				t.Errorf("negotiateLocale() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestCatalogs (from i18n_catalog) tests whether all texts of the
+// HTML-templates have a translation, and whether all translations contain as
+// many values as their German messages.
+func TestCatalogs(t *testing.T) {
+
+	message := regexp.MustCompile(`{{t \$[\w.]* "((?:[^"\\]|\\.)*)"`)
+	files, err := filepath.Glob("../../frontend/html/*/*.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout, _ := filepath.Glob("../../frontend/html/*.html")
+
+	for locale, catalog := range catalogs {
+		for _, file := range append(files, layout...) {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, match := range message.FindAllStringSubmatch(string(content), -1) {
+				if _, ok := catalog[match[1]]; !ok {
+					t.Errorf("catalog %v: missing translation of %q (%v)", locale, match[1], filepath.Base(file))
+				}
+			}
+		}
+		for message, translation := range catalog {
+			if strings.Count(message, "%v") != strings.Count(translation, "%v") {
+				t.Errorf("catalog %v: translation %q has other values than %q", locale, translation, message)
+			}
+		}
+	}
+}
+
+// TestTranslationInputs (from translations) tests creating a translation to be
+// edited for every language other than German.
+func TestTranslationInputs(t *testing.T) {
+
+	english := x.Translation{ID: 1, Locale: "en", Name: "Second World War"}
+
+	// Declare test cases
+	tests := []struct {
+		name         string
+		translations []x.Translation
+		want         []translationInput
+	}{
+		{
+			name:         "#1 TRANSLATED",
+			translations: []x.Translation{english},
+			want:         []translationInput{{Translation: english, Language: "English"}},
+		},
+		{
+			name:         "#2 NOT TRANSLATED",
+			translations: nil,
+			want:         []translationInput{{Translation: x.Translation{Locale: "en"}, Language: "English"}},
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := translationInputs(test.translations); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("translationInputs() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestLocalizeTopics (from translations) tests replacing the names and
+// descriptions of topics with their translations.
+func TestLocalizeTopics(t *testing.T) {
+
+	topics := []x.Topic{
+		{TopicID: 1, Name: "Zweiter Weltkrieg", Description: "Der Krieg von 1939 bis 1945."},
+		{TopicID: 2, Name: "Kalter Krieg", Description: "Der Konflikt der Supermächte."},
+		{TopicID: 3, Name: "Antike", Description: "Das Altertum."},
+	}
+	translations := map[int]x.Translation{
+		1: {ID: 1, Locale: "en", Name: "Second World War", Description: "The war from 1939 to 1945."},
+		2: {ID: 2, Locale: "en", Name: "Cold War"}, // missing description falls back to German
+	}
+
+	want := []x.Topic{
+		{TopicID: 1, Name: "Second World War", Description: "The war from 1939 to 1945."},
+		{TopicID: 2, Name: "Cold War", Description: "Der Konflikt der Supermächte."},
+		{TopicID: 3, Name: "Antike", Description: "Das Altertum."},
+	}
+
+	if localizeTopics(topics, translations); !reflect.DeepEqual(topics, want) {
+		t.Errorf("localizeTopics() = %v, want %v", topics, want)
+	}
+}
+
+// TestParseLeaderboardFilter (from score_handler) tests converting the URL
+// query into a filter of the leaderboard.
+func TestParseLeaderboardFilter(t *testing.T) {
+
+	now := time.Date(2021, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		query      string
+		wantForm   leaderboardFilterForm
+		wantFilter x.LeaderboardFilter
+		wantQuery  string
+	}{
+		{
+			name:       "#1 EMPTY",
+			query:      "",
+			wantForm:   leaderboardFilterForm{},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardBest},
+			wantQuery:  "",
+		},
+		{
+			name:     "#2 ALL CRITERIA",
+			query:    "topic=2&class=+4A+&period=week",
+			wantForm: leaderboardFilterForm{TopicID: 2, Class: "4a", Period: "week"},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardBest, TopicID: 2, Class: "4a",
+				Since: time.Date(2021, 3, 8, 12, 0, 0, 0, time.UTC)},
+			wantQuery: "class=4a&period=week&topic=2",
+		},
+		{
+			name:     "#3 MONTH",
+			query:    "period=month",
+			wantForm: leaderboardFilterForm{Period: "month"},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardBest,
+				Since: time.Date(2021, 2, 13, 12, 0, 0, 0, time.UTC)},
+			wantQuery: "period=month",
+		},
+		{
+			name:       "#4 INVALID",
+			query:      "topic=-1&period=year&mode=worst",
+			wantForm:   leaderboardFilterForm{},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardBest},
+			wantQuery:  "",
+		},
+		{
+			name:       "#5 TOTAL",
+			query:      "mode=total&class=4a",
+			wantForm:   leaderboardFilterForm{Mode: "total", Class: "4a"},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardTotal, Class: "4a"},
+			wantQuery:  "class=4a&mode=total",
+		},
+		{
+			name:       "#6 ALL SCORES",
+			query:      "mode=all",
+			wantForm:   leaderboardFilterForm{Mode: "all"},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardAll},
+			wantQuery:  "mode=all",
+		},
+		{
+			name:       "#7 DEFAULT MODE",
+			query:      "mode=best",
+			wantForm:   leaderboardFilterForm{},
+			wantFilter: x.LeaderboardFilter{Mode: x.LeaderboardBest},
+			wantQuery:  "",
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			query, _ := neturl.ParseQuery(test.query)
+			form, filter := parseLeaderboardFilter(query, now)
+
+			if form != test.wantForm {
+				t.Errorf("parseLeaderboardFilter() form = %v, want %v", form, test.wantForm)
+			}
+			if !reflect.DeepEqual(filter, test.wantFilter) {
+				t.Errorf("parseLeaderboardFilter() filter = %v, want %v", filter, test.wantFilter)
+			}
+			if got := form.query(); got != test.wantQuery {
+				t.Errorf("query() = %v, want %v", got, test.wantQuery)
+			}
+		})
+	}
+}
+
+// TestRatingQuizResult (from ratings) tests converting the points of a quiz
+// into a result between 0 and 1.
+func TestRatingQuizResult(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name            string
+		points          int
+		potentialPoints int
+		want            float64
+	}{
+		{name: "#1 HALF", points: 50, potentialPoints: 100, want: 0.5},
+		{name: "#2 ALL", points: 100, potentialPoints: 100, want: 1},
+		{name: "#3 NONE", points: 0, potentialPoints: 100, want: 0},
+		{name: "#4 TOO MANY", points: 120, potentialPoints: 100, want: 1},
+		{name: "#5 NO POTENTIAL POINTS", points: 10, potentialPoints: 0, want: 0},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := quizResult(test.points, test.potentialPoints); got != test.want {
+				t.Errorf("quizResult() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRatingExpectedResult (from ratings) tests calculating the expected
+// result against an opponent.
+func TestRatingExpectedResult(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name     string
+		rating   int
+		opponent int
+		want     float64
+	}{
+		{name: "#1 EQUAL", rating: 1000, opponent: 1000, want: 0.5},
+		{name: "#2 STRONGER", rating: 1400, opponent: 1000, want: 10.0 / 11},
+		{name: "#3 WEAKER", rating: 1000, opponent: 1400, want: 1.0 / 11},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := expectedResult(test.rating, test.opponent); math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("expectedResult() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRatingNewRating (from ratings) tests calculating the rating after a
+// result against an opponent.
+func TestRatingNewRating(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name     string
+		rating   int
+		opponent int
+		result   float64
+		factor   int
+		want     int
+	}{
+		{name: "#1 AS EXPECTED", rating: 1000, opponent: 1000, result: 0.5, factor: 32, want: 1000},
+		{name: "#2 BETTER", rating: 1000, opponent: 1000, result: 1, factor: 32, want: 1016},
+		{name: "#3 WORSE", rating: 1000, opponent: 1000, result: 0, factor: 32, want: 984},
+		{name: "#4 NO RATING YET", rating: 0, opponent: 0, result: 0.75, factor: 32, want: 1008},
+		{name: "#5 DIFFICULT TOPIC", rating: 1000, opponent: 1400, result: 0.5, factor: 32, want: 1013},
+		{name: "#6 DIFFICULTY", rating: 1000, opponent: 1000, result: 0.25, factor: 8, want: 998},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := newRating(test.rating, test.opponent, test.result, test.factor); got != test.want {
+				t.Errorf("newRating() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRatingCurrentRatings (from ratings) tests splitting the history of a
+// user's ratings into the overall ratings and the latest rating per topic.
+func TestRatingCurrentRatings(t *testing.T) {
+
+	date := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	ratings := []x.Rating{
+		{RatingID: 1, UserID: 1, Rating: 1008, Date: date},
+		{RatingID: 2, UserID: 1, TopicID: 2, Rating: 1008, Date: date, TopicName: "Kalter Krieg"},
+		{RatingID: 3, UserID: 1, Rating: 1002, Date: date.AddDate(0, 0, 1)},
+		{RatingID: 4, UserID: 1, TopicID: 1, Rating: 994, Date: date.AddDate(0, 0, 1), TopicName: "Antike"},
+		{RatingID: 5, UserID: 1, Rating: 1015, Date: date.AddDate(0, 0, 2)},
+		{RatingID: 6, UserID: 1, TopicID: 2, Rating: 1021, Date: date.AddDate(0, 0, 2), TopicName: "Kalter Krieg"},
+	}
+
+	wantOverall := []x.Rating{ratings[0], ratings[2], ratings[4]}
+	wantTopics := []x.Rating{ratings[5], ratings[3]}
+
+	overall, topics := currentRatings(ratings)
+	if !reflect.DeepEqual(overall, wantOverall) {
+		t.Errorf("currentRatings() overall = %v, want %v", overall, wantOverall)
+	}
+	if !reflect.DeepEqual(topics, wantTopics) {
+		t.Errorf("currentRatings() topics = %v, want %v", topics, wantTopics)
+	}
+}
+
+// TestAchievementEvaluateAchievements (from achievements) tests evaluating the
+// rules of the achievements, which haven't been unlocked yet.
+func TestAchievementEvaluateAchievements(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name     string
+		progress achievementProgress
+		unlocked []x.Achievement
+		want     []string
+	}{
+		{
+			name:     "#1 NOTHING",
+			progress: achievementProgress{TopicsCount: 9, Streak: 6},
+			want:     nil,
+		},
+		{
+			name:     "#2 ALL",
+			progress: achievementProgress{PerfectPhase3: true, TopicsCount: 10, Streak: 7, FirstPlace: true},
+			want:     []string{"perfect_phase3", "topics", "streak", "first_place"},
+		},
+		{
+			name:     "#3 ALREADY UNLOCKED",
+			progress: achievementProgress{PerfectPhase3: true, TopicsCount: 12, FirstPlace: true},
+			unlocked: []x.Achievement{{Key: "perfect_phase3"}, {Key: "first_place"}},
+			want:     []string{"topics"},
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, a := range evaluateAchievements(test.progress, test.unlocked) {
+				got = append(got, a.Key)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("evaluateAchievements() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestAchievementAchievementStatuses (from achievements) tests combining all
+// achievements with the ones a user has unlocked.
+func TestAchievementAchievementStatuses(t *testing.T) {
+
+	date := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	statuses := achievementStatuses([]x.Achievement{{UserID: 1, Key: "streak", Date: date}})
+
+	if len(statuses) != len(achievements) {
+		t.Fatalf("achievementStatuses() = %v statuses, want %v", len(statuses), len(achievements))
+	}
+	for _, status := range statuses {
+		if wantUnlocked := status.Key == "streak"; status.Unlocked != wantUnlocked {
+			t.Errorf("achievementStatuses() %v unlocked = %v, want %v", status.Key, status.Unlocked, wantUnlocked)
+		}
+		if status.Unlocked && !status.Date.Equal(date) {
+			t.Errorf("achievementStatuses() %v date = %v, want %v", status.Key, status.Date, date)
+		}
+	}
+
+	// Descriptions with amounts must be translatable
+	if got := translate("en", statuses[1].Description); got != "Played 10 different topics." {
+		t.Errorf("translate() = %v, want %v", got, "Played 10 different topics.")
+	}
+}
+
+// TestActivityCurrentStreak (from activity) tests calculating the amount of
+// consecutive days played up to today.
+func TestActivityCurrentStreak(t *testing.T) {
+
+	today := time.Date(2021, 3, 10, 18, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time {
+		return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		days []time.Time
+		want int
+	}{
+		{name: "#1 NO DAYS", days: nil, want: 0},
+		{name: "#2 ONLY TODAY", days: []time.Time{day(10)}, want: 1},
+		{name: "#3 CONSECUTIVE", days: []time.Time{day(10), day(9), day(8), day(6)}, want: 3},
+		{name: "#4 UNTIL YESTERDAY", days: []time.Time{day(9), day(8)}, want: 2},
+		{name: "#5 ENDED", days: []time.Time{day(8), day(7)}, want: 0},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := currentStreak(test.days, today); got != test.want {
+				t.Errorf("currentStreak() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	// Streak across the end of a month
+	march1 := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+	days := []time.Time{day(1), time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)}
+	if got := currentStreak(days, march1); got != 2 {
+		t.Errorf("currentStreak() = %v, want %v", got, 2)
+	}
+}
+
+// TestActivityLongestStreak (from activity) tests calculating the highest
+// amount of consecutive days played ever.
+func TestActivityLongestStreak(t *testing.T) {
+
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2021, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Declare test cases
+	tests := []struct {
+		name string
+		days []time.Time
+		want int
+	}{
+		{name: "#1 NO DAYS", days: nil, want: 0},
+		{name: "#2 SINGLE DAY", days: []time.Time{day(3, 10)}, want: 1},
+		{name: "#3 LATEST STREAK", days: []time.Time{day(3, 10), day(3, 9), day(3, 8), day(3, 1)}, want: 3},
+		{name: "#4 EARLIER STREAK", days: []time.Time{day(3, 10), day(3, 2), day(3, 1), day(2, 28), day(2, 27)}, want: 4},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := longestStreak(test.days); got != test.want {
+				t.Errorf("longestStreak() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestActivityHeatmap (from activity) tests creating the heatmap of the past
+// year out of the activities of a user.
+func TestActivityHeatmap(t *testing.T) {
+
+	today := time.Date(2021, 3, 10, 18, 30, 0, 0, time.UTC) // Wednesday
+	activities := []x.Activity{
+		{UserID: 1, Day: time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC), Quizzes: 2, Practices: 1},
+		{UserID: 1, Day: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Quizzes: 7},
+		{UserID: 1, Day: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), Quizzes: 1}, // too old
+	}
+
+	heatmap := activityHeatmap(activities, today)
+
+	if len(heatmap) != heatmapWeeks {
+		t.Fatalf("activityHeatmap() = %v weeks, want %v", len(heatmap), heatmapWeeks)
+	}
+
+	// The first day is a Monday and the days are consecutive
+	first := heatmap[0][0].Date
+	if first.Weekday() != time.Monday {
+		t.Errorf("activityHeatmap() first day = %v, want Monday", first.Weekday())
+	}
+	if last := heatmap[heatmapWeeks-1][6].Date; !last.Equal(first.AddDate(0, 0, heatmapWeeks*7-1)) {
+		t.Errorf("activityHeatmap() last day = %v, want %v", last, first.AddDate(0, 0, heatmapWeeks*7-1))
+	}
+
+	// Today is Wednesday of the last week, the rest of the week is future
+	if got := heatmap[heatmapWeeks-1][2]; got.Quizzes != 2 || got.Practices != 1 || got.Level != 2 || got.Future {
+		t.Errorf("activityHeatmap() today = %+v, want 2 quizzes, 1 practice, level 2", got)
+	}
+	if !heatmap[heatmapWeeks-1][3].Future {
+		t.Errorf("activityHeatmap() tomorrow isn't future")
+	}
+	if got := heatmap[heatmapWeeks-2][0]; got.Quizzes != 7 || got.Level != 4 {
+		t.Errorf("activityHeatmap() 1st of March = %+v, want 7 quizzes, level 4", got)
+	}
+	if got := heatmapActiveDays(heatmap); got != 2 {
+		t.Errorf("heatmapActiveDays() = %v, want %v", got, 2)
+	}
+}
+
+// TestActivityLevel (from activity) tests converting the amount of
+// activities on a day into the intensity of its color.
+func TestActivityLevel(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		count int
+		want  int
+	}{
+		{count: 0, want: 0},
+		{count: 1, want: 1},
+		{count: 3, want: 2},
+		{count: 5, want: 3},
+		{count: 20, want: 4},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.count), func(t *testing.T) {
+			if got := activityLevel(test.count); got != test.want {
+				t.Errorf("activityLevel() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestQuizYearGuessPoints (from quiz_handler) tests calculating the points of
+// a guess of the exact year of an event.
+func TestQuizYearGuessPoints(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name  string
+		guess int
+		want  int
+	}{
+		{name: "#1 CORRECT", guess: 1848, want: phase2Points},
+		{name: "#2 1 YEAR OFF", guess: 1847, want: 2},
+		{name: "#3 2 YEARS OFF", guess: 1850, want: 1},
+		{name: "#4 3 YEARS OFF", guess: 1845, want: 0},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := yearGuessPoints(1848, test.guess); got != test.want {
+				t.Errorf("yearGuessPoints() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestChallengeSelectChallengeEvents (from challenge_handler) tests choosing
+// the events of the quiz of a day, which have to be the same for every request
+// on that day, regardless of the order of the given events.
+func TestChallengeSelectChallengeEvents(t *testing.T) {
+
+	var events []x.Event
+	for id := 1; id <= 30; id++ {
+		events = append(events, x.Event{EventID: id, Year: 1800 + id})
+	}
+	reversed := make([]x.Event, len(events))
+	for i, event := range events {
+		reversed[len(events)-1-i] = event
+	}
+
+	day := time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)
+	selected := selectChallengeEvents(events, day)
+
+	if len(selected) != challengeQuestions {
+		t.Fatalf("selectChallengeEvents() = %v events, want %v", len(selected), challengeQuestions)
+	}
+	if got := selectChallengeEvents(reversed, day); !reflect.DeepEqual(got, selected) {
+		t.Errorf("selectChallengeEvents() = %v, want %v for the same day", got, selected)
+	}
+	if got := selectChallengeEvents(events, day.AddDate(0, 0, 1)); reflect.DeepEqual(got, selected) {
+		t.Errorf("selectChallengeEvents() = %v for the next day, want other events", got)
+	}
+	if events[0].EventID != 1 || reversed[0].EventID != 30 {
+		t.Errorf("selectChallengeEvents() changed the order of the given events")
+	}
+
+	// If there aren't enough events, all of them get chosen
+	if got := selectChallengeEvents(events[:5], day); len(got) != 5 {
+		t.Errorf("selectChallengeEvents() = %v events, want %v", len(got), 5)
+	}
+}
+
+// TestStatisticsTopicStatistics (from statistics) tests combining all topics
+// with the statistics of a user in the topics played.
+func TestStatisticsTopicStatistics(t *testing.T) {
+
+	topics := []x.Topic{
+		{TopicID: 1, Name: "Topic 1", EventsCount: 10},
+		{TopicID: 2, Name: "Topic 2", EventsCount: 10},
+	}
+	stats := []x.TopicStats{
+		{TopicID: 1, TopicName: "Topic 1", AttemptsCount: 3, BestPoints: 47, AveragePoints: 40.3333, Percentile: 50,
+			PlayersCount: 3},
+	}
+
+	want := []scoresPerTopic{
+		{TopicName: "Topic 1", Points: 47, MaxPoints: 94, Percentage: 50, AveragePoints: 40.3, AveragePercentage: 43,
+			Attempts: 3, Percentile: 50, PlayersCount: 3},
+		{TopicName: "Topic 2", MaxPoints: 94},
+	}
+
+	if got := topicStatistics(topics, stats); !reflect.DeepEqual(got, want) {
+		t.Errorf("topicStatistics() = %+v, want %+v", got, want)
+	}
+}
+
+// TestStatisticsAverageBestChart (from statistics) tests comparing the average
+// and best points of a user in every topic played, whereas topics not played
+// get left out.
+func TestStatisticsAverageBestChart(t *testing.T) {
+
+	statistics := []scoresPerTopic{
+		{TopicName: "Topic 1", Percentage: 50, AveragePercentage: 43, Attempts: 3},
+		{TopicName: "Topic 2"},
+		{TopicName: "Topic 3", Percentage: 80, AveragePercentage: 80, Attempts: 1},
+	}
+
+	want := chart{
+		Type:   "bar",
+		Labels: []string{"Topic 1", "Topic 3"},
+		Datasets: []chartDataset{
+			{Label: "Average", Values: []interface{}{43, 80}},
+			{Label: "Best result", Values: []interface{}{50, 80}},
+		},
+	}
+
+	if got := averageBestChart(statistics, "en"); !reflect.DeepEqual(got, want) {
+		t.Errorf("averageBestChart() = %+v, want %+v", got, want)
+	}
+}
+
+// TestStatisticsScoreHistoryChart (from statistics) tests converting the scores
+// of a user into the history of the points in every topic, with gaps at the
+// scores of other topics.
+func TestStatisticsScoreHistoryChart(t *testing.T) {
+
+	day := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	scores := []x.Score{
+		{TopicID: 1, TopicName: "Topic 1", Points: 30, Date: day},
+		{TopicID: 2, TopicName: "Topic 2", Points: 40, Date: day.AddDate(0, 0, 1)},
+		{TopicID: 1, TopicName: "Topic 1", Points: 47, Date: day.AddDate(0, 0, 2)},
+	}
+
+	want := chart{
+		Type: "line",
+		Labels: []string{formatDate(defaultLocale, day), formatDate(defaultLocale, day.AddDate(0, 0, 1)),
+			formatDate(defaultLocale, day.AddDate(0, 0, 2))},
+		Datasets: []chartDataset{
+			{Label: "Topic 1", Values: []interface{}{30, nil, 47}},
+			{Label: "Topic 2", Values: []interface{}{nil, 40, nil}},
+		},
+	}
+
+	if got := scoreHistoryChart(scores, defaultLocale); !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreHistoryChart() = %+v, want %+v", got, want)
+	}
+
+	// Without scores, the chart is empty
+	if got := scoreHistoryChart(nil, defaultLocale); len(got.Labels) != 0 || len(got.Datasets) != 0 {
+		t.Errorf("scoreHistoryChart() = %+v, want an empty chart", got)
+	}
+}
+
+// TestSearchPagesOf (from search) tests determining the pages among the search
+// results, which are accessible to a user.
+func TestSearchPagesOf(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name      string
+		user      x.User
+		loggedIn  bool
+		wantPages []string
+	}{
+		{
+			name:      "#1 VISITOR",
+			loggedIn:  false,
+			wantPages: []string{loginURL, registerURL, topicsURL},
+		},
+		{
+			name:      "#2 USER",
+			user:      x.User{UserID: 1, Verified: true},
+			loggedIn:  true,
+			wantPages: []string{challengeURL, scoresURL, profileURL, loginURL, registerURL, topicsURL},
+		},
+		{
+			name:      "#3 UNVERIFIED ADMIN",
+			user:      x.User{UserID: 1, Admin: true},
+			loggedIn:  true,
+			wantPages: []string{challengeURL, scoresURL, profileURL, loginURL, registerURL, topicsURL},
+		},
+		{
+			name:      "#4 ADMIN",
+			user:      x.User{UserID: 1, Admin: true, Verified: true},
+			loggedIn:  true,
+			wantPages: []string{challengeURL, scoresURL, profileURL, loginURL, registerURL, topicsURL, usersURL},
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var pages []string
+			for url := range searchPagesOf(test.user, test.loggedIn) {
+				pages = append(pages, url)
+			}
+			sort.Strings(pages)
+			sort.Strings(test.wantPages)
+			if !reflect.DeepEqual(pages, test.wantPages) {
+				t.Errorf("searchPagesOf() = %v, want %v", pages, test.wantPages)
+			}
+		})
+	}
+}
+
+// TestRatingsRatingChart (from ratings) tests converting the history of a
+// user's overall ratings into a line chart.
+func TestRatingsRatingChart(t *testing.T) {
+
+	day := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	ratings := []x.Rating{
+		{UserID: 1, Rating: 1012, Date: day},
+		{UserID: 1, Rating: 1005, Date: day.AddDate(0, 0, 1)},
+	}
+
+	want := chart{
+		Type:   "line",
+		Labels: []string{formatDate("en", day), formatDate("en", day.AddDate(0, 0, 1))},
+		Datasets: []chartDataset{
+			{Label: "Rating", Values: []interface{}{1012, 1005}},
+		},
+	}
+
+	if got := ratingChart(ratings, "en"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ratingChart() = %+v, want %+v", got, want)
+	}
+}
+
+// TestQuizPhase3Orders (from quiz_handler) tests converting the indexes of the
+// questions of phase 3 into the actual orders of the events.
+func TestQuizPhase3Orders(t *testing.T) {
+
+	questions := []phase3Question{
+		{EventName: "Event C", Order: 2},
+		{EventName: "Event A", Order: 0},
+		{EventName: "Event B", Order: 1},
+	}
+
+	tests := []struct {
+		name       string
+		guesses    []string
+		wantOrders []int
+		wantOk     bool
+	}{
+		{
+			name:       "#1 OK",
+			guesses:    []string{"1", "2", "0"},
+			wantOrders: []int{0, 1, 2},
+			wantOk:     true,
+		},
+		{
+			name:       "#2 OK WRONG ORDER",
+			guesses:    []string{"0", "1", "2"},
+			wantOrders: []int{2, 0, 1},
+			wantOk:     true,
+		},
+		{
+			name:    "#3 DUPLICATE INDEX",
+			guesses: []string{"1", "1", "1"},
+			wantOk:  false,
+		},
+		{
+			name:    "#4 INDEX OUT OF RANGE",
+			guesses: []string{"1", "2", "3"},
+			wantOk:  false,
+		},
+		{
+			name:    "#5 INVALID INDEX",
+			guesses: []string{"1", "2", "abc"},
+			wantOk:  false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			orders, ok := phase3Orders(test.guesses, questions)
+			if ok != test.wantOk {
+				t.Fatalf("phase3Orders() ok = %v, want %v", ok, test.wantOk)
+			}
+			if !reflect.DeepEqual(orders, test.wantOrders) {
+				t.Errorf("phase3Orders() = %v, want %v", orders, test.wantOrders)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Responsible for the internationalization of all user-facing texts, such as
+// flash messages, form errors and texts of HTML-templates.
+//
+// German is the source language, whose texts serve as IDs of the messages in
+// the catalogs of other languages (see i18n_catalog.go). Texts without a
+// translation fall back to German. Texts containing values (e.g. "Thema '%v'
+// wurde ...") get translated even after the values have been inserted, which
+// is why flash messages and form errors can be translated when being
+// displayed, rather than when being created.
+//
+// The locale of a request gets negotiated in the following order: the query
+// parameter 'lang' (which also gets stored in the session and as the
+// preference of the user), the preference of the user, the session and the
+// header 'Accept-Language'.
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	defaultLocale = "de" // source language of all texts
+)
+
+var (
+	// locales are all supported locales
+	locales = []string{"de", "en"}
+
+	// languageNames are the names of the languages of the locales, in the
+	// language itself
+	languageNames = map[string]string{
+		"de": "Deutsch",
+		"en": "English",
+	}
+
+	// dateFormats are the formats of dates per locale
+	dateFormats = map[string]string{
+		"de": "02.01.2006",
+		"en": "01/02/2006",
+	}
+
+	// dateTimeFormats are the formats of dates including the time per locale
+	dateTimeFormats = map[string]string{
+		"de": "02.01.2006 15:04",
+		"en": "01/02/2006 3:04 PM",
+	}
+
+	// catalogPatterns are the messages of the catalogs containing values,
+	// converted to regular expressions per locale, in order to translate texts
+	// in which the values have already been inserted
+	catalogPatterns = map[string][]catalogPattern{}
+
+	// templateFuncs are the HTML-template-functions available in all
+	// HTML-templates, in order to translate texts and format dates
+	templateFuncs = template.FuncMap{
+		"t":         translate,
+		"date":      formatDate,
+		"datetime":  formatDateTime,
+		"localized": localize,
+	}
+)
+
+// catalogPattern is a message containing values as a regular expression,
+// together with its translation.
+type catalogPattern struct {
+	regex       *regexp.Regexp
+	translation string
+}
+
+// localized is a value passed to a nested HTML-template together with the
+// locale, since nested HTML-templates have no access to the data of the
+// surrounding HTML-template.
+type localized struct {
+	Locale string
+	Value  interface{}
+}
+
+// init gets initialized with the package.
+//
+// All messages of the catalogs containing values get compiled to regular
+// expressions once, longest messages first, since they're the most specific.
+func init() {
+
+	for locale, catalog := range catalogs {
+		var messages []string
+		for message := range catalog {
+			if strings.Contains(message, "%v") {
+				messages = append(messages, message)
+			}
+		}
+		sort.Slice(messages, func(n1, n2 int) bool {
+			return len(messages[n1]) > len(messages[n2])
+		})
+
+		for _, message := range messages {
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(message), "%v", "(.*?)")
+			catalogPatterns[locale] = append(catalogPatterns[locale], catalogPattern{
+				regex:       regexp.MustCompile("^" + pattern + "$"),
+				translation: catalog[message],
+			})
+		}
+	}
+}
+
+// newTemplate creates a new HTML-template based on the layout, which has
+// access to the HTML-template-functions for translations and dates.
+func newTemplate() *template.Template {
+	return template.New("layout.html").Funcs(templateFuncs)
+}
+
+// localize wraps a value passed to a nested HTML-template together with the
+// locale.
+func localize(locale string, value interface{}) localized {
+	return localized{Locale: locale, Value: value}
+}
+
+// translate translates a German message to a locale and inserts the values
+// into it, if any. Messages without values might be texts, in which values
+// have already been inserted (e.g. errors of rows of an import). Messages
+// without a translation remain German.
+// (Tested in handler_test.go)
+func translate(locale string, message string, values ...interface{}) string {
+
+	if len(values) == 0 {
+		return translateText(locale, message)
+	}
+
+	if translation, ok := catalogs[locale][message]; ok {
+		message = translation
+	}
+
+	return fmt.Sprintf(message, values...)
+}
+
+// translateText translates a German text to a locale, in which values might
+// already have been inserted, such as flash messages and form errors.
+// (Tested in handler_test.go)
+func translateText(locale string, text string) string {
+
+	if translation, ok := catalogs[locale][text]; ok {
+		return translation
+	}
+
+	for _, pattern := range catalogPatterns[locale] {
+		if match := pattern.regex.FindStringSubmatch(text); match != nil {
+			// Values might be translatable texts themselves (e.g. the error
+			// of a form within the error of an import)
+			values := make([]interface{}, len(match)-1)
+			for i, value := range match[1:] {
+				values[i] = translateText(locale, value)
+			}
+			return fmt.Sprintf(pattern.translation, values...)
+		}
+	}
+
+	return text
+}
+
+// translateFormErrors translates the errors of a form, which got stored in
+// the session, in place. Forms are of different types, all of which have a
+// field 'Errors'.
+func translateFormErrors(locale string, form interface{}) {
+
+	value := reflect.ValueOf(form)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	field := value.FieldByName("Errors")
+	if !field.IsValid() || !field.CanInterface() {
+		return
+	}
+	if errors, ok := field.Interface().(FormErrors); ok {
+		for field, msg := range errors {
+			errors[field] = translateText(locale, msg)
+		}
+	}
+}
+
+// formatDate formats a date according to a locale.
+func formatDate(locale string, date time.Time) string {
+
+	if format, ok := dateFormats[locale]; ok {
+		return date.Format(format)
+	}
+
+	return date.Format(dateFormats[defaultLocale])
+}
+
+// formatDateTime formats a date including the time according to a locale.
+func formatDateTime(locale string, date time.Time) string {
+
+	if format, ok := dateTimeFormats[locale]; ok {
+		return date.Format(format)
+	}
+
+	return date.Format(dateTimeFormats[defaultLocale])
+}
+
+// supportedLocale checks whether a locale is supported.
+func supportedLocale(locale string) bool {
+
+	for _, supported := range locales {
+		if locale == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// negotiateLocale chooses the locale of a request out of the query parameter,
+// the preference of the user, the session and the header 'Accept-Language',
+// in that order, falling back to German.
+// (Tested in handler_test.go)
+func negotiateLocale(query string, preference string, session string, acceptLanguage string) string {
+
+	for _, locale := range []string{query, preference, session} {
+		if supportedLocale(locale) {
+			return locale
+		}
+	}
+
+	// Choose the supported language with the highest quality out of the
+	// header (e.g. 'en-US,en;q=0.9,de;q=0.8')
+	best, bestQuality := defaultLocale, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.ToLower(strings.SplitN(fields[0], "-", 2)[0])
+		quality := 1.0
+		for _, field := range fields[1:] {
+			if field = strings.TrimSpace(field); strings.HasPrefix(field, "q=") {
+				quality, _ = strconv.ParseFloat(strings.TrimPrefix(field, "q="), 64)
+			}
+		}
+		if supportedLocale(locale) && quality > bestQuality {
+			best, bestQuality = locale, quality
+		}
+	}
+
+	return best
+}
+
+// localeOf gets the negotiated locale of a request from its context.
+func localeOf(ctx context.Context) string {
+
+	if locale, ok := ctx.Value("locale").(string); ok {
+		return locale
+	}
+
+	return defaultLocale
+}
+
+// withLocale is a middleware that negotiates the locale of a request and adds
+// it to the context. A locale chosen through the query parameter 'lang' gets
+// stored in the session and as the preference of the user.
+func (h *Handler) withLocale(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		user, loggedIn := ctx.Value("user").(x.User)
+
+		// Store locale chosen through the query parameter
+		query := req.URL.Query().Get("lang")
+		if supportedLocale(query) {
+			h.sessions.Put(ctx, "locale", query)
+			if loggedIn && user.Locale != query {
+				// Execute SQL statement to update the preference of the user
+				if err := h.store.UpdateUserLocale(user.UserID, query); err != nil {
+					log.Printf("error storing locale of user %v: %v", user.UserID, err)
+				}
+				user.Locale = query
+				ctx = context.WithValue(ctx, "user", user)
+			}
+		}
+
+		locale := negotiateLocale(query, user.Locale, h.sessions.GetString(ctx, "locale"),
+			req.Header.Get("Accept-Language"))
+		res.Header().Set("Content-Language", locale)
+
+		next.ServeHTTP(res, req.WithContext(context.WithValue(ctx, "locale", locale)))
+	})
+}
@@ -0,0 +1,529 @@
+// The catalogs of translations of all user-facing texts, with the German
+// texts as IDs of the messages. Values get inserted at '%v', in the same
+// order as in the German text.
+
+package web
+
+var (
+	// catalogs contain the translations of German messages per locale
+	catalogs = map[string]map[string]string{
+		"en": catalogEN,
+	}
+
+	// catalogEN is the English catalog
+	catalogEN = map[string]string{
+		// Layout
+		"Themen":             "Topics",
+		"Profil":             "Profile",
+		"Suchen nach ...":    "Search for ...",
+		"Gast":               "Guest",
+		"Benutzer verwalten": "Manage users",
+		"Vorschläge":         "Suggestions",
+		"Protokoll":          "Audit log",
+		"Papierkorb":         "Trash",
+		"Abmelden":           "Log out",
+		"Registrieren":       "Register",
+		"Anmelden":           "Log in",
+
+		// Titles of pages found by the search
+		"Bestenliste": "Leaderboard",
+
+		// HTTP-errors
+		"Seite nicht gefunden":                                "Page not found",
+		"Es sieht so aus, als wäre ein Fehler aufgetreten...": "It looks like an error occurred...",
+		"Zurück zu Home":                                      "Back to home",
+		"Methode nicht erlaubt":                               "Method not allowed",
+		"Nicht eingeloggt":                                    "Not logged in",
+		"Zugriff verweigert":                                  "Access denied",
+		"Zum Login":                                           "To login",
+
+		// Denied access
+		"Unzureichende Berechtigung. Sie müssen eingeloggt sein, um diese Seite aufzurufen.":                                                                                     "Insufficient permission. You must be logged in to access this page.",
+		"Unzureichende Berechtigung. Sie müssen als Admin eingeloggt sein, um diese Seite aufzurufen.":                                                                           "Insufficient permission. You must be logged in as an admin to access this page.",
+		"Unzureichende Berechtigung. Sie müssen zuerst Ihre Email bestätigen, um diese Seite aufzurufen. Auf Ihrem Profil können Sie eine erneute Bestätigungs-Email versenden.": "Insufficient permission. You must confirm your email first to access this page. You can resend a confirmation email on your profile.",
+
+		// Home
+		"Willkommen bei 'Jahreszahlen'!":   "Welcome to 'Jahreszahlen'!",
+		"Benutzer":                         "User",
+		"Ereignisse":                       "Events",
+		"Gespielte Quiz (total)":           "Quizzes played (total)",
+		"Gespielte Quiz (letzten 30 Tage)": "Quizzes played (last 30 days)",
+		"Top 5 meist-gespielte Themen":     "Top 5 most played topics",
+		"%v Ereignisse":                    "%v events",
+		"Neu hier?":                        "New here?",
+		"Sind Sie neu hier?":               "Are you new here?",
+		"Dann erstellen Sie in wenigen Klicks einen neuen Account. So können Sie von allen Features dieser Applikation profitieren!": "Then create a new account in a few clicks. This way you can benefit from all features of this application!",
+		"Als Admin können Sie hier zu einer Liste aller Benutzer gelangen.":                                                          "As an admin you can get to a list of all users here.",
+		"Dort haben Sie die Möglichkeit, Benutzer mit unangebrachten Namen zu löschen, oder jemanden zum Admin zu befördern.":        "There you can delete users with inappropriate names or promote someone to admin.",
+		"Zu den Benutzern": "To the users",
+		"Themen & Quiz":    "Topics & quizzes",
+		"Jedes Thema besteht aus mehreren Ereignissen. Diese werden dann in einem Quiz, welches aus 3 Phasen besteht, abgefragt und getestet. Am Ende des Quiz, werden Ihre Leistungen mit jenen anderer verglichen.": "Every topic consists of several events. These are then tested in a quiz consisting of 3 phases. At the end of the quiz, your performance is compared to that of others.",
+		"Zu den Themen":               "To the topics",
+		"Spielresultate im Vergleich": "Comparing results",
+		"Auf dem Leaderboard werden die Spielresultate aufgelistet, mitsamt Benutzer und Thema. Suchen/filtern Sie dort nach ihrem Namen, um Ihre Resultate zu betrachten.": "The leaderboard lists the results, including user and topic. Search/filter for your name there to view your results.",
+		"Zum Leaderboard": "To the leaderboard",
+
+		// Search
+		"Suche":       "Search",
+		"Suchbegriff": "Search term",
+		"Thema, Ereignis oder Jahre (z.B. Weltkrieg 1914-1918)": "Topic, event or years (e.g. World War 1914-1918)",
+		"Suchen":                 "Search",
+		"%v Ergebnisse für '%v'": "%v results for '%v'",
+		"im Jahr %v":             "in the year %v",
+		"Thema":                  "Topic",
+		"Seite":                  "Page",
+		"Es wurde kein Suchergebnis gefunden. Versuchen Sie es mit anderen Begriffen oder einem anderen Zeitraum.": "No search results were found. Try other terms or another period.",
+
+		// Topics
+		"Alle":                                "All",
+		"Von":                                 "From",
+		"Bis":                                 "To",
+		"Zurücksetzen":                        "Reset",
+		"Filtern":                             "Filter",
+		"Inklusive aller Unterthemen":         "Including all sub-topics",
+		"Klicken Sie hier für weitere Infos.": "Click here for more information.",
+		"Thema bearbeiten":                    "Edit topic",
+		"Quiz spielen":                        "Play quiz",
+		"Es wurden keine Themen gefunden.":    "No topics were found.",
+		"Neues Thema":                         "New topic",
+		"Als Admin haben Sie hier Möglichkeit, ein neues Thema zu erstellen, inklusive all ihren Ereignissen.": "As an admin you can create a new topic here, including all of its events.",
+		"Neues Thema erstellen":             "Create new topic",
+		"Thema importieren":                 "Import topic",
+		"Übersicht '%v'":                    "Overview '%v'",
+		"Keine Beschreibung":                "No description",
+		"inklusive aller Unterthemen":       "including all sub-topics",
+		"Quiz starten":                      "Start quiz",
+		"Ereignisse auflisten":              "List events",
+		"Arbeitsblatt drucken":              "Print worksheet",
+		"Lernkarten für Anki herunterladen": "Download flashcards for Anki",
+		"Ereignis vorschlagen":              "Suggest event",
+		"Thema exportieren":                 "Export topic",
+		"Thema löschen":                     "Delete topic",
+		"Sind Sie sicher?":                  "Are you sure?",
+		"Das Thema wird in den Papierkorb verschoben und kann dort wiederhergestellt werden, bevor es mitsamt allen Ereignissen und Spielresultaten endgültig gelöscht wird.": "The topic is moved to the trash and can be restored there, before it is permanently deleted together with all of its events and results.",
+		"Abbrechen":   "Cancel",
+		"Löschen":     "Delete",
+		"Unterthemen": "Sub-topics",
+		"Dieses Thema hat noch keine Unterthemen.": "This topic has no sub-topics yet.",
+		"Unterthema erstellen":                     "Create sub-topic",
+		"Thema erstellen":                          "Create topic",
+		"Namen des Themas":                         "Name of the topic",
+		"Zeitspanne":                               "Time span",
+		"Start-Jahr der Epoche":                    "Start year of the era",
+		"End-Jahr der Epoche":                      "End year of the era",
+		"Beschreibung":                             "Description",
+		"Optionale Beschreibung (empfohlen)":       "Optional description (recommended)",
+		"Übergeordnetes Thema":                     "Parent topic",
+		"Keines (Hauptthema)":                      "None (main topic)",
+		"Optionale, durch Kommas getrennte Tags (z.B. Antike, Schweizer Geschichte)": "Optional, comma-separated tags (e.g. Antiquity, Swiss history)",
+		"Vorhandene Tags:": "Existing tags:",
+		"Bild":             "Image",
+		"URL zu einem Bild (erlaubte Formate: .PNG, .JPG, .JPEG, .GIF)":                          "URL of an image (allowed formats: .PNG, .JPG, .JPEG, .GIF)",
+		"Alternativ ein Bild hochladen (max. 5 MB)":                                              "Alternatively upload an image (max. 5 MB)",
+		"Alternativ ein Bild hochladen (PNG, JPG oder GIF, max. 5 MB), welches die URL ersetzt.": "Alternatively upload an image (PNG, JPG or GIF, max. 5 MB), which replaces the URL.",
+		"Thema '%v'":                     "Topic '%v'",
+		"Verlauf":                        "History",
+		"Exportiertes Thema importieren": "Import exported topic",
+		"Laden Sie eine JSON-Datei hoch, welche zuvor über die Übersicht eines Themas exportiert wurde. Dabei wird ein neues Thema inklusive aller Ereignisse erstellt. Existiert bereits ein Thema mit demselben Namen, wird das neue Thema automatisch umbenannt.": "Upload a JSON file, which was previously exported via the overview of a topic. This creates a new topic including all of its events. If a topic with the same name already exists, the new topic is renamed automatically.",
+
+		// History of revisions
+		"von %v":                         "by %v",
+		"von unbekannt":                  "by unknown",
+		"(aktuelle Version)":             "(current version)",
+		"Auf diese Version zurücksetzen": "Revert to this version",
+		"Keine Änderungen":               "No changes",
+		"Es wurden noch keine Versionen aufgezeichnet. Mit der nächsten Änderung wird der bisherige Stand als erste Version gespeichert.": "No versions have been recorded yet. With the next change, the current state is saved as the first version.",
+		"Datum":     "Date",
+		"Quelle":    "Source",
+		"Startjahr": "Start year",
+		"Endjahr":   "End year",
+
+		// Translations
+		"Übersetzungen":          "Translations",
+		"Übersetzung speichern":  "Save translation",
+		"(noch nicht übersetzt)": "(not translated yet)",
+		"Ohne Übersetzung werden Name und Beschreibung auf Deutsch angezeigt. Ein leerer Name löscht die Übersetzung.": "Without a translation, the name and description are displayed in German. An empty name deletes the translation.",
+
+		// Events
+		"Ereignis erstellen":      "Create event",
+		"Neues Ereignis für '%v'": "New event for '%v'",
+		"Namen des Ereignisses":   "Name of the event",
+		"Jahr/Datum des Geschehens (erlaubte Formate: 2000 / 12.2000 / 30.12.2000)":   "Year/date of the occurrence (allowed formats: 2000 / 12.2000 / 30.12.2000)",
+		"Optionaler Kontext, welcher nach dem Beantworten einer Frage angezeigt wird": "Optional context, which is displayed after answering a question",
+		"Optionale URL zu einer Quelle":                                               "Optional URL of a source",
+		"Optionale URL zu einem Bild (erlaubte Formate: .PNG, .JPG, .JPEG, .GIF)":     "Optional URL of an image (allowed formats: .PNG, .JPG, .JPEG, .GIF)",
+		"Dieses Ereignis existiert möglicherweise bereits:":                           "This event might already exist:",
+		"Identisch":           "Identical",
+		"Trotzdem erstellen":  "Create anyway",
+		"Ereignis bearbeiten": "Edit event",
+		"Ereignis '%v'":       "Event '%v'",
+		"Ereignisse '%v'":     "Events '%v'",
+		"Ereignis löschen":    "Delete event",
+		"Das Ereignis wird in den Papierkorb verschoben und kann dort wiederhergestellt werden, bevor es endgültig gelöscht wird.": "The event is moved to the trash and can be restored there, before it is permanently deleted.",
+		"Neues Ereignis": "New event",
+		"Als Admin haben Sie hier die Möglichkeit, ein neues Ereignis für das Thema '%v' zu erstellen, welches ab sofort in den Quiz abgefragt wird.": "As an admin you can create a new event for the topic '%v' here, which will be asked in the quizzes from now on.",
+		"Neues Ereignis erstellen": "Create new event",
+		"Ereignisse importieren":   "Import events",
+		"Duplikate suchen":         "Find duplicates",
+		"Duplikate":                "Duplicates",
+		"Duplikate '%v'":           "Duplicates '%v'",
+		"Ereignisse mit demselben Namen oder mit ähnlichem Namen im selben Jahr. Doppelte Ereignisse verfälschen die Auswahlmöglichkeiten in Phase 1 und die Reihenfolge in Phase 3.": "Events with the same name or with a similar name in the same year. Duplicate events distort the choices in phase 1 and the order in phase 3.",
+		"Mögliche Duplikate":                  "Possible duplicates",
+		"Ähnlichkeit":                         "Similarity",
+		"Es wurden keine Duplikate gefunden.": "No duplicates were found.",
+		"Zurück zu den Ereignissen":           "Back to the events",
+		"Ereignisse für '%v' importieren":     "Import events for '%v'",
+		"Laden Sie eine CSV- oder JSON-Datei hoch oder fügen Sie deren Inhalt ein. Jede Zeile enthält den Namen und das Jahr/Datum eines Ereignisses (erlaubte Formate: 2000 / 12.2000 / 30.12.2000).": "Upload a CSV or JSON file or paste its content. Every line contains the name and the year/date of an event (allowed formats: 2000 / 12.2000 / 30.12.2000).",
+		"Datei":  "File",
+		"Inhalt": "Content",
+		"Alternativ den Inhalt der Datei hier einfügen": "Alternatively paste the content of the file here",
+		"Automatisch erkennen":                          "Detect automatically",
+		"Vorschau anzeigen":                             "Show preview",
+		"Vorschau":                                      "Preview",
+		"%v von %v Ereignissen sind gültig. Nur gültige Ereignisse werden importiert.": "%v of %v events are valid. Only valid events will be imported.",
+		"%v davon existieren möglicherweise bereits.":                                  "%v of them might already exist.",
+		"Jahr/Datum":                           "Year/date",
+		"Fehler":                               "Errors",
+		"Zeile %v":                             "Line %v",
+		"Mögliche Duplikate nicht importieren": "Do not import possible duplicates",
+		"%v Ereignisse importieren":            "Import %v events",
+
+		// Worksheet
+		"Arbeitsblatt %v":             "Worksheet %v",
+		"Drucken / Als PDF speichern": "Print / Save as PDF",
+		"Neu mischen":                 "Shuffle again",
+		"Zurück zum Thema":            "Back to topic",
+		"Arbeitsblatt: %v":            "Worksheet: %v",
+		"Erstellt am %v":              "Created on %v",
+		"Name":                        "Name",
+		"Klasse":                      "Class",
+		"Zeitstrahl":                  "Timeline",
+		"Bringen Sie die folgenden Ereignisse in die richtige chronologische Reihenfolge. Tragen Sie dazu den Buchstaben des frühesten Ereignisses in das erste Feld ein, jenen des zweitfrühesten in das zweite Feld usw.": "Put the following events in the correct chronological order. To do so, enter the letter of the earliest event in the first box, the letter of the second earliest in the second box and so on.",
+		"Lösungen: %v": "Answers: %v",
+		"Buchstabe":    "Letter",
+		"Ereignis":     "Event",
+		"Jahr":         "Year",
+
+		// Quiz
+		"Überprüfen":           "Check",
+		"Phase 1 Überprüfung":  "Phase 1 review",
+		"Lösungen %v":          "Answers %v",
+		"Fehler melden":        "Report error",
+		"Weiter zu Phase 2":    "Continue to phase 2",
+		"Phase 2 Überprüfung":  "Phase 2 review",
+		"Richtige Antwort: %v": "Correct answer: %v",
+		"Weiter zu Phase 3":    "Continue to phase 3",
+		"Mit Klick auf ein Ereignis verschiebt es sich auf die andere Seite. Versuchen Sie alle Ereignisse in der richtigen Reihenfolge rechts abzubilden.": "Clicking on an event moves it to the other side. Try to arrange all events in the correct order on the right.",
+		"Alle Ereignisse":                    "All events",
+		"In der richtigen Reihenfolge":       "In the correct order",
+		"Phase 3 Überprüfung":                "Phase 3 review",
+		"Ihre Reihenfolge":                   "Your order",
+		"Quiz beenden":                       "Finish quiz",
+		"Auswertung":                         "Evaluation",
+		"Ihr wart besser als":                "You were better than",
+		"aller Spieler beim Quiz über":       "of all players in the quiz about",
+		"von":                                "of",
+		"Fragen wurden richtig beantwortet.": "questions were answered correctly.",
+		"von möglichen":                      "of possible",
+		"Punkten wurden erreicht.":           "points were achieved.",
+
+		// Leaderboard
+		"Spielresultate":         "Results",
+		"Anzahl":                 "Amount",
+		"Benutzer/Thema filtern": "Filter user/topic",
+		"Punkte":                 "Points",
+		"Zeigt %v bis %v von %v": "Showing %v to %v of %v",
+		"Zeitraum":               "Period",
+		"Gesamte Zeit":           "All-time",
+		"Letzte 7 Tage":          "Last 7 days",
+		"Letzte 30 Tage":         "Last 30 days",
+		"Es wurden keine Spielresultate gefunden.":          "No results were found.",
+		"Dieses Thema wurde noch nicht gespielt.":           "This topic hasn't been played yet.",
+		"Ganzes Leaderboard anzeigen":                       "Show whole leaderboard",
+		"Leaderboard der Klasse %v":                         "Leaderboard of class %v",
+		"Ansicht":                                           "View",
+		"Bestes Resultat pro Thema":                         "Best result per topic",
+		"Gesamtpunktzahl aller Themen":                      "Total points of all topics",
+		"Alle Spielresultate":                               "All results",
+		"Mehrere Themen":                                    "Several topics",
+		"Wertung":                                           "Rating",
+		"Nach Ihrem ersten Quiz erhalten Sie eine Wertung.": "You receive a rating after your first quiz.",
+
+		// Quiz of the day
+		"Quiz des Tages": "Quiz of the day",
+		"Archiv":         "Archive",
+		"Erraten Sie die genauen Jahreszahlen der heutigen Ereignisse aus allen Themen. Das Quiz kann einmal pro Tag gespielt werden.": "Guess the exact years of today's events from all topics. The quiz can be played once a day.",
+		"Quiz des Tages vom %v": "Quiz of the day of %v",
+		"Sie haben mit %v Punkten den %v. Platz von %v Spielern erreicht.": "With %v points you reached place %v of %v players.",
+		"Sie haben das Quiz dieses Tages nicht gespielt.":                  "You didn't play the quiz of this day.",
+		"Lösungen":          "Answers",
+		"Spieler":           "Players",
+		"Höchste Punktzahl": "Highest score",
+		"Heute":             "Today",
+		"Es wurde noch kein Quiz des Tages gespielt.": "No quiz of the day has been played yet.",
+
+		// Suggestions
+		"Korrektur für '%v' (%v)": "Correction of '%v' (%v)",
+		"Ihr Vorschlag wird von einem Administrator geprüft, bevor er übernommen wird. Ob er angenommen oder abgelehnt wurde, sehen Sie in Ihrem Profil.": "Your suggestion is reviewed by an administrator before it is applied. You can see in your profile whether it was accepted or rejected.",
+		"Optionale Korrektur des Namens":         "Optional correction of the name",
+		"Korrektes Jahr":                         "Correct year",
+		"Kommentar":                              "Comment",
+		"Optionale Begründung, z.B. eine Quelle": "Optional reason, e.g. a source",
+		"Vorschlag senden":                       "Send suggestion",
+		"Offen":                                  "Open",
+		"Angenommen":                             "Accepted",
+		"Abgelehnt":                              "Rejected",
+		"Warteschlange":                          "Queue",
+		"Bearbeitete Vorschläge":                 "Processed suggestions",
+		"Vorschlag":                              "Suggestion",
+		"Begründung":                             "Reason",
+		"Korrektur":                              "Correction",
+		"Gelöschtes Ereignis":                    "Deleted event",
+		"Neu":                                    "New",
+		"Übernehmen":                             "Apply",
+		"Ablehnen":                               "Reject",
+		"Begründung für den Benutzer":            "Reason for the user",
+		"Es wurden keine Vorschläge gefunden.":   "No suggestions were found.",
+
+		// Audit log
+		"Erstellt":                           "Created",
+		"Bearbeitet":                         "Edited",
+		"Gelöscht":                           "Deleted",
+		"Importiert":                         "Imported",
+		"Befördert":                          "Promoted",
+		"Wiederhergestellt":                  "Restored",
+		"Endgültig gelöscht":                 "Permanently deleted",
+		"Zurückgesetzt":                      "Reverted",
+		"Übersetzt":                          "Translated",
+		"Filter":                             "Filter",
+		"Benutzername":                       "Username",
+		"Aktion":                             "Action",
+		"Typ":                                "Type",
+		"Änderungen":                         "Changes",
+		"Anzeigen":                           "Show",
+		"Vorher":                             "Before",
+		"Nachher":                            "After",
+		"Es wurden keine Einträge gefunden.": "No entries were found.",
+
+		// Trash
+		"Gelöschte Themen, Ereignisse und Benutzer können während %v Tagen wiederhergestellt werden. Danach werden sie endgültig gelöscht.": "Deleted topics, events and users can be restored for %v days. Afterwards they are permanently deleted.",
+		"Jahre":            "Years",
+		"Gelöscht am":      "Deleted on",
+		"noch %v Tage":     "%v days left",
+		"Wiederherstellen": "Restore",
+		"Es befinden sich keine Themen im Papierkorb.":     "There are no topics in the trash.",
+		"Es befinden sich keine Ereignisse im Papierkorb.": "There are no events in the trash.",
+		"Es befinden sich keine Benutzer im Papierkorb.":   "There are no users in the trash.",
+
+		// Users
+		"Alle Benutzer":                    "All users",
+		"Benutzer mit verifizierter Email": "Users with verified email",
+		"Benutzer mit gespielten Quiz":     "Users with played quizzes",
+		"Zum Admin befördern":              "Promote to admin",
+		"Das Befördern eines Benutzers zum Admin kann nicht rückgängig gemacht werden. Damit bekommt der Benutzer die selben Rechte wie Sie.": "Promoting a user to admin cannot be undone. The user thereby gets the same rights as you.",
+		"Befördern":        "Promote",
+		"Benutzer löschen": "Delete user",
+		"Der Benutzer wird in den Papierkorb verschoben und kann dort wiederhergestellt werden, bevor er mitsamt allen Spielresultaten endgültig gelöscht wird.": "The user is moved to the trash and can be restored there, before they are permanently deleted together with all of their results.",
+		"Willkommen zurück!":      "Welcome back!",
+		"Benutzername oder Email": "Username or email",
+		"Passwort":                "Password",
+		"Einloggen":               "Log in",
+		"Account erstellen!":      "Create account!",
+		"Passwort vergessen?":     "Forgot password?",
+		"Neuer Account erstellen": "Create new account",
+		"Account vorhanden?":      "Already have an account?",
+		"Passwort vergessen":      "Forgot password",
+		"Email versenden":         "Send email",
+		"Passwort zurücksetzen":   "Reset password",
+		"Neues Passwort":          "New password",
+		"Email ändern":            "Change email",
+		"Passwort ändern":         "Change password",
+		"Benutzernamen ändern":    "Change username",
+		"Klasse ändern":           "Change class",
+		"z.B. 4a (leer lassen, um keiner Klasse anzugehören)":                 "e.g. 4a (leave empty to belong to no class)",
+		"Im Leaderboard können die Resultate Ihrer Klasse verglichen werden.": "The results of your class can be compared in the leaderboard.",
+		"Neue Email":         "New email",
+		"Neuer Benutzername": "New username",
+
+		// Profile
+		"Account-Daten":                    "Account data",
+		"Benutzername:":                    "Username:",
+		"Benutzername bearbeiten":          "Edit username",
+		"Email:":                           "Email:",
+		"Bestätigungs-Email erneut senden": "Resend confirmation email",
+		"Email bearbeiten":                 "Edit email",
+		"Passwort:":                        "Password:",
+		"Passwort bearbeiten":              "Edit password",
+		"Klasse:":                          "Class:",
+		"Keine":                            "None",
+		"Klasse bearbeiten":                "Edit class",
+		"Bestes Spielresultat pro Thema":   "Best result per topic",
+		"Meine Vorschläge":                 "My suggestions",
+		"Begründung: %v":                   "Reason: %v",
+
+		// Statistics
+		"Statistik pro Thema":                  "Statistics per topic",
+		"%v Quiz(ze)":                          "%v quiz(zes)",
+		"Durchschnitt und bestes Resultat (%)": "Average and best result (%)",
+		"Verlauf der Punkte":                   "History of points",
+		"Versuche":                             "Attempts",
+		"Durchschnitt":                         "Average",
+		"Bestes Resultat":                      "Best result",
+		"Besser als":                           "Better than",
+		"%v Spieler":                           "%v players",
+		"Nach Ihrem ersten Quiz sehen Sie hier Ihre Statistik.": "You see your statistics here after your first quiz.",
+
+		// Activity
+		"Aktivität":                   "Activity",
+		"Aktuelle Serie (Tage)":       "Current streak (days)",
+		"Längste Serie (Tage)":        "Longest streak (days)",
+		"Aktive Tage im letzten Jahr": "Active days in the past year",
+		"%v Quiz(ze), %v Übung(en)":   "%v quiz(zes), %v practice session(s)",
+		"Weniger":                     "Less",
+		"Mehr":                        "More",
+
+		// Achievements
+		"Auszeichnungen":                                         "Achievements",
+		"Noch nicht freigeschaltet":                              "Not unlocked yet",
+		"Perfektionist":                                          "Perfectionist",
+		"Alle Ereignisse in Phase 3 richtig geordnet.":           "Ordered all events of phase 3 correctly.",
+		"Weltenbummler":                                          "Globetrotter",
+		"%v verschiedene Themen gespielt.":                       "Played %v different topics.",
+		"Dranbleiber":                                            "Persistent",
+		"An %v Tagen in Folge ein Quiz gespielt.":                "Played a quiz on %v consecutive days.",
+		"Spitzenreiter":                                          "Leader",
+		"Den ersten Platz im Leaderboard eines Themas erreicht.": "Reached first place on the leaderboard of a topic.",
+		"Neue Auszeichnung freigeschaltet: %v":                   "New achievement unlocked: %v",
+		"%v neue Auszeichnungen freigeschaltet. Sie finden sie in Ihrem Profil.": "%v new achievements unlocked. You can find them in your profile.",
+
+		// Errors of forms
+		"Name darf nicht leer sein.":                                                                       "Name must not be empty.",
+		"Name darf 50 Zeichen nicht überschreiten.":                                                        "Name must not exceed 50 characters.",
+		"Name darf 150 Zeichen nicht überschreiten.":                                                       "Name must not exceed 150 characters.",
+		"Start-Jahr muss positiv sein.":                                                                    "Start year must be positive.",
+		"End-Jahr muss positiv sein.":                                                                      "End year must be positive.",
+		"Start-Jahr darf nicht in der Zukunft sein.":                                                       "Start year must not be in the future.",
+		"End-Jahr darf nicht in der Zukunft sein.":                                                         "End year must not be in the future.",
+		"Da wurden wohl Start- und End-Jahr vertauscht.":                                                   "Start and end year seem to have been swapped.",
+		"Beschreibung darf 1000 Buchstaben nicht überschreiten.":                                           "Description must not exceed 1000 characters.",
+		"Es sind höchstens 10 Tags erlaubt.":                                                               "At most 10 tags are allowed.",
+		"Tag '%v' darf 30 Zeichen nicht überschreiten.":                                                    "Tag '%v' must not exceed 30 characters.",
+		"URL des Fotos darf nicht leer sein.":                                                              "URL of the image must not be empty.",
+		"URL der Quelle darf 5000 Buchstaben nicht überschreiten.":                                         "URL of the source must not exceed 5000 characters.",
+		"URL der Quelle muss mit HTTP:// oder HTTPS:// beginnen.":                                          "URL of the source must start with HTTP:// or HTTPS://.",
+		"URL der Quelle ist ungültig.":                                                                     "URL of the source is invalid.",
+		"Kommentar darf 1000 Buchstaben nicht überschreiten.":                                              "Comment must not exceed 1000 characters.",
+		"Jahr/Datum darf nicht leer sein.":                                                                 "Year/date must not be empty.",
+		"Jahr muss positiv sein.":                                                                          "Year must be positive.",
+		"Wird hier die Zukunft vorausgesagt?":                                                              "Is the future being predicted here?",
+		"URL des Fotos darf 5000 Buchstaben nicht überschreiten.":                                          "URL of the image must not exceed 5000 characters.",
+		"URL des Fotos muss mit HTTP:// oder HTTPS:// beginnen oder auf ein hochgeladenes Foto verweisen.": "URL of the image must start with HTTP:// or HTTPS:// or refer to an uploaded image.",
+		"URL des Fotos muss auf '.PNG', '.JPG', '.JPEG' oder '.GIF' enden.":                                "URL of the image must end with '.PNG', '.JPG', '.JPEG' or '.GIF'.",
+		"URL des Fotos ist ungültig.":                                                                      "URL of the image is invalid.",
+		"Das hochgeladene Bild ist leer.":                                                                  "The uploaded image is empty.",
+		"Das Bild darf höchstens %v MB gross sein.":                                                        "The image must not be larger than %v MB.",
+		"Das Bild muss vom Typ PNG, JPG oder GIF sein.":                                                    "The image must be of type PNG, JPG or GIF.",
+		"Das Bild ist beschädigt und kann nicht gelesen werden.":                                           "The image is damaged and cannot be read.",
+		"Das Bild darf höchstens %vx%v Pixel gross sein.":                                                  "The image must not be larger than %vx%v pixels.",
+		"Das Bild konnte nicht gelesen werden.":                                                            "The image could not be read.",
+		"Übergeordnetes Thema existiert nicht.":                                                            "Parent topic does not exist.",
+		"Ein Thema kann nicht sich selbst oder einem seiner Unterthemen untergeordnet werden.":             "A topic cannot be placed under itself or one of its sub-topics.",
+		"Benutzername ist bereits vergeben.":                                                               "Username is already taken.",
+		"Email ist bereits vergeben.":                                                                      "Email is already taken.",
+		"Bitte Benutzernamen order Email angeben.":                                                         "Please enter a username or email.",
+		"Ungültiger Benutzername oder Email.":                                                              "Invalid username or email.",
+		"Bitte Passwort angeben.":                                                                          "Please enter a password.",
+		"Ungültiges Passwort.":                                                                             "Invalid password.",
+		"Geben Sie Ihr Passwort ein.":                                                                      "Enter your password.",
+		"Passwort ist inkorrekt.":                                                                          "Password is incorrect.",
+		"Geben Sie Ihr altes Passwort ein.":                                                                "Enter your old password.",
+		"Altes Passwort ist inkorrekt.":                                                                    "Old password is incorrect.",
+		"Bitte Email angeben.":                                                                             "Please enter an email.",
+		"Es gibt keinen Account mit dieser Email.":                                                         "There is no account with this email.",
+		"Ihre Email wurde nie bestätigt. Sie können derzeit das Passwort nicht zurücksetzen.":              "Your email was never confirmed. You currently cannot reset the password.",
+		"Bitte Benutzernamen angeben.":                                                                     "Please enter a username.",
+		"Benutzername muss mindestens 3 Zeichen lang sein.":                                                "Username must be at least 3 characters long.",
+		"Benutzername darf höchstens 20 Zeichen lang sein.":                                                "Username must be at most 20 characters long.",
+		"Benutzername darf nur Buchstaben, Zahlen, '.' und '_' enthalten.":                                 "Username may only contain letters, numbers, '.' and '_'.",
+		"Klasse darf 20 Zeichen nicht überschreiten.":                                                      "Class must not exceed 20 characters.",
+		"Klasse darf nur Buchstaben, Zahlen, Leerzeichen, '.', '_' und '-' enthalten.":                     "Class may only contain letters, numbers, spaces, '.', '_' and '-'.",
+		"Benutzername muss mindestens 1 Buchstaben enthalten.":                                             "Username must contain at least 1 letter.",
+		"Benutzername darf nicht mit '.' oder '_' beginnen.":                                               "Username must not start with '.' or '_'.",
+		"Benutzername darf nicht mit '.' oder '_' enden.":                                                  "Username must not end with '.' or '_'.",
+		"Benutzername darf '.' und '_' nicht aufeinanderfolgend haben.":                                    "Username must not have consecutive '.' and '_'.",
+		"Email muss mindestens 3 Zeichen lang sein.":                                                       "Email must be at least 3 characters long.",
+		"Email darf höchstens 100 Zeichen lang sein.":                                                      "Email must be at most 100 characters long.",
+		"Ungültiges Email-Format.":                                                                         "Invalid email format.",
+		"Passwort muss mindestens 6 Zeichen lang sein.":                                                    "Password must be at least 6 characters long.",
+		"Passwort muss mindestens einen Buchstaben enthalten.":                                             "Password must contain at least one letter.",
+		"Passwort muss mindestens eine Zahl enthalten.":                                                    "Password must contain at least one number.",
+		"Sprache wird nicht unterstützt.":                                                                  "Language is not supported.",
+
+		// Flash messages and errors of imports
+		"Thema wurde erfolgreich erstellt.":                                                                                    "Topic was created successfully.",
+		"Thema wurde in den Papierkorb verschoben.":                                                                            "Topic was moved to the trash.",
+		"Thema wurde erfolgreich bearbeitet.":                                                                                  "Topic was edited successfully.",
+		"Thema wurde auf die Version vom %v zurückgesetzt.":                                                                    "Topic was reverted to the version of %v.",
+		"Thema wurde erfolgreich importiert, inklusive %v Ereignissen.":                                                        "Topic was imported successfully, including %v events.",
+		"Da bereits ein Thema '%v' existiert, wurde das importierte Thema in '%v' umbenannt.":                                  "Since a topic '%v' already exists, the imported topic was renamed to '%v'.",
+		"Die Datei ist kein gültiges exportiertes Thema.":                                                                      "The file is not a valid exported topic.",
+		"Die Version %v der Datei wird nicht unterstützt (erwartet: %v).":                                                      "Version %v of the file is not supported (expected: %v).",
+		"Ungültiges Thema: %v":                                                                                                 "Invalid topic: %v",
+		"Ereignis wurde erfolgreich erstellt.":                                                                                 "Event was created successfully.",
+		"Ereignis wurde in den Papierkorb verschoben.":                                                                         "Event was moved to the trash.",
+		"Ereignis wurde erfolgreich bearbeitet.":                                                                               "Event was edited successfully.",
+		"Ereignis wurde auf die Version vom %v zurückgesetzt.":                                                                 "Event was reverted to the version of %v.",
+		"Übersetzung wurde erfolgreich gespeichert.":                                                                           "Translation was saved successfully.",
+		"Es gibt keine gültigen Ereignisse zum Importieren.":                                                                   "There are no valid events to import.",
+		"Es gibt keine Ereignisse ohne Duplikate zum Importieren.":                                                             "There are no events without duplicates to import.",
+		"%v Ereignisse wurden erfolgreich importiert.":                                                                         "%v events were imported successfully.",
+		"Die Datei ist zu gross (max. 1 MB).":                                                                                  "The file is too large (max. 1 MB).",
+		"Die Datei konnte nicht gelesen werden.":                                                                               "The file could not be read.",
+		"Bitte laden Sie eine Datei hoch oder fügen Sie den Inhalt ein.":                                                       "Please upload a file or paste its content.",
+		"Ungültiges JSON. Erwartet wird eine Liste von Objekten mit 'name' und 'year'.":                                        "Invalid JSON. A list of objects with 'name' and 'year' is expected.",
+		"Ungültiges CSV in Zeile %v.":                                                                                          "Invalid CSV in line %v.",
+		"Es wurden keine Ereignisse gefunden.":                                                                                 "No events were found.",
+		"Es können höchstens %v Ereignisse auf einmal importiert werden.":                                                      "At most %v events can be imported at once.",
+		"Das Thema '%v' hat nicht genügend Ereignisse (min. %v), um ein Quiz zur Verfügung zu stellen.":                        "The topic '%v' does not have enough events (min. %v) to provide a quiz.",
+		"Ein Fehler ist aufgetreten in Phase %v des Quiz. Bitte starten Sie ein Quiz nur über die Themenübersicht.":            "An error occurred in phase %v of the quiz. Please only start a quiz via the topic overview.",
+		"Ein Fehler ist aufgetreten in Phase %v des Quiz. Womöglich haben Sie versucht, während des Quiz das Thema zu ändern.": "An error occurred in phase %v of the quiz. You might have tried to change the topic during the quiz.",
+		"Ein Fehler ist aufgetreten in Phase %v des Quiz. Womöglich haben Sie versucht, eine Phase des Quiz zu überspringen oder zu wiederholen.": "An error occurred in phase %v of the quiz. You might have tried to skip or repeat a phase of the quiz.",
+		"Ein Fehler ist aufgetreten in Phase %v des Quiz. Die Anzahl Antworten stimmt nicht mit der Anzahl Fragen überein.":                       "An error occurred in phase %v of the quiz. The number of answers does not match the number of questions.",
+		"Vielen Dank für Ihren Vorschlag! Er wird nun von einem Administrator geprüft.":                                                           "Thank you for your suggestion! It will now be reviewed by an administrator.",
+		"Vorschlag wurde bereits bearbeitet.":                                                                         "Suggestion has already been processed.",
+		"Ereignis existiert nicht mehr. Der Vorschlag kann nur noch abgelehnt werden.":                                "Event no longer exists. The suggestion can only be rejected.",
+		"Thema existiert nicht mehr. Der Vorschlag kann nur noch abgelehnt werden.":                                   "Topic no longer exists. The suggestion can only be rejected.",
+		"Vorschlag wurde erfolgreich übernommen.":                                                                     "Suggestion was applied successfully.",
+		"Begründung darf nicht leer sein und 1000 Buchstaben nicht überschreiten.":                                    "Reason must not be empty and must not exceed 1000 characters.",
+		"Vorschlag wurde abgelehnt.":                                                                                  "Suggestion was rejected.",
+		"Thema wurde erfolgreich wiederhergestellt.":                                                                  "Topic was restored successfully.",
+		"Ereignis wurde erfolgreich wiederhergestellt.":                                                               "Event was restored successfully.",
+		"Benutzer wurde erfolgreich wiederhergestellt.":                                                               "User was restored successfully.",
+		"Ihr Benutzername wurde erfolgreich geändert.":                                                                "Your username was changed successfully.",
+		"Ihre Email wurde erfolgreich geändert.":                                                                      "Your email was changed successfully.",
+		"Ihr Passwort wurde erfolgreich geändert.":                                                                    "Your password was changed successfully.",
+		"Ihre Klasse wurde erfolgreich geändert.":                                                                     "Your class was changed successfully.",
+		"Sie sind bereits eingeloggt.":                                                                                "You are already logged in.",
+		"Willkommen %v! Ihre Registrierung war erfolgreich. Sie sind nun eingeloggt.":                                 "Welcome %v! Your registration was successful. You are now logged in.",
+		"Eine Bestätigungs-Email wurde an %v versandt. Bitte tätigen Sie diesen Link, um Ihre Email zu verifizieren.": "A confirmation email was sent to %v. Please follow its link to verify your email.",
+		"Eine Bestätigungs-Email wurde an %v versandt.":                                                               "A confirmation email was sent to %v.",
+		"Hallo %v! Sie sind nun eingeloggt.":                                                                          "Hello %v! You are now logged in.",
+		"Sie haben Ihre Email noch nicht verifiziert. Ohne verifizierte Email können Sie im Fall der Fälle Ihr Passwort nicht via Email zurücksetzen. Auf Ihrem Profil können Sie eine erneute Bestätigungs-Email versenden.": "You have not verified your email yet. Without a verified email you cannot reset your password via email if need be. You can resend a confirmation email on your profile.",
+		"Sie wurden erfolgreich ausgeloggt.":                                                                                                           "You were logged out successfully.",
+		"Ihr Token zum Bestätigen der Email ist ungültig.":                                                                                             "Your token for confirming the email is invalid.",
+		"Ihre Email wurde erfolgreich bestätigt.":                                                                                                      "Your email was confirmed successfully.",
+		"Beim Versenden der Email ist ein Fehler aufgetreten. Bitte versuchen Sie es später erneut.":                                                   "An error occurred while sending the email. Please try again later.",
+		"Eine Email zum Zurücksetzen Ihres Passworts wurde an %v versandt.":                                                                            "An email for resetting your password was sent to %v.",
+		"Der Token zum Zurücksetzen Ihres Passworts ist ungültig.":                                                                                     "The token for resetting your password is invalid.",
+		"Der Token ist abgelaufen. Sie haben jeweils 1 Stunde Zeit, um Ihr Passwort zurückzusetzen.":                                                   "The token has expired. You have 1 hour each time to reset your password.",
+		"Ihr Passwort wurde erfolgreich geändert. Bitte loggen Sie sich ein.":                                                                          "Your password was changed successfully. Please log in.",
+		"Ungültiges Ereignis Nr. %v ('%v'): %v":                                                                                                        "Invalid event no. %v ('%v'): %v",
+		"Sie haben das Quiz des Tages bereits gespielt. Morgen gibt es ein neues.":                                                                     "You have already played the quiz of the day. There will be a new one tomorrow.",
+		"Es gibt noch nicht genügend Ereignisse für das Quiz des Tages.":                                                                               "There are not enough events for the quiz of the day yet.",
+		"Sie haben im Quiz des Tages %v Punkte erreicht.":                                                                                              "You achieved %v points in the quiz of the day.",
+		"Ein Fehler ist aufgetreten im Quiz des Tages. Bitte starten Sie das Quiz des Tages nur über die Seitenleiste.":                                "An error occurred in the quiz of the day. Please only start the quiz of the day via the sidebar.",
+		"Ein Fehler ist aufgetreten im Quiz des Tages. Womöglich haben Sie versucht, das Quiz des Tages zu wiederholen.":                               "An error occurred in the quiz of the day. You might have tried to repeat the quiz of the day.",
+		"Ein Fehler ist aufgetreten im Quiz des Tages. Das Quiz des Tages ist inzwischen abgelaufen.":                                                  "An error occurred in the quiz of the day. The quiz of the day has expired in the meantime.",
+		"Ein Fehler ist aufgetreten im Quiz des Tages. Womöglich haben Sie das Quiz verlassen und dann versucht, nach über %v Minuten zurückzukehren.": "An error occurred in the quiz of the day. You might have left the quiz and then tried to return after more than %v minutes.",
+	}
+)
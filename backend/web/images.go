@@ -0,0 +1,231 @@
+// Responsible for validating uploaded images, such as images of topics, and
+// generating thumbnails of them. The images and thumbnails get saved in the
+// blob store.
+
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF-decoder
+	"image/jpeg"
+	_ "image/png" // register PNG-decoder
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	imageMaxSize       = 5 << 20 // max size of an uploaded image (5 MB)
+	imageMaxDimension  = 6000    // max width and height of an uploaded image in pixels
+	thumbnailWidth     = 400     // width of a generated thumbnail in pixels
+	thumbnailExtension = "_thumb.jpg"
+)
+
+var (
+	// Allowed types of uploaded images and their file extensions
+	imageTypes = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+		"image/gif":  ".gif",
+	}
+)
+
+// validateImageUpload validates the content of an uploaded image by its size,
+// type and dimensions. It returns an error message or an empty string if the
+// image is valid.
+// (Tested in handler_test.go)
+func validateImageUpload(content []byte) string {
+
+	if len(content) == 0 {
+		return "Das hochgeladene Bild ist leer."
+	}
+	if len(content) > imageMaxSize {
+		return fmt.Sprintf("Das Bild darf höchstens %v MB gross sein.", imageMaxSize>>20)
+	}
+
+	// The type gets detected by the content, since the file extension could
+	// be wrong
+	if _, ok := imageTypes[http.DetectContentType(content)]; !ok {
+		return "Das Bild muss vom Typ PNG, JPG oder GIF sein."
+	}
+
+	// Decode only the dimensions, to avoid decoding huge images
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return "Das Bild ist beschädigt und kann nicht gelesen werden."
+	}
+	if config.Width > imageMaxDimension || config.Height > imageMaxDimension {
+		return fmt.Sprintf("Das Bild darf höchstens %vx%v Pixel gross sein.", imageMaxDimension, imageMaxDimension)
+	}
+
+	return ""
+}
+
+// readImageUpload retrieves the content of an image uploaded through the form
+// input 'image_file'. It returns nil if no image was uploaded, or an error
+// message if the upload couldn't be received.
+func readImageUpload(res http.ResponseWriter, req *http.Request) ([]byte, string) {
+
+	// Limit size of request body (the image plus the other form inputs)
+	req.Body = http.MaxBytesReader(res, req.Body, imageMaxSize+(1<<20))
+	if err := req.ParseMultipartForm(imageMaxSize); err != nil {
+		if err == http.ErrNotMultipart {
+			return nil, ""
+		}
+		return nil, fmt.Sprintf("Das Bild darf höchstens %v MB gross sein.", imageMaxSize>>20)
+	}
+
+	// Check for uploaded image
+	file, _, err := req.FormFile("image_file")
+	if err != nil { // no image was uploaded
+		return nil, ""
+	}
+	defer file.Close()
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, "Das Bild konnte nicht gelesen werden."
+	}
+
+	return content, ""
+}
+
+// saveImage saves a validated image including its thumbnail in the blob store
+// and returns the URL of the image.
+func saveImage(blobs x.BlobStore, content []byte) (string, error) {
+
+	// Generate random name, which is the same for image and thumbnail
+	name := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(generateRandomString(24)))
+	extension := imageTypes[http.DetectContentType(content)]
+
+	// Generate thumbnail
+	thumbnail, err := createThumbnail(content)
+	if err != nil {
+		return "", err
+	}
+
+	// Save image and thumbnail
+	imageURL, err := blobs.SaveBlob(name+extension, content)
+	if err != nil {
+		return "", err
+	}
+	if _, err = blobs.SaveBlob(name+thumbnailExtension, thumbnail); err != nil {
+		return "", err
+	}
+
+	return imageURL, nil
+}
+
+// deleteImage deletes an image including its thumbnail from the blob store, if
+// it was uploaded and isn't referenced by any other topic, event or revision
+// anymore, since the same image may be used multiple times. Errors only get
+// logged, since a leftover file doesn't affect the user.
+func deleteImage(store x.RevisionStore, blobs x.BlobStore, imageURL string) {
+
+	if !blobs.OwnsBlob(imageURL) {
+		return
+	}
+
+	referenced, err := store.IsImageReferenced(imageURL)
+	if err != nil {
+		log.Printf("error checking references of image %v: %v", imageURL, err)
+		return
+	}
+	if referenced {
+		return
+	}
+
+	for _, url := range []string{imageURL, thumbnailURL(imageURL)} {
+		if err = blobs.DeleteBlob(url); err != nil {
+			log.Printf("error deleting image %v: %v", url, err)
+		}
+	}
+}
+
+// thumbnailURL returns the URL of the thumbnail of an uploaded image. External
+// images don't have a thumbnail, so their URL stays the same.
+// Example: '/frontend/static/uploads/abc.png' => '/frontend/static/uploads/abc_thumb.jpg'
+// (Tested in handler_test.go)
+func thumbnailURL(imageURL string) string {
+
+	if !strings.HasPrefix(imageURL, "/"+uploadPath+"/") {
+		return imageURL
+	}
+
+	if dot := strings.LastIndex(imageURL, "."); dot > strings.LastIndex(imageURL, "/") {
+		imageURL = imageURL[:dot]
+	}
+
+	return imageURL + thumbnailExtension
+}
+
+// createThumbnail generates a downscaled JPEG-version of an image. Transparent
+// areas become white, since JPEG doesn't support transparency.
+func createThumbnail(content []byte) ([]byte, error) {
+
+	// Decode image (only the first frame of a GIF)
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	// Encode downscaled image
+	var buffer bytes.Buffer
+	if err = jpeg.Encode(&buffer, resizeImage(src, thumbnailWidth), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// resizeImage downscales an image to a certain width, while keeping its aspect
+// ratio. Each pixel of the new image is the average of the area of pixels it
+// covers in the original image. Images narrower than the width don't get
+// upscaled.
+func resizeImage(src image.Image, width int) *image.RGBA {
+
+	bounds := src.Bounds()
+	width = min(width, bounds.Dx())
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		// Area of the original image covered by this row
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+
+			// Sum up colors of the area
+			var r, g, b, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA() // premultiplied by alpha
+					r += uint64(cr + 0xffff - ca)           // blend with white background
+					g += uint64(cg + 0xffff - ca)
+					b += uint64(cb + 0xffff - ca)
+					count++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: 0xffff,
+			})
+		}
+	}
+
+	return dst
+}
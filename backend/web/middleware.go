@@ -0,0 +1,142 @@
+// The middleware responsible for authorization, which gets applied to groups of
+// routes instead of checking the permission of a user in every HTTP-handler
+// function. HTML-clients get an error page, while JSON-clients get an error
+// object, both with the status code 401 (not logged in) or 403 (insufficient
+// permission).
+
+package web
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	unauthorizedError = "Unzureichende Berechtigung. Sie müssen eingeloggt sein, um diese Seite aufzurufen."
+	notAdminError     = "Unzureichende Berechtigung. Sie müssen als Admin eingeloggt sein, um diese Seite aufzurufen."
+	notVerifiedError  = "Unzureichende Berechtigung. Sie müssen zuerst Ihre Email bestätigen, um diese Seite " +
+		"aufzurufen. Auf Ihrem Profil können Sie eine erneute Bestätigungs-Email versenden."
+)
+
+var (
+	// Parsed HTML-template to be executed when access to a route is denied
+	httpAccessDeniedTemplate *template.Template
+)
+
+// init gets initialized with the package.
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
+func init() {
+	if _testing { // skip initialization of templates when running tests
+		return
+	}
+
+	httpAccessDeniedTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"http_access_denied.html"))
+}
+
+// RequireLogin is a middleware that only grants access to users that are
+// logged in.
+func (h *Handler) RequireLogin(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, ok := req.Context().Value("user").(x.User); !ok {
+			h.accessDenied(res, req, http.StatusUnauthorized, unauthorizedError)
+			return
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
+
+// RequireAdmin is a middleware that only grants access to admins.
+func (h *Handler) RequireAdmin(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		user, ok := req.Context().Value("user").(x.User)
+		if !ok {
+			h.accessDenied(res, req, http.StatusUnauthorized, unauthorizedError)
+			return
+		}
+		if !user.Admin {
+			h.accessDenied(res, req, http.StatusForbidden, notAdminError)
+			return
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
+
+// RequireVerified is a middleware that only grants access to users, whose
+// email has been verified.
+func (h *Handler) RequireVerified(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		user, ok := req.Context().Value("user").(x.User)
+		if !ok {
+			h.accessDenied(res, req, http.StatusUnauthorized, unauthorizedError)
+			return
+		}
+		if !user.Verified {
+			h.accessDenied(res, req, http.StatusForbidden, notVerifiedError)
+			return
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
+
+// accessDenied responds with the status code 401 or 403 and an error message,
+// either as a JSON-object or as an HTML-page.
+func (h *Handler) accessDenied(res http.ResponseWriter, req *http.Request, status int, msg string) {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+
+		Status  int
+		Message string
+	}
+
+	msg = translate(localeOf(req.Context()), msg)
+
+	if wantsJSON(req) {
+		res.Header().Set("Content-Type", "application/json; charset=utf-8")
+		res.WriteHeader(status)
+		_ = json.NewEncoder(res).Encode(map[string]interface{}{
+			"status": status,
+			"error":  msg,
+		})
+		return
+	}
+
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	res.WriteHeader(status)
+	if err := httpAccessDeniedTemplate.Execute(res, data{
+		SessionData: GetSessionData(h.sessions, req.Context()),
+		Status:      status,
+		Message:     msg,
+	}); err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// wantsJSON checks if a request comes from a JSON-client, such as a script
+// calling a JSON-endpoint, rather than from a browser.
+// (Tested in middleware_test.go)
+func wantsJSON(req *http.Request) bool {
+
+	accept := req.Header.Get("Accept")
+	if strings.Contains(accept, "text/html") {
+		return false
+	}
+
+	return strings.Contains(accept, "application/json") ||
+		strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") ||
+		req.Header.Get("X-Requested-With") == "XMLHttpRequest"
+}
@@ -0,0 +1,356 @@
+// Collection of tests for the authorization middleware, including a test
+// ensuring that every route is protected by the intended middleware.
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	// Access levels of routes
+	accessPublic   = "public"
+	accessLogin    = "login"
+	accessAdmin    = "admin"
+	accessVerified = "admin & verified"
+)
+
+var (
+	// tRoutes contains the access level of every route
+	tRoutes = map[string]string{
+		"GET /":                   accessPublic,
+		"GET /search":             accessPublic,
+		"GET /search/suggestions": accessPublic,
+
+		"GET /topics/":                                         accessPublic,
+		"GET /topics/{topicID}":                                accessPublic,
+		"GET /topics/{topicID}/anki":                           accessLogin,
+		"GET /topics/{topicID}/worksheet":                      accessLogin,
+		"GET /topics/{topicID}/suggest":                        accessLogin,
+		"POST /topics/{topicID}/suggest":                       accessLogin,
+		"GET /topics/new":                                      accessAdmin,
+		"POST /topics/":                                        accessAdmin,
+		"POST /topics/{topicID}/delete":                        accessAdmin,
+		"GET /topics/{topicID}/edit":                           accessAdmin,
+		"POST /topics/{topicID}/edit":                          accessAdmin,
+		"POST /topics/{topicID}/revisions/{revisionID}/revert": accessAdmin,
+		"POST /topics/{topicID}/translations":                  accessAdmin,
+		"GET /topics/{topicID}/export":                         accessAdmin,
+		"GET /topics/import":                                   accessAdmin,
+		"POST /topics/import":                                  accessAdmin,
+
+		"GET /topics/{topicID}/events/":                                         accessLogin,
+		"GET /topics/{topicID}/events/new":                                      accessAdmin,
+		"POST /topics/{topicID}/events/":                                        accessAdmin,
+		"POST /topics/{topicID}/events/{eventID}/delete":                        accessAdmin,
+		"GET /topics/{topicID}/events/{eventID}/edit":                           accessAdmin,
+		"POST /topics/{topicID}/events/{eventID}/edit":                          accessAdmin,
+		"POST /topics/{topicID}/events/{eventID}/revisions/{revisionID}/revert": accessAdmin,
+		"POST /topics/{topicID}/events/{eventID}/translations":                  accessAdmin,
+		"GET /topics/{topicID}/events/duplicates":                               accessAdmin,
+		"GET /topics/{topicID}/events/import":                                   accessAdmin,
+		"POST /topics/{topicID}/events/import":                                  accessAdmin,
+		"POST /topics/{topicID}/events/import/store":                            accessAdmin,
+
+		"GET /topics/{topicID}/quiz/1":         accessLogin,
+		"POST /topics/{topicID}/quiz/1":        accessLogin,
+		"GET /topics/{topicID}/quiz/1/review":  accessLogin,
+		"POST /topics/{topicID}/quiz/1/review": accessLogin,
+		"GET /topics/{topicID}/quiz/2":         accessLogin,
+		"POST /topics/{topicID}/quiz/2":        accessLogin,
+		"GET /topics/{topicID}/quiz/2/review":  accessLogin,
+		"POST /topics/{topicID}/quiz/2/review": accessLogin,
+		"GET /topics/{topicID}/quiz/3":         accessLogin,
+		"POST /topics/{topicID}/quiz/3":        accessLogin,
+		"GET /topics/{topicID}/quiz/3/review":  accessLogin,
+		"GET /topics/{topicID}/quiz/summary":   accessLogin,
+
+		"GET /challenge/":        accessLogin,
+		"POST /challenge/":       accessLogin,
+		"GET /challenge/archive": accessLogin,
+		"GET /challenge/{day}":   accessLogin,
+
+		"GET /scores": accessLogin,
+
+		"GET /users/register":          accessPublic,
+		"POST /users/register":         accessPublic,
+		"GET /users/login":             accessPublic,
+		"POST /users/login":            accessPublic,
+		"GET /users/verify/email":      accessPublic,
+		"GET /users/forgot/password":   accessPublic,
+		"POST /users/forgot/password":  accessPublic,
+		"GET /users/reset/password":    accessPublic,
+		"POST /users/reset/password":   accessPublic,
+		"GET /users/logout":            accessLogin,
+		"GET /users/profile":           accessLogin,
+		"GET /users/edit/username":     accessLogin,
+		"POST /users/edit/username":    accessLogin,
+		"GET /users/edit/email":        accessLogin,
+		"POST /users/edit/email":       accessLogin,
+		"GET /users/edit/password":     accessLogin,
+		"POST /users/edit/password":    accessLogin,
+		"GET /users/edit/class":        accessLogin,
+		"POST /users/edit/class":       accessLogin,
+		"POST /users/resend/email":     accessLogin,
+		"GET /users/":                  accessVerified,
+		"POST /users/{userID}/delete":  accessVerified,
+		"POST /users/{userID}/promote": accessVerified,
+
+		"GET /suggestions/":                       accessAdmin,
+		"POST /suggestions/{suggestionID}/accept": accessAdmin,
+		"POST /suggestions/{suggestionID}/reject": accessAdmin,
+
+		"GET /audit": accessVerified,
+
+		"GET /trash/":                          accessVerified,
+		"POST /trash/topics/{topicID}/restore": accessVerified,
+		"POST /trash/events/{eventID}/restore": accessVerified,
+		"POST /trash/users/{userID}/restore":   accessVerified,
+	}
+
+	// Mock users with different permissions
+	tUser          = x.User{UserID: 1, Username: "user", Verified: true}
+	tAdmin         = x.User{UserID: 2, Username: "admin", Admin: true}
+	tAdminVerified = x.User{UserID: 3, Username: "admin", Admin: true, Verified: true}
+
+	// tURLParams matches URL parameters of a route (e.g. '{topicID}')
+	tURLParams = regexp.MustCompile(`{[^}]+}`)
+)
+
+// newTestHandler initializes a handler with all routes, where the user gets
+// added to the request directly instead of through the session.
+func newTestHandler(user *x.User) *Handler {
+
+	handler := &Handler{Mux: chi.NewMux()}
+	handler.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "user", *user))
+			}
+			next.ServeHTTP(res, req)
+		})
+	})
+	handler.routes(nil)
+
+	return handler
+}
+
+// TestRoutesAccessLevel tests that every route denies access to users without
+// the necessary permission. HTTP-handlers of routes that grant access don't
+// get called, since they would require a database.
+func TestRoutesAccessLevel(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		user       *x.User
+		wantStatus map[string]int // expected status of denied access per access level
+	}{
+		{
+			name:       "#1 ANONYMOUS",
+			user:       nil,
+			wantStatus: map[string]int{accessLogin: 401, accessAdmin: 401, accessVerified: 401},
+		},
+		{
+			name:       "#2 USER",
+			user:       &tUser,
+			wantStatus: map[string]int{accessAdmin: 403, accessVerified: 403},
+		},
+		{
+			name:       "#3 ADMIN (NOT VERIFIED)",
+			user:       &tAdmin,
+			wantStatus: map[string]int{accessVerified: 403},
+		},
+	}
+
+	// Check that every route has a declared access level, so that new routes
+	// can't be added without considering their protection
+	walked := map[string]bool{}
+	if err := chi.Walk(newTestHandler(nil), func(method string, route string, _ http.Handler,
+		_ ...func(http.Handler) http.Handler) error {
+		walked[method+" "+route] = true
+		if _, ok := tRoutes[method+" "+route]; !ok {
+			t.Errorf("route %v %v has no declared access level", method, route)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("chi.Walk() error = %v", err)
+	}
+	for route := range tRoutes {
+		if !walked[route] {
+			t.Errorf("route %v doesn't exist", route)
+		}
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := newTestHandler(test.user)
+
+			for route, level := range tRoutes {
+				wantStatus, denied := test.wantStatus[level]
+				if !denied {
+					continue
+				}
+
+				split := strings.SplitN(route, " ", 2)
+				req := httptest.NewRequest(split[0], tURLParams.ReplaceAllString(split[1], "1"), nil)
+				req.Header.Set("Accept", "application/json")
+				res := httptest.NewRecorder()
+
+				handler.ServeHTTP(res, req)
+
+				if res.Code != wantStatus {
+					t.Errorf("%v: status = %v, want %v", route, res.Code, wantStatus)
+					continue
+				}
+
+				var body map[string]interface{}
+				if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil || body["error"] == "" {
+					t.Errorf("%v: body = %v, want JSON with error message", route, res.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// TestRequireMiddleware tests that the middleware grants access to users with
+// the necessary permission.
+func TestRequireMiddleware(t *testing.T) {
+
+	h := &Handler{}
+
+	// Declare test cases
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		user       *x.User
+		wantStatus int
+	}{
+		{
+			name:       "#1 LOGIN OK",
+			middleware: h.RequireLogin,
+			user:       &tUser,
+			wantStatus: 200,
+		},
+		{
+			name:       "#2 LOGIN ANONYMOUS",
+			middleware: h.RequireLogin,
+			user:       nil,
+			wantStatus: 401,
+		},
+		{
+			name:       "#3 ADMIN OK",
+			middleware: h.RequireAdmin,
+			user:       &tAdmin,
+			wantStatus: 200,
+		},
+		{
+			name:       "#4 ADMIN NOT ADMIN",
+			middleware: h.RequireAdmin,
+			user:       &tUser,
+			wantStatus: 403,
+		},
+		{
+			name:       "#5 VERIFIED OK",
+			middleware: h.RequireVerified,
+			user:       &tAdminVerified,
+			wantStatus: 200,
+		},
+		{
+			name:       "#6 VERIFIED NOT VERIFIED",
+			middleware: h.RequireVerified,
+			user:       &tAdmin,
+			wantStatus: 403,
+		},
+		{
+			name:       "#7 VERIFIED ANONYMOUS",
+			middleware: h.RequireVerified,
+			user:       nil,
+			wantStatus: 401,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", "application/json")
+			if test.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "user", *test.user))
+			}
+			res := httptest.NewRecorder()
+
+			test.middleware(next).ServeHTTP(res, req)
+
+			if res.Code != test.wantStatus {
+				t.Errorf("status = %v, want %v", res.Code, test.wantStatus)
+			}
+		})
+	}
+}
+
+// TestWantsJSON tests distinguishing JSON-clients from browsers.
+func TestWantsJSON(t *testing.T) {
+
+	// Declare test cases
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{
+			name:    "#1 BROWSER",
+			headers: map[string]string{"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+			want:    false,
+		},
+		{
+			name:    "#2 ACCEPT JSON",
+			headers: map[string]string{"Accept": "application/json"},
+			want:    true,
+		},
+		{
+			name:    "#3 CONTENT-TYPE JSON",
+			headers: map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			want:    true,
+		},
+		{
+			name:    "#4 XMLHTTPREQUEST",
+			headers: map[string]string{"X-Requested-With": "XMLHttpRequest"},
+			want:    true,
+		},
+		{
+			name:    "#5 NO HEADERS",
+			headers: map[string]string{},
+			want:    false,
+		},
+	}
+
+	// Run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+
+			if got := wantsJSON(req); got != test.want {
+				t.Errorf("wantsJSON() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/gob"
 	"fmt"
 	"html/template"
+	"log"
 	"math/rand"
 	"net/http"
 	"sort"
@@ -45,8 +46,6 @@ const (
 
 	phase3Questions = 10 // amount of events to be put in the correct order in phase 3
 	phase3Points    = 5  // amount of points per correct guess of phase 3 (partial points: -1 per deviation from correct order)
-
-	noPermissionError = "Unzureichende Berechtigung. Sie müssen als Benutzer eingeloggt sein, um ein Quiz zu spielen."
 )
 
 const (
@@ -86,18 +85,27 @@ func init() {
 	gob.Register(phase2Question{})
 	gob.Register([]phase3Question{})
 	gob.Register(phase3Question{})
+	gob.Register(eventDetails{})
 
 	if _testing { // skip initialization of templates when running tests
 		return
 	}
 
-	quizPhase1Template = template.Must(template.ParseFiles(layout, templatePath+"quiz_phase1.html"))
-	quizPhase1ReviewTemplate = template.Must(template.ParseFiles(layout, templatePath+"quiz_phase1_review.html"))
-	quizPhase2Template = template.Must(template.ParseFiles(layout, templatePath+"quiz_phase2.html"))
-	quizPhase2ReviewTemplate = template.Must(template.ParseFiles(layout, templatePath+"quiz_phase2_review.html"))
-	quizPhase3Template = template.Must(template.ParseFiles(layout, templatePath+"quiz_phase3.html"))
-	quizPhase3ReviewTemplate = template.Must(template.ParseFiles(layout, templatePath+"quiz_phase3_review.html"))
-	quizSummaryTemplate = template.Must(template.ParseFiles(layout, templatePath+"quiz_summary.html"))
+	quizPhase1Template = template.Must(newTemplate().ParseFiles(layout, templatePath+"quiz_phase1.html"))
+	quizPhase1ReviewTemplate = parseReviewTemplate("quiz_phase1_review.html")
+	quizPhase2Template = template.Must(newTemplate().ParseFiles(layout, templatePath+"quiz_phase2.html"))
+	quizPhase2ReviewTemplate = parseReviewTemplate("quiz_phase2_review.html")
+	quizPhase3Template = template.Must(newTemplate().ParseFiles(layout, templatePath+"quiz_phase3.html"))
+	quizPhase3ReviewTemplate = parseReviewTemplate("quiz_phase3_review.html")
+	quizSummaryTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"quiz_summary.html"))
+}
+
+// parseReviewTemplate parses the HTML-template of a review including the
+// template showing the details of an event.
+func parseReviewTemplate(file string) *template.Template {
+	return template.Must(newTemplate().
+		Funcs(template.FuncMap{"thumbnail": thumbnailURL}).
+		ParseFiles(layout, templatePath+file, templatePath+"quiz_event_details.html"))
 }
 
 // QuizHandler is the object for handlers to access sessions and database.
@@ -147,17 +155,23 @@ func (h *QuizHandler) Phase1() http.HandlerFunc {
 			return
 		}
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", noPermissionError)
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+		// Execute SQL statement to get topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Execute SQL statement to get topic
-		topic, err := h.store.GetTopic(topicID)
+		// Include the events of all sub-topics, in case of a parent topic
+		topic, err = withDescendantEvents(h.store, topic)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Localize the topic and its events to the language of the player,
+		// which the following phases adopt through the quiz data
+		topic, err = withTranslations(h.store, topic, localeOf(req.Context()))
 		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
@@ -282,15 +296,6 @@ func (h *QuizHandler) Phase1Review() http.HandlerFunc {
 			return
 		}
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", noPermissionError)
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve quiz data from session
 		// 'ok' is false if quiz from session isn't convertible to quizData
 		// struct (so if quiz doesn't exist in session)
@@ -394,15 +399,6 @@ func (h *QuizHandler) Phase2() http.HandlerFunc {
 			return
 		}
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", noPermissionError)
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve quiz data from session
 		// 'ok' is false if quiz from session isn't convertible to quizData
 		// struct (so if quiz doesn't exist in session)
@@ -475,20 +471,11 @@ func (h *QuizHandler) Phase2Submit() http.HandlerFunc {
 			// Check if the user's guess is correct, by comparing it to the
 			// corresponding event in the array of events of the topic
 			correctYear := quiz.Topic.Events[num+phase1Questions].Year
-			if questions[num].UserGuess == correctYear { // if guess is correct...
+			points := yearGuessPoints(correctYear, questions[num].UserGuess)
+			if points == phase2Points { // if guess is correct...
 				quiz.CorrectGuesses++
-				quiz.Points += phase2Points // ...user gets 8 points
-			} else {
-				// Get absolute value of difference between user's guess and
-				// correct year
-				difference := abs(correctYear - questions[num].UserGuess)
-
-				// Check if the user's guess is close and potentially add
-				// partial points (the closer the guess, the more points)
-				if difference < phase2PartialPoints { // if guess is close...
-					quiz.Points += phase2PartialPoints - difference // ...user gets partial points
-				}
 			}
+			quiz.Points += points
 		}
 		quiz.Questions = questions
 
@@ -500,6 +487,26 @@ func (h *QuizHandler) Phase2Submit() http.HandlerFunc {
 	}
 }
 
+// yearGuessPoints calculates the points of a guess of the exact year of an
+// event. A correct guess gets 8 points, whereas a close guess gets partial
+// points (the closer the guess, the more points).
+// (Tested in handler_test.go)
+func yearGuessPoints(correctYear int, guess int) int {
+	if guess == correctYear {
+		return phase2Points
+	}
+
+	// Get absolute value of difference between user's guess and correct year
+	difference := abs(correctYear - guess)
+
+	// Check if the user's guess is close and potentially add partial points
+	if difference < phase2PartialPoints {
+		return phase2PartialPoints - difference
+	}
+
+	return 0
+}
+
 // Phase2Review is a GET-method that is accessible to any user after Phase2.
 //
 // It displays a correction of the questions.
@@ -525,15 +532,6 @@ func (h *QuizHandler) Phase2Review() http.HandlerFunc {
 			return
 		}
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", noPermissionError)
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve quiz data from session
 		// 'ok' is false if quiz from session isn't convertible to quizData
 		// struct (so if quiz doesn't exist in session)
@@ -639,15 +637,6 @@ func (h *QuizHandler) Phase3() http.HandlerFunc {
 			return
 		}
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", noPermissionError)
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve quiz data from session
 		// 'ok' is false if quiz from session isn't convertible to quizData
 		// struct (so if quiz doesn't exist in session)
@@ -684,7 +673,9 @@ func (h *QuizHandler) Phase3() http.HandlerFunc {
 // Phase3Submit is a POST-method that is accessible to any user after Phase3.
 //
 // It calculates the points and redirects to Phase3Review. It also creates a
-// new score object which is stored in the database.
+// new score object which is stored in the database, updates the ratings of
+// the user and the difficulty of the topic and unlocks achievements, which
+// get announced in the summary.
 func (h *QuizHandler) Phase3Submit() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
@@ -695,10 +686,11 @@ func (h *QuizHandler) Phase3Submit() http.HandlerFunc {
 
 		// Retrieve quiz data from session
 		quiz, ok := h.sessions.Get(req.Context(), "quiz").(QuizData)
+		questions, isPhase3 := quiz.Questions.([]phase3Question)
 
 		// Validate the token of the quiz-data, so that the user can't go back
 		// in order to change his answers after having seen the review
-		msg := quiz.validate(ok, preparedPhase3, topicID)
+		msg := quiz.validate(ok && isPhase3, preparedPhase3, topicID)
 
 		// If 'msg' isn't empty, an error occurred
 		if msg != "" {
@@ -718,42 +710,80 @@ func (h *QuizHandler) Phase3Submit() http.HandlerFunc {
 		}
 		guesses := req.Form["guesses"]
 
+		// Validate the guesses, which have to be the indexes of all events in
+		// phase 3, each exactly once, since the points and achievements
+		// depend on them
+		phase3Amount := min(quiz.Topic.EventsCount, phase3Questions) // amount of events in phase 3
+		guessesInt, ok := phase3Orders(guesses, questions[:min(len(questions), phase3Amount)])
+		if !ok || len(guessesInt) != phase3Amount {
+			h.sessions.Put(req.Context(), "flash_error", fmt.Sprintf("Ein Fehler ist aufgetreten in Phase %v "+
+				"des Quiz. Die Anzahl Antworten stimmt nicht mit der Anzahl Fragen überein.", 3))
+			http.Redirect(res, req, "/topics/"+topicIDstr+"/quiz/3", http.StatusSeeOther)
+			return
+		}
+
 		// Loop through user's guessing order to calculate points
-		// Value of the form input was the event's actual order, so by
+		// The actual order of each event was looked up in the session, so by
 		// comparing it to the user's order, we get the difference in position
 		// If a user's guess is 3 spots off, he gets 2 points (5-3); if user
 		// was spot on, he gets 5 points for that event
-		var guessesInt []int
-		for eventsOrder, guess := range guesses {
-			guessOrder, _ := strconv.Atoi(guess)
+		perfectGuesses := 0 // amount of events in phase 3 at the correct position
+		for eventsOrder, guessOrder := range guessesInt {
 			points := phase3Points - abs(eventsOrder-guessOrder)
 			if points > 0 {
 				quiz.Points += points
 				if points == phase3Points {
 					quiz.CorrectGuesses++
+					perfectGuesses++
 				}
 			}
-			guessesInt = append(guessesInt, guessOrder)
 		}
 
 		// Retrieve user from session
 		user := req.Context().Value("user").(x.User)
 
-		// Add score of quiz to database
-		if err := h.store.CreateScore(&x.Score{
+		// Add score of quiz to database and update ratings based on the
+		// percentage of possible points achieved
+		if err := rateQuiz(h.store, &x.Score{
 			TopicID: quiz.Topic.TopicID,
 			UserID:  user.UserID,
 			Points:  quiz.Points,
-			Date:    time.Now(),
-		}); err != nil {
+			Date:    quiz.TimeStamp,
+		}, quizPotentialPoints(quiz.Topic.EventsCount)); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Pass quiz data and user's guesses to session
+		// Pass quiz data and user's guesses to session right away, so that the
+		// quiz can't be submitted again
 		h.sessions.Put(req.Context(), "quiz", quiz)
 		h.sessions.Put(req.Context(), "guesses", guessesInt)
 
+		// Add quiz to the user's activity of the day, which isn't essential
+		// for the quiz, so that a failure only gets logged
+		if err := h.store.AddActivity(&x.Activity{
+			UserID:  user.UserID,
+			Day:     quiz.TimeStamp,
+			Quizzes: 1,
+		}); err != nil {
+			log.Printf("error adding quiz to activity of user %v: %v", user.UserID, err)
+		}
+
+		// Unlock achievements, whose names get passed to the session in order
+		// to be announced in the summary. A failure only gets logged as well
+		newAchievements, err := unlockAchievements(h.store, user.UserID, quiz.Topic.TopicID,
+			perfectGuesses == phase3Amount, quiz.TimeStamp)
+		if err != nil {
+			log.Printf("error unlocking achievements of user %v: %v", user.UserID, err)
+		}
+		if len(newAchievements) > 0 {
+			var names []string
+			for _, newAchievement := range newAchievements {
+				names = append(names, newAchievement.Name)
+			}
+			h.sessions.Put(req.Context(), "achievements", names)
+		}
+
 		// Redirect to review of phase 3
 		http.Redirect(res, req, "/topics/"+topicIDstr+"/quiz/3/review", http.StatusSeeOther)
 	}
@@ -785,15 +815,6 @@ func (h *QuizHandler) Phase3Review() http.HandlerFunc {
 			return
 		}
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", noPermissionError)
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve quiz data from session
 		// 'ok' is false if quiz from session isn't convertible to quizData
 		// struct (so if quiz doesn't exist in session)
@@ -830,7 +851,8 @@ func (h *QuizHandler) Phase3Review() http.HandlerFunc {
 
 // Summary is a GET-method that is accessible to any user after Phase3Review.
 //
-// It summarizes the quiz completed.
+// It summarizes the quiz completed and announces achievements, which were
+// unlocked by it.
 func (h *QuizHandler) Summary() http.HandlerFunc {
 	// Data to pass to HTML-templates
 	type data struct {
@@ -867,31 +889,34 @@ func (h *QuizHandler) Summary() http.HandlerFunc {
 			return
 		}
 
-		// Execute SQL statement to get scores by topic
-		scores, err := h.store.GetScoresByTopic(topicID)
+		// Execute SQL statement to count the scores of the topic with lower
+		// points than the user, in order to find out how many users were
+		// worse than the current user
+		// Example: 50 scores, of which 20 scores have lower points than user
+		// => 'user is better than 40% of players' (20/50 * 100% = 40%)
+		lowerScoresCount, scoresCount, err := h.store.CountLowerScoresByTopic(topicID, quiz.Points)
 		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
-
-		// Compare user's points to all previous to find out how many users
-		// were worse than the current user (recursively)
-		// Example: 50 scores, of which 20 scores have lower points than user
-		// => 'user is better than 40% of players' (20/50 * 100% = 40%)
-		potentialIndexOfScore := binarySearchForPoints(quiz.Points, scores, 0, len(scores))
-		amountOfLowerScores := len(scores) - potentialIndexOfScore
-		averageComparison := amountOfLowerScores * 100 / len(scores)
+		averageComparison := 0
+		if scoresCount > 0 {
+			averageComparison = lowerScoresCount * 100 / scoresCount
+		}
 
 		phase3Amount := min(quiz.Topic.EventsCount, phase3Questions) // amount of events in phase 3
-		// Amount of possible points, if every guess was correct
-		potentialPoints := phase1Questions*phase1Points + phase2Questions*phase2Points + phase3Amount*phase3Points
+
+		// Announce achievements unlocked by the quiz with a flash message
+		if names, ok := h.sessions.Pop(req.Context(), "achievements").([]string); ok && len(names) > 0 {
+			h.sessions.Put(req.Context(), "flash_success", achievementsFlash(names))
+		}
 
 		// Execute HTML-templates with data
 		if err = quizSummaryTemplate.Execute(res, data{
 			SessionData:       GetSessionData(h.sessions, req.Context()),
 			Quiz:              quiz,
 			QuestionsCount:    phase1Questions + phase2Questions + phase3Amount,
-			PotentialPoints:   potentialPoints,
+			PotentialPoints:   quizPotentialPoints(quiz.Topic.EventsCount),
 			AverageComparison: averageComparison,
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
@@ -900,6 +925,14 @@ func (h *QuizHandler) Summary() http.HandlerFunc {
 	}
 }
 
+// quizPotentialPoints calculates the amount of possible points of a quiz, if
+// every guess was correct, depending on the amount of events of the topic.
+func quizPotentialPoints(eventsCount int) int {
+	phase3Amount := min(eventsCount, phase3Questions) // amount of events in phase 3
+
+	return phase1Questions*phase1Points + phase2Questions*phase2Points + phase3Amount*phase3Points
+}
+
 // validate validates the correct playing order of a quiz by first checking for
 // a valid quiz-data struct and then comparing the phase, topic and time-
 // stamp of the quiz-data in the session with the URL and current time
@@ -945,13 +978,33 @@ func (quiz QuizData) validate(ok bool, step int, topicID int) string {
 	return ""
 }
 
+// eventDetails contains the optional description, source and image of an
+// event, which get shown in the reviews, so that users learn the context of an
+// event after having answered a question about it.
+type eventDetails struct {
+	Description string
+	Source      string
+	Image       string
+}
+
+// newEventDetails extracts the optional details of an event.
+func newEventDetails(event x.Event) eventDetails {
+	return eventDetails{
+		Description: event.Description,
+		Source:      event.Source,
+		Image:       event.Image,
+	}
+}
+
 // phase1Question represents 1 of the 4 multiple-choice questions of phase 1.
 // It contains name of event, year of event and 2 random years randomly mixed
 // in with the correct year.
 type phase1Question struct {
-	EventName string // name of event
-	EventYear int    // year of event
-	Choices   []int  // choices in random order (including correct year)
+	EventID      int          // ID of event, in order to suggest a correction in the review
+	EventName    string       // name of event
+	EventYear    int          // year of event
+	EventDetails eventDetails // optional context of event, shown in the review
+	Choices      []int        // choices in random order (including correct year)
 
 	UserGuess int // only relevant for review of phase 1
 }
@@ -997,9 +1050,11 @@ func createPhase1Questions(events []x.Event) []phase1Question {
 
 		// Add values to struct and add struct to array
 		questions = append(questions, phase1Question{
-			EventName: event.Name,
-			EventYear: event.Year,
-			Choices:   years,
+			EventID:      event.EventID,
+			EventName:    event.Name,
+			EventYear:    event.Year,
+			EventDetails: newEventDetails(event),
+			Choices:      years,
 		})
 	}
 
@@ -1009,8 +1064,10 @@ func createPhase1Questions(events []x.Event) []phase1Question {
 // phase2Question represents 1 of the 4 questions of phase 2. It contains name
 // of event and year of event.
 type phase2Question struct {
-	EventName string // name of event
-	EventYear int    // year of event
+	EventID      int          // ID of event, in order to suggest a correction in the review
+	EventName    string       // name of event
+	EventYear    int          // year of event
+	EventDetails eventDetails // optional context of event, shown in the review
 
 	UserGuess int
 }
@@ -1023,8 +1080,10 @@ func createPhase2Questions(events []x.Event) []phase2Question {
 	// Loop through events 3-7 and turn them into questions
 	for _, event := range events[phase1Questions:(phase2Questions + phase1Questions)] { // events[4:8] -> 4-7
 		questions = append(questions, phase2Question{
-			EventName: event.Name,
-			EventYear: event.Year,
+			EventID:      event.EventID,
+			EventName:    event.Name,
+			EventYear:    event.Year,
+			EventDetails: newEventDetails(event),
 		})
 	}
 
@@ -1044,13 +1103,21 @@ type phase3Question struct {
 // createPhase3Questions generates a phase3Question struct for all events of
 // the topic.
 func createPhase3Questions(events []x.Event) ([]phase3Question, []x.Event) {
+	return createTimelineQuestions(events, phase3Questions)
+}
+
+// createTimelineQuestions generates a phase3Question struct for a certain
+// amount of random events, to be put in chronological order. The first
+// *amount* events of the returned array of events are sorted by date, while
+// the questions are shuffled.
+func createTimelineQuestions(events []x.Event, amount int) ([]phase3Question, []x.Event) {
 	var questions []phase3Question
 
 	// Shuffle array of questions, in order to get random events for the user
 	// put in the correct order
-	// If amount of events is smaller than amount of questions of phase 3, we
-	// utilize all the events instead, so no need to shuffle
-	if len(events) > phase3Questions {
+	// If amount of events is smaller than amount of questions, we utilize all
+	// the events instead, so no need to shuffle
+	if len(events) > amount {
 		rand.Seed(time.Now().UnixNano()) // generate new seed to base RNG off of
 		rand.Shuffle(len(events), func(n1, n2 int) {
 			events[n1], events[n2] = events[n2], events[n1]
@@ -1059,7 +1126,7 @@ func createPhase3Questions(events []x.Event) ([]phase3Question, []x.Event) {
 
 	// Sort array of events by date, in order to add 'order' value to the
 	// first *amount* questions
-	amount := min(phase3Questions, len(events))
+	amount = min(amount, len(events))
 	sort.Slice(events[:amount], func(n1, n2 int) bool {
 		return events[n1].Date.Before(events[n2].Date)
 	})
@@ -1082,38 +1149,21 @@ func createPhase3Questions(events []x.Event) ([]phase3Question, []x.Event) {
 	return questions, events
 }
 
-// binarySearchForPoints searches for the index, where the user's score
-// would be if all scores of this topic were sorted by points in descending
-// order.
-// It looks for this recursively, in a binary-search way, since this is the
-// most efficient way of looking for this index.
-// Time complexity: O(log n) using binary-search instead of O(n) using iteration;
-// 50000 scores take 224 iterations max instead of 50000 max (=> scalable).
+// phase3Orders converts the user's guesses of phase 3, which are the indexes
+// of the questions in the order the user put them, into the actual orders of
+// the corresponding events. The orders are looked up in the questions of the
+// session, so that a user can't fake them. 'ok' is false if a guess isn't a
+// valid index or if it occurs more than once.
 // (Tested in handler_test.go)
-func binarySearchForPoints(points int, scores []x.Score, floor int, ceil int) int {
-	if len(scores) == 0 {
-		return 0
-	}
-
-	// Get the points of the score in the middle of the 'floor'- and 'ceiling'-
-	// pointers
-	// Example: len(scores) = 40, floor = 10, ceil = 25 => middle = 17
-	middle := (floor + ceil) / 2
-	middlePoints := scores[middle].Points
-
-	// Base case for recursive function
-	if ceil-floor <= 1 || points == middlePoints {
-		if points < middlePoints {
-			return (floor+ceil)/2 + 1
+func phase3Orders(guesses []string, questions []phase3Question) (orders []int, ok bool) {
+	used := make(map[int]bool, len(guesses))
+	for _, guess := range guesses {
+		index, err := strconv.Atoi(guess)
+		if err != nil || index < 0 || index >= len(questions) || used[index] {
+			return nil, false
 		}
-		return (floor + ceil) / 2
-	}
-
-	if points < middlePoints {
-		// Binary-search with upper half (lower points)
-		return binarySearchForPoints(points, scores, middle+1, ceil) // example: scores 18 - 25
-	} else {
-		// Binary-search with lower half (higher points)
-		return binarySearchForPoints(points, scores, floor, middle-1) // example: scores 10 - 16
+		used[index] = true
+		orders = append(orders, questions[index].Order)
 	}
+	return orders, true
 }
@@ -0,0 +1,117 @@
+// Skill ratings of users, similar to the Elo rating system. Raw points aren't
+// comparable across topics with different amounts of events, which is why
+// every finished quiz is treated as a match between the user and the topic:
+// the result is the percentage of possible points the user achieved, whereas
+// the topic's difficulty acts as the rating of the opponent. Achieving more
+// than expected raises the user's rating and lowers the topic's difficulty
+// and vice versa.
+
+package web
+
+import (
+	"math"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	ratingInitial    = 1000 // rating of a user or difficulty of a topic without any quiz
+	ratingFactor     = 32   // maximum change of a user's rating after a quiz
+	difficultyFactor = 8    // maximum change of a topic's difficulty after a quiz
+)
+
+// rateQuiz creates the score of a finished quiz, in which the user achieved
+// 'points' out of 'potentialPoints', and updates the overall rating of the
+// user, the user's rating in the topic and the difficulty of the topic at
+// once.
+func rateQuiz(store x.Store, score *x.Score, potentialPoints int) error {
+
+	result := quizResult(score.Points, potentialPoints)
+
+	// Execute SQL statements to store the score and the new ratings
+	return store.CreateRatedScore(score, func(rating int, topicRating int, difficulty int) []x.Rating {
+		return []x.Rating{
+			{UserID: score.UserID, Rating: newRating(rating, difficulty, result, ratingFactor),
+				Date: score.Date},
+			{UserID: score.UserID, TopicID: score.TopicID,
+				Rating: newRating(topicRating, difficulty, result, ratingFactor), Date: score.Date},
+			{TopicID: score.TopicID, Rating: newRating(difficulty, rating, 1-result, difficultyFactor),
+				Date: score.Date},
+		}
+	})
+}
+
+// quizResult converts the points of a quiz into a result between 0 and 1,
+// being the percentage of possible points achieved.
+// (Tested in handler_test.go)
+func quizResult(points int, potentialPoints int) float64 {
+	if potentialPoints <= 0 {
+		return 0
+	}
+
+	return math.Max(0, math.Min(1, float64(points)/float64(potentialPoints)))
+}
+
+// expectedResult calculates the result (between 0 and 1), which is to be
+// expected against an opponent, e.g. 0.5 for equal ratings or ~0.76 if the
+// rating is 200 higher than the opponent's.
+// (Tested in handler_test.go)
+func expectedResult(rating int, opponent int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponent-rating)/400))
+}
+
+// newRating calculates the rating after a result against an opponent, whereas
+// a rating of 0 stands for no rating yet. The 'factor' is the maximum change.
+// (Tested in handler_test.go)
+func newRating(rating int, opponent int, result float64, factor int) int {
+	if rating == 0 {
+		rating = ratingInitial
+	}
+	if opponent == 0 {
+		opponent = ratingInitial
+	}
+
+	change := float64(factor) * (result - expectedResult(rating, opponent))
+
+	return rating + int(math.Round(change))
+}
+
+// currentRatings splits the history of a user's ratings, sorted by date, into
+// the overall ratings and the latest rating in every topic, in the order the
+// topics were first played.
+// (Tested in handler_test.go)
+func currentRatings(ratings []x.Rating) ([]x.Rating, []x.Rating) {
+	var overall, topics []x.Rating
+
+	indexes := make(map[int]int) // index in 'topics' of every topic
+	for _, rating := range ratings {
+		if rating.TopicID == 0 {
+			overall = append(overall, rating)
+			continue
+		}
+		if i, ok := indexes[rating.TopicID]; ok {
+			topics[i] = rating
+			continue
+		}
+		indexes[rating.TopicID] = len(topics)
+		topics = append(topics, rating)
+	}
+
+	return overall, topics
+}
+
+// ratingChart converts the history of a user's overall ratings into a line
+// chart.
+// (Tested in handler_test.go)
+func ratingChart(ratings []x.Rating, locale string) chart {
+
+	dataset := chartDataset{Label: translate(locale, "Wertung"), Values: []interface{}{}}
+	c := chart{Type: "line", Labels: []string{}}
+	for _, rating := range ratings {
+		c.Labels = append(c.Labels, formatDate(locale, rating.Date))
+		dataset.Values = append(dataset.Values, rating.Rating)
+	}
+	c.Datasets = []chartDataset{dataset}
+
+	return c
+}
@@ -0,0 +1,200 @@
+// Responsible for recording revisions of events and topics with every change,
+// and for comparing them field by field, in order to display the history of
+// an event or topic and to be able to revert to a previous revision.
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// revisionField is a field of an event or topic to be compared between
+// revisions.
+type revisionField struct {
+	Label string
+	Value string
+}
+
+// revisionChange is a field of an event or topic, which changed from one
+// revision to the next.
+type revisionChange struct {
+	Label    string
+	Previous string
+	Current  string
+}
+
+// revisionView is a revision including its changes compared to the previous
+// revision, to be displayed in the history of an event or topic.
+type revisionView struct {
+	x.Revision
+	Changes []revisionChange
+	Current bool // whether it is the current revision, which can't be reverted to
+}
+
+// revise records a new revision of an event or topic after a change. If there
+// are no revisions of the target yet (e.g. because it was imported), the
+// target before the change gets recorded first, so that the original values
+// don't get lost. An error doesn't abort the change, since it has already
+// been executed, but gets logged.
+func revise(store x.RevisionStore, req *http.Request, targetType string, targetID int,
+	before interface{}, after interface{}) {
+
+	var userID int
+	if user, ok := req.Context().Value("user").(x.User); ok {
+		userID = user.UserID
+	}
+
+	if before != nil {
+		revisions, err := store.GetRevisionsByTarget(targetType, targetID)
+		if err != nil {
+			log.Printf("error getting revisions of %v %v: %v", targetType, targetID, err)
+			return
+		}
+		if len(revisions) == 0 {
+			createRevision(store, targetType, targetID, 0, before)
+		}
+	}
+
+	createRevision(store, targetType, targetID, userID, after)
+}
+
+// createRevision records a revision with a snapshot of the event or topic.
+// The user ID is 0 for revisions without a known author.
+func createRevision(store x.RevisionStore, targetType string, targetID int, userID int, target interface{}) {
+
+	snapshot, err := revisionSnapshot(target)
+	if err != nil {
+		log.Printf("error creating snapshot of %v %v: %v", targetType, targetID, err)
+		return
+	}
+
+	if err = store.CreateRevision(&x.Revision{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Snapshot:   snapshot,
+		Date:       time.Now(),
+	}); err != nil {
+		log.Printf("error recording revision of %v %v: %v", targetType, targetID, err)
+	}
+}
+
+// revisionSnapshot converts an event or topic to JSON, without the values
+// that aren't part of a revision (e.g. the events of a topic or
+// translations).
+func revisionSnapshot(target interface{}) (string, error) {
+
+	switch target := target.(type) {
+	case x.Event:
+		target.DeletedAt, target.Translations = nil, nil
+		snapshot, err := json.Marshal(target)
+		return string(snapshot), err
+	case x.Topic:
+		target.ParentID, target.DeletedAt, target.Events, target.ScoresCount, target.EventsCount = 0, nil, nil, 0, 0
+		target.Translations = nil
+		snapshot, err := json.Marshal(target)
+		return string(snapshot), err
+	}
+
+	return "", fmt.Errorf("unsupported type of revision %T", target)
+}
+
+// revisionFields converts the snapshot of a revision to the fields to be
+// compared between revisions.
+// (Tested in handler_test.go)
+func revisionFields(revision x.Revision) ([]revisionField, error) {
+
+	switch revision.TargetType {
+	case auditEvent:
+		var event x.Event
+		if err := json.Unmarshal([]byte(revision.Snapshot), &event); err != nil {
+			return nil, fmt.Errorf("error parsing revision %v: %w", revision.RevisionID, err)
+		}
+		return []revisionField{
+			{Label: "Ereignis", Value: event.Name},
+			{Label: "Jahr", Value: strconv.Itoa(event.Year)},
+			{Label: "Datum", Value: event.Date.Format("02.01.2006")},
+			{Label: "Beschreibung", Value: event.Description},
+			{Label: "Quelle", Value: event.Source},
+			{Label: "Bild", Value: event.Image},
+		}, nil
+	case auditTopic:
+		var topic x.Topic
+		if err := json.Unmarshal([]byte(revision.Snapshot), &topic); err != nil {
+			return nil, fmt.Errorf("error parsing revision %v: %w", revision.RevisionID, err)
+		}
+		return []revisionField{
+			{Label: "Thema", Value: topic.Name},
+			{Label: "Startjahr", Value: strconv.Itoa(topic.StartYear)},
+			{Label: "Endjahr", Value: strconv.Itoa(topic.EndYear)},
+			{Label: "Beschreibung", Value: topic.Description},
+			{Label: "Bild", Value: topic.Image},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type of revision %v", revision.TargetType)
+}
+
+// revisionDiff compares the fields of two revisions and returns the changed
+// fields. The fields of the previous revision may be nil (e.g. for the first
+// revision), in which case every non-empty field counts as changed.
+// (Tested in handler_test.go)
+func revisionDiff(previous []revisionField, current []revisionField) []revisionChange {
+	var changes []revisionChange
+
+	for i, field := range current {
+		var previousValue string
+		if i < len(previous) {
+			previousValue = previous[i].Value
+		}
+
+		if field.Value != previousValue {
+			changes = append(changes, revisionChange{
+				Label:    field.Label,
+				Previous: previousValue,
+				Current:  field.Value,
+			})
+		}
+	}
+
+	return changes
+}
+
+// revisionHistory compares every revision, sorted by date descending, with
+// the revision before it, in order to display the history of an event or
+// topic.
+// (Tested in handler_test.go)
+func revisionHistory(revisions []x.Revision) ([]revisionView, error) {
+
+	// Convert snapshots of all revisions to fields
+	fields := make([][]revisionField, len(revisions))
+	for i, revision := range revisions {
+		var err error
+		if fields[i], err = revisionFields(revision); err != nil {
+			return nil, err
+		}
+	}
+
+	history := make([]revisionView, len(revisions))
+	for i, revision := range revisions {
+		var previous []revisionField
+		if i+1 < len(revisions) {
+			previous = fields[i+1]
+		}
+
+		history[i] = revisionView{
+			Revision: revision,
+			Changes:  revisionDiff(previous, fields[i]),
+			Current:  i == 0,
+		}
+	}
+
+	return history, nil
+}
@@ -7,7 +7,9 @@ package web
 import (
 	"html/template"
 	"net/http"
+	neturl "net/url"
 	"strconv"
+	"time"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/gorilla/csrf"
@@ -19,10 +21,26 @@ var (
 	// Parsed HTML-templates to be executed in their respective HTTP-handler
 	// functions when needed
 	scoresListTemplate *template.Template
+
+	// Time windows of the leaderboard, besides all-time
+	leaderboardPeriods = []leaderboardPeriod{
+		{Key: "week", Label: "Letzte 7 Tage", Days: 7},
+		{Key: "month", Label: "Letzte 30 Tage", Days: 30},
+	}
+
+	// Modes of the leaderboard, whereas the first one is the default
+	leaderboardModes = []leaderboardMode{
+		{Key: x.LeaderboardBest, Label: "Bestes Resultat pro Thema"},
+		{Key: x.LeaderboardTotal, Label: "Gesamtpunktzahl aller Themen"},
+		{Key: x.LeaderboardAll, Label: "Alle Spielresultate"},
+		{Key: x.LeaderboardRating, Label: "Wertung"},
+	}
 )
 
 const (
 	showDefault = 10
+
+	topicLeaderboardSize = 5 // amount of scores on the leaderboard of a topic
 )
 
 // init gets initialized with the package.
@@ -34,7 +52,7 @@ func init() {
 		return
 	}
 
-	scoresListTemplate = template.Must(template.New("layout.html").
+	scoresListTemplate = template.Must(newTemplate().
 		Funcs(template.FuncMap{ // Add custom HTML-template-function to increment a number
 			"increment": func(num int) int {
 				return num + 1
@@ -54,10 +72,15 @@ type ScoreHandler struct {
 
 // List is a GET-method that is accessible to any user.
 //
-// It lists all scores and displays it as a leaderboard table, ranked by
-// points, with the ability to filter whilst typing in the search bar, as well
-// as to choose how many entries are shown at a time and navigate to the
-// previous or next page.
+// It lists the scores and displays them as a leaderboard table, ranked by
+// points. By default, only the best score of every user per topic is listed,
+// alternatively the total points of every user across topics, every score or
+// the current rating of every user, overall or in the topic (e.g.
+// '?mode=total'). The leaderboard can be filtered by topic, by school class
+// and by time window (e.g. '?topic=2&class=4a&period=week'), with the ability
+// to filter the entries of the current page whilst typing in the search bar,
+// as well as to choose how many entries are shown at a time and navigate to
+// the previous or next page.
 //
 // The leaderboard contains of a rank, name of user, name of topic, date and
 // points of a score.
@@ -69,6 +92,12 @@ func (h *ScoreHandler) List() http.HandlerFunc {
 		CSRF template.HTML
 
 		Leaderboard []leaderboardRow
+		Filter      leaderboardFilterForm
+		Query       template.URL // URL query of the filter, for navigating between pages
+		Topics      []topicNode  // topics to filter by
+		Classes     []string     // school classes to filter by
+		Periods     []leaderboardPeriod
+		Modes       []leaderboardMode
 
 		Show     int // amount of scores shown
 		ShowFrom int // first score's rank
@@ -83,47 +112,69 @@ func (h *ScoreHandler) List() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if a user is logged in
-		userInf := req.Context().Value("user")
-		if userInf == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", "Unzureichende Berechtigung. "+
-				"Sie müssen als Benutzer eingeloggt sein, um das Leaderboard zu betrachten.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+		// Retrieve values from URL query for filtering the leaderboard
+		form, filter := parseLeaderboardFilter(req.URL.Query(), time.Now())
+
+		// Execute SQL statement to get amount of scores matching the filter
+		scoresCount, err := h.store.CountLeaderboard(filter)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Execute SQL statement to get scores
-		scores, err := h.store.GetScores()
+		// Execute SQL statements to get topics and classes to filter by
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		classes, err := h.store.GetClasses()
 		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Retrieve values from URL query for filtering the leaderboard by
-		// indicating the amount of scores to be shown and with which offset
+		// Retrieve values from URL query for indicating the amount of scores
+		// to be shown and with which offset
 		showFilter := req.URL.Query().Get("show")
 		pageFilter := req.URL.Query().Get("page")
-		show, page := inspectFilters(showFilter, pageFilter, len(scores))
+		show, page := inspectFilters(showFilter, pageFilter, scoresCount)
+
+		// Execute SQL statement to get the ranked scores of the current page
+		// Example: page = 3, show = 15 => ranks 31-45
+		filter.Limit = show
+		filter.Offset = show * (page - 1)
+		scores, err := h.store.GetLeaderboard(filter)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		// Create table of scores for the current page & show size
-		// Example: page = 3, show = 15 => scores[30:45] (ranks 31-45)
-		// However, if len(scores) = 33 => scores[30:33] (ranks 31-33)
-		leaderboard := createLeaderboardRows(scores, show, page)
+		// Create table of scores for the current page
+		leaderboard := createLeaderboardRows(scores)
 
 		// Page numbers to be shown below the leaderboard in order to navigate
 		// to different pages
-		pages := createPages(show, page, len(scores))
+		pages := createPages(show, page, scoresCount)
+		if len(pages) == 0 {
+			pages = []int{1}
+		}
 
 		// Execute HTML-templates with data
 		if err = scoresListTemplate.Execute(res, data{
 			SessionData:  GetSessionData(h.sessions, req.Context()),
 			CSRF:         csrf.TemplateField(req),
 			Leaderboard:  leaderboard,
+			Filter:       form,
+			Query:        template.URL(form.query()),
+			Topics:       topicTree(topics),
+			Classes:      classes,
+			Periods:      leaderboardPeriods,
+			Modes:        leaderboardModes,
 			Show:         show,
-			ShowFrom:     leaderboard[0].Rank,
-			ShowTo:       leaderboard[len(leaderboard)-1].Rank,
-			ShowOf:       len(scores),
+			ShowFrom:     min(filter.Offset+1, scoresCount),
+			ShowTo:       filter.Offset + len(leaderboard),
+			ShowOf:       scoresCount,
 			Page:         page,
 			Pages:        pages,
 			PagePrevious: page != pages[0],
@@ -140,25 +191,100 @@ type leaderboardRow struct {
 	Rank      int
 	UserName  string
 	TopicName string
-	Date      string
+	Date      time.Time
 	Points    int
 }
 
-// createLeaderboardRows generates all rows of the leaderboard. 'show'
-// indicates the amount of scores (scores[n:n+show]) and 'page' indicates
-// the offset of the range (start=show*(page-1) -> scores[start:start+show]).
-func createLeaderboardRows(scores []x.Score, show int, page int) []leaderboardRow {
+// leaderboardPeriod is a time window of the leaderboard, which ends now.
+type leaderboardPeriod struct {
+	Key   string // value of the URL query 'period'
+	Label string
+	Days  int
+}
+
+// leaderboardMode is a mode of the leaderboard, which determines what a row of
+// the leaderboard is.
+type leaderboardMode struct {
+	Key   string // value of the URL query 'mode'
+	Label string
+}
+
+// leaderboardFilterForm holds the values of the filter of the leaderboard, as
+// chosen in the form.
+type leaderboardFilterForm struct {
+	Mode    string // key of a mode, empty for the default mode
+	TopicID int
+	Class   string
+	Period  string // key of a time window, empty for all-time
+}
+
+// query converts the filter back to a URL query.
+func (form leaderboardFilterForm) query() string {
+
+	values := neturl.Values{}
+	if form.Mode != "" {
+		values.Set("mode", form.Mode)
+	}
+	if form.TopicID != 0 {
+		values.Set("topic", strconv.Itoa(form.TopicID))
+	}
+	if form.Class != "" {
+		values.Set("class", form.Class)
+	}
+	if form.Period != "" {
+		values.Set("period", form.Period)
+	}
+
+	return values.Encode()
+}
+
+// parseLeaderboardFilter converts the URL query into a filter of the
+// leaderboard, ignoring invalid values and falling back to the default mode.
+// The time window ends at 'now'.
+// (Tested in handler_test.go)
+func parseLeaderboardFilter(query neturl.Values, now time.Time) (leaderboardFilterForm, x.LeaderboardFilter) {
+	var form leaderboardFilterForm
+	var filter x.LeaderboardFilter
+
+	// The first mode is the default, thus it isn't part of the URL query
+	filter.Mode = leaderboardModes[0].Key
+	for _, mode := range leaderboardModes[1:] {
+		if query.Get("mode") == mode.Key {
+			form.Mode = mode.Key
+			filter.Mode = mode.Key
+		}
+	}
+
+	if topicID, err := strconv.Atoi(query.Get("topic")); err == nil && topicID > 0 {
+		form.TopicID = topicID
+		filter.TopicID = topicID
+	}
+
+	form.Class = formatInput(query.Get("class"))
+	filter.Class = form.Class
+
+	for _, period := range leaderboardPeriods {
+		if query.Get("period") == period.Key {
+			form.Period = period.Key
+			filter.Since = now.AddDate(0, 0, -period.Days)
+		}
+	}
+
+	return form, filter
+}
+
+// createLeaderboardRows generates the rows of the leaderboard from a page of
+// ranked scores.
+func createLeaderboardRows(scores []x.Score) []leaderboardRow {
 	var leaderboard []leaderboardRow
 
-	start := show * (page - 1)
-	end := show * page
-	for i := start; i < len(scores) && i < end; i++ {
+	for _, score := range scores {
 		leaderboard = append(leaderboard, leaderboardRow{
-			Rank:      i + 1,
-			UserName:  scores[i].UserName,
-			TopicName: scores[i].TopicName,
-			Date:      scores[i].Date.Format("02.01.06"), // date formatted as 'dd.mm.yy'
-			Points:    scores[i].Points,
+			Rank:      score.Rank,
+			UserName:  score.UserName,
+			TopicName: score.TopicName,
+			Date:      score.Date,
+			Points:    score.Points,
 		})
 	}
 
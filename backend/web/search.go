@@ -0,0 +1,335 @@
+// Responsible for the full-text search across topics, events and pages of the
+// navigation bar.
+//
+// The search-query and all searchable texts get normalized the same way as
+// names of events when detecting duplicates (case, umlauts, accents,
+// punctuation). Every word of the query must match a word of a result, either
+// exactly, as a prefix, as a part of a compound word (e.g. "krieg" in
+// "Weltkrieg") or with a few typos, depending on the length of the word. A
+// range of years (e.g. "1914-1918") or a single year restricts the results to
+// topics and events within it.
+
+package web
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	searchLimit = 50 // maximum amount of search results
+
+	// Types of search results
+	searchTopic = "topic"
+	searchEvent = "event"
+	searchPage  = "page"
+)
+
+var (
+	// searchYearRange matches a range of years in a search-query
+	// (e.g. "1914-1918", "1914 bis 1918")
+	searchYearRange = regexp.MustCompile(`\b(\d{1,4})\s*(?:-|–|bis)\s*(\d{1,4})\b`)
+
+	// searchYear matches a single year in a search-query (e.g. "1989")
+	searchYear = regexp.MustCompile(`\b(\d{3,4})\b`)
+
+	// searchStopWords are words, which are too common to be searched for
+	searchStopWords = map[string]bool{
+		"der": true, "die": true, "das": true, "den": true, "dem": true, "des": true, "ein": true, "eine": true,
+		"und": true, "von": true, "im": true, "in": true, "am": true, "zu": true, "bei": true, "bis": true,
+	}
+
+	// searchPages are the titles of the pages, which keywords of the search
+	// can lead to
+	searchPages = map[string]string{
+		topicsURL:    "Themen",
+		scoresURL:    "Bestenliste",
+		profileURL:   "Profil",
+		loginURL:     "Anmelden",
+		registerURL:  "Registrieren",
+		usersURL:     "Benutzer verwalten",
+		challengeURL: "Quiz des Tages",
+	}
+)
+
+// searchQuery is a parsed search-query.
+type searchQuery struct {
+	Terms []string // normalized words, excluding the years
+	From  int      // start of the range of years, 0 if none
+	To    int      // end of the range of years, 0 if none
+}
+
+// searchResult is a topic, event or page matching a search-query.
+type searchResult struct {
+	Type     string  `json:"type"`
+	Title    string  `json:"title"`
+	Subtitle string  `json:"subtitle"`
+	URL      string  `json:"url"`
+	Score    float64 `json:"score"`
+
+	year int // year of the topic or event, in order to sort equally ranked results
+}
+
+// searchField is a searchable text of a topic or event, whose matches are
+// weighted (e.g. a matching name counts more than a matching description).
+type searchField struct {
+	words  []string
+	weight float64
+}
+
+// parseSearchQuery parses a search-query into its normalized words and an
+// optional range of years.
+// (Tested in handler_test.go)
+func parseSearchQuery(raw string) searchQuery {
+	var query searchQuery
+
+	// Extract a range of years or a single year
+	if match := searchYearRange.FindStringSubmatch(raw); match != nil {
+		query.From, _ = strconv.Atoi(match[1])
+		query.To, _ = strconv.Atoi(match[2])
+		if query.From > query.To {
+			query.From, query.To = query.To, query.From
+		}
+		raw = strings.Replace(raw, match[0], " ", 1)
+	} else if match := searchYear.FindStringSubmatch(raw); match != nil {
+		query.From, _ = strconv.Atoi(match[1])
+		query.To = query.From
+		raw = strings.Replace(raw, match[0], " ", 1)
+	}
+
+	for _, term := range strings.Fields(normalizeName(raw)) {
+		if !searchStopWords[term] {
+			query.Terms = append(query.Terms, term)
+		}
+	}
+
+	return query
+}
+
+// search finds and ranks all topics, events and pages matching a
+// search-query, best matches first. Only pages accessible to the user get
+// found.
+// (Tested in handler_test.go)
+func search(query searchQuery, topics []x.Topic, events []x.Event, pages map[string]bool) []searchResult {
+	var results []searchResult
+
+	hasRange := query.From != 0 || query.To != 0
+	if len(query.Terms) == 0 && !hasRange {
+		return results
+	}
+
+	// Search topics, which match a range of years if their time span
+	// overlaps with it
+	topicNames := map[int]string{}
+	for _, topic := range topics {
+		topicNames[topic.TopicID] = topic.Name
+		if hasRange && (topic.EndYear < query.From || topic.StartYear > query.To) {
+			continue
+		}
+		score := searchScore(query.Terms, []searchField{
+			{words: strings.Fields(normalizeName(topic.Name)), weight: 3},
+			{words: strings.Fields(normalizeName(topic.Description)), weight: 1},
+		})
+		if score == 0 {
+			continue
+		}
+		results = append(results, searchResult{
+			Type:     searchTopic,
+			Title:    topic.Name,
+			Subtitle: fmt.Sprintf("%v - %v", topic.StartYear, topic.EndYear),
+			URL:      "/topics/" + strconv.Itoa(topic.TopicID),
+			Score:    score,
+			year:     topic.StartYear,
+		})
+	}
+
+	// Search events
+	for _, event := range events {
+		if hasRange && (event.Year < query.From || event.Year > query.To) {
+			continue
+		}
+		score := searchScore(query.Terms, []searchField{
+			{words: strings.Fields(normalizeName(event.Name)), weight: 2},
+		})
+		if score == 0 {
+			continue
+		}
+		results = append(results, searchResult{
+			Type:     searchEvent,
+			Title:    event.Name,
+			Subtitle: fmt.Sprintf("%v, %v", event.Year, topicNames[event.TopicID]),
+			URL:      fmt.Sprintf("/topics/%v", event.TopicID),
+			Score:    score,
+			year:     event.Year,
+		})
+	}
+
+	// Search pages by their keywords, unless searching for years
+	if !hasRange {
+		results = append(results, searchPageResults(query.Terms, pages)...)
+	}
+
+	// Rank results by their score, then chronologically
+	sort.SliceStable(results, func(n1, n2 int) bool {
+		if results[n1].Score != results[n2].Score {
+			return results[n1].Score > results[n2].Score
+		}
+		if results[n1].year != results[n2].year {
+			return results[n1].year < results[n2].year
+		}
+		return results[n1].Title < results[n2].Title
+	})
+
+	if len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	return results
+}
+
+// localizeSearchResults translates the titles of pages among search results
+// to a locale, since they're the only results not consisting of content.
+func localizeSearchResults(locale string, results []searchResult) []searchResult {
+
+	for i, result := range results {
+		if result.Type == searchPage {
+			results[i].Title = translate(locale, result.Title)
+		}
+	}
+
+	return results
+}
+
+// searchPagesOf determines the pages among the search results, which are
+// accessible to a user, since pages requiring a login or the rights of an
+// admin would only deny access. 'loggedIn' is false for visitors.
+// (Tested in handler_test.go)
+func searchPagesOf(user x.User, loggedIn bool) map[string]bool {
+	pages := map[string]bool{}
+
+	for url := range searchPages {
+		switch url {
+		case scoresURL, challengeURL, profileURL:
+			if !loggedIn {
+				continue
+			}
+		case usersURL:
+			if !loggedIn || !user.Admin || !user.Verified {
+				continue
+			}
+		}
+		pages[url] = true
+	}
+
+	return pages
+}
+
+// searchPageResults finds the accessible pages, whose keywords match any of
+// the words of a search-query, allowing for a typo in longer words.
+func searchPageResults(terms []string, pages map[string]bool) []searchResult {
+	var results []searchResult
+
+	found := map[string]bool{}
+	for _, term := range terms {
+		for keyword, url := range searchKeywords {
+			if found[url] || !pages[url] {
+				continue
+			}
+			keyword = normalizeName(keyword)
+			if keyword != term && (searchTypos(term) == 0 ||
+				levenshtein([]rune(keyword), []rune(term)) > 1) {
+				continue
+			}
+			found[url] = true
+			results = append(results, searchResult{
+				Type:  searchPage,
+				Title: searchPages[url],
+				URL:   url,
+				Score: 1,
+			})
+		}
+	}
+
+	return results
+}
+
+// searchScore calculates the score of a topic or event, which is the sum of
+// the best weighted match of every word of a search-query. A score of 0 means
+// that not all words match. Without any words (e.g. when only searching for
+// years) everything matches equally.
+func searchScore(terms []string, fields []searchField) float64 {
+
+	if len(terms) == 0 {
+		return 1
+	}
+
+	var score float64
+	for _, term := range terms {
+		var best float64
+		for _, field := range fields {
+			if match := field.weight * matchTerm(term, field.words); match > best {
+				best = match
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+
+	return score
+}
+
+// matchTerm calculates how well a word of a search-query matches the best of
+// some words, between 0 (no match) and 1 (exact match).
+// (Tested in handler_test.go)
+func matchTerm(term string, words []string) float64 {
+
+	var best float64
+	termRunes := []rune(term)
+	typos := searchTypos(term)
+
+	for _, word := range words {
+		var match float64
+		wordRunes := []rune(word)
+
+		switch {
+		case word == term:
+			match = 1
+		case strings.HasPrefix(word, term):
+			match = 0.8
+		case len(termRunes) >= 4 && strings.Contains(word, term):
+			match = 0.6
+		case typos > 0 && levenshtein(termRunes, wordRunes) <= typos:
+			match = 0.5
+		case typos > 0 && len(wordRunes) > len(termRunes) &&
+			levenshtein(termRunes, wordRunes[:len(termRunes)]) <= typos:
+			match = 0.4 // prefix with typos, e.g. when the word isn't typed in completely
+		}
+
+		if match > best {
+			best = match
+		}
+	}
+
+	return best
+}
+
+// searchTypos returns the amount of typos allowed in a word of a
+// search-query, depending on its length.
+func searchTypos(term string) int {
+
+	switch length := len([]rune(term)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
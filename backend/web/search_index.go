@@ -0,0 +1,221 @@
+// Responsible for the in-memory index of the autocompletion of the search,
+// which suggests topics, events and pages while typing in the navigation bar.
+//
+// The index consists of the sorted, normalized words of all topics, events and
+// keywords of pages, in order to find all words starting with a prefix by a
+// binary search. It gets built lazily on the first request and rebuilt on the
+// next request after having been marked as outdated. Therefore every handler,
+// which changes a topic or event, must call invalidate explicitly.
+
+package web
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	suggestionsLimit = 8 // maximum amount of suggestions of the autocompletion
+)
+
+var (
+	// suggestIndex is the index of the autocompletion, shared by all requests
+	suggestIndex = &searchIndex{}
+)
+
+// searchIndex is an in-memory index of topics, events and pages, which can be
+// searched by the prefixes of words.
+type searchIndex struct {
+	mu         sync.RWMutex
+	built      bool // whether the index is up to date
+	generation int  // incremented with every change, in order to detect changes while building
+
+	items []searchResult
+	words []searchIndexWord // sorted by word
+}
+
+// searchIndexWord is a normalized word of an item of the index.
+type searchIndexWord struct {
+	word string
+	item int // index of the item
+}
+
+// invalidate marks the index as outdated, so that it gets rebuilt on the next
+// request.
+func (index *searchIndex) invalidate() {
+	index.mu.Lock()
+	index.built = false
+	index.generation++
+	index.mu.Unlock()
+}
+
+// suggest gets the suggestions for a search-query, whereas the index gets
+// rebuilt first, if it's outdated.
+func (index *searchIndex) suggest(store x.Store, query string, pages map[string]bool) ([]searchResult, error) {
+
+	index.mu.RLock()
+	built, generation := index.built, index.generation
+	index.mu.RUnlock()
+
+	if !built {
+		// Execute SQL statements to get topics and their events
+		topics, err := store.GetTopics()
+		if err != nil {
+			return nil, err
+		}
+		topicIDs := make([]int, len(topics))
+		for i, topic := range topics {
+			topicIDs[i] = topic.TopicID
+		}
+		events, err := store.GetEventsByTopics(topicIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		index.build(topics, events)
+
+		// Keep the index marked as outdated, if something changed meanwhile
+		index.mu.Lock()
+		if index.generation != generation {
+			index.built = false
+		}
+		index.mu.Unlock()
+	}
+
+	return index.lookup(query, pages), nil
+}
+
+// build replaces the content of the index with topics, events and pages.
+// (Tested in handler_test.go)
+func (index *searchIndex) build(topics []x.Topic, events []x.Event) {
+
+	var items []searchResult
+	var words []searchIndexWord
+	add := func(item searchResult, texts ...string) {
+		for _, text := range texts {
+			for _, word := range strings.Fields(normalizeName(text)) {
+				words = append(words, searchIndexWord{word: word, item: len(items)})
+			}
+		}
+		items = append(items, item)
+	}
+
+	// Add topics
+	topicNames := map[int]string{}
+	for _, topic := range topics {
+		topicNames[topic.TopicID] = topic.Name
+		add(searchResult{
+			Type:     searchTopic,
+			Title:    topic.Name,
+			Subtitle: fmt.Sprintf("%v - %v", topic.StartYear, topic.EndYear),
+			URL:      "/topics/" + strconv.Itoa(topic.TopicID),
+			year:     topic.StartYear,
+		}, topic.Name)
+	}
+
+	// Add events, including the name of their topic
+	for _, event := range events {
+		add(searchResult{
+			Type:     searchEvent,
+			Title:    event.Name,
+			Subtitle: fmt.Sprintf("%v, %v", event.Year, topicNames[event.TopicID]),
+			URL:      fmt.Sprintf("/topics/%v", event.TopicID),
+			year:     event.Year,
+		}, event.Name)
+	}
+
+	// Add pages by their keywords
+	keywords := map[string][]string{}
+	for keyword, url := range searchKeywords {
+		keywords[url] = append(keywords[url], keyword)
+	}
+	for url, title := range searchPages {
+		add(searchResult{
+			Type:  searchPage,
+			Title: title,
+			URL:   url,
+		}, append(keywords[url], title)...)
+	}
+
+	sort.SliceStable(words, func(n1, n2 int) bool {
+		return words[n1].word < words[n2].word
+	})
+
+	index.mu.Lock()
+	index.items, index.words, index.built = items, words, true
+	index.mu.Unlock()
+}
+
+// lookup finds the items, of which every word of a search-query is the prefix
+// of a word, leaving out pages that aren't accessible to the user. Items
+// matching whole words rank first, then topics before pages before events,
+// then shorter titles.
+// (Tested in handler_test.go)
+func (index *searchIndex) lookup(query string, pages map[string]bool) []searchResult {
+
+	terms := strings.Fields(normalizeName(query))
+	if len(terms) == 0 {
+		return []searchResult{}
+	}
+
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	// Count the matching words of a query per item, and whole words separately
+	matches := map[int]int{}
+	exact := map[int]int{}
+	for _, term := range terms {
+		matched := map[int]bool{}
+		start := sort.Search(len(index.words), func(i int) bool {
+			return index.words[i].word >= term
+		})
+		for i := start; i < len(index.words) && strings.HasPrefix(index.words[i].word, term); i++ {
+			item := index.words[i].item
+			if index.words[i].word == term && !matched[item] {
+				exact[item]++
+			}
+			if !matched[item] {
+				matched[item] = true
+				matches[item]++
+			}
+		}
+	}
+
+	suggestions := []searchResult{}
+	for item, count := range matches {
+		if count == len(terms) {
+			suggestion := index.items[item]
+			if suggestion.Type == searchPage && !pages[suggestion.URL] {
+				continue
+			}
+			suggestion.Score = float64(exact[item])
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	typeRanks := map[string]int{searchTopic: 0, searchPage: 1, searchEvent: 2}
+	sort.Slice(suggestions, func(n1, n2 int) bool {
+		s1, s2 := suggestions[n1], suggestions[n2]
+		if s1.Score != s2.Score {
+			return s1.Score > s2.Score
+		}
+		if typeRanks[s1.Type] != typeRanks[s2.Type] {
+			return typeRanks[s1.Type] < typeRanks[s2.Type]
+		}
+		if len(s1.Title) != len(s2.Title) {
+			return len(s1.Title) < len(s2.Title)
+		}
+		return s1.Title < s2.Title
+	})
+
+	if len(suggestions) > suggestionsLimit {
+		suggestions = suggestions[:suggestionsLimit]
+	}
+
+	return suggestions
+}
@@ -41,22 +41,27 @@ type SessionData struct {
 	Form                interface{}
 	User                x.User
 	LoggedIn            bool
+	Locale              string // negotiated language, in order to translate HTML-templates
 }
 
 // GetSessionData gets all the data from session.
 func GetSessionData(session *scs.SessionManager, ctx context.Context) SessionData {
 	var data SessionData
 
-	// Retrieve flash message from session
-	data.FlashMessageSuccess = session.PopString(ctx, "flash_success")
-	data.FlashMessageInfo = session.PopString(ctx, "flash_info")
-	data.FlashMessageError = session.PopString(ctx, "flash_error")
+	// Retrieve negotiated language
+	data.Locale = localeOf(ctx)
 
-	// Retrieve form from session
+	// Retrieve flash message from session, translated to the language
+	data.FlashMessageSuccess = translateText(data.Locale, session.PopString(ctx, "flash_success"))
+	data.FlashMessageInfo = translateText(data.Locale, session.PopString(ctx, "flash_info"))
+	data.FlashMessageError = translateText(data.Locale, session.PopString(ctx, "flash_error"))
+
+	// Retrieve form from session, with its errors translated to the language
 	data.Form = session.Pop(ctx, "form")
 	if data.Form == nil {
 		data.Form = map[string]string{}
 	}
+	translateFormErrors(data.Locale, data.Form)
 
 	// Retrieve user from session
 	userInf := ctx.Value("user")
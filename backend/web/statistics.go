@@ -0,0 +1,155 @@
+// Statistics of a user on the profile, which get aggregated from all scores of
+// the user: the best and average points, the amount of attempts and the
+// percentile among all players in every topic, as well as the history of the
+// user's points. They get drawn as charts by the script for every canvas with
+// a 'data-chart'-attribute.
+
+package web
+
+import (
+	"encoding/json"
+	"math"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// chart holds the data of a chart, which gets drawn by the script for every
+// canvas with a 'data-chart'-attribute.
+type chart struct {
+	Type     string         `json:"type"` // "line" or "bar"
+	Labels   []string       `json:"labels"`
+	Datasets []chartDataset `json:"datasets"`
+}
+
+// chartDataset is a series of values of a chart, with a value per label.
+type chartDataset struct {
+	Label  string        `json:"label"`
+	Values []interface{} `json:"values"` // nil for labels without a value
+}
+
+// encodeChart encodes a chart as JSON, in order to be passed to the script.
+func encodeChart(c chart) (string, error) {
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// scoresPerTopic represents 1 row of the chart with user's points for each
+// topic, including the statistics of the user in that topic.
+type scoresPerTopic struct {
+	TopicName         string
+	Points            int // best points
+	MaxPoints         int
+	Percentage        int // best points in percent of the max points
+	AveragePoints     float64
+	AveragePercentage int // average points in percent of the max points
+	Attempts          int
+	Percentile        int // percentage of other players with lower best points
+	PlayersCount      int
+}
+
+// topicStatistics combines all topics with the statistics of a user in the
+// topics played. The quiz of a parent topic includes the events of all its
+// sub-topics, which is why the max points depend on the events of those too.
+// (Tested in handler_test.go)
+func topicStatistics(topics []x.Topic, stats []x.TopicStats) []scoresPerTopic {
+	var statistics []scoresPerTopic
+
+	statsByTopic := make(map[int]x.TopicStats)
+	for _, s := range stats {
+		statsByTopic[s.TopicID] = s
+	}
+
+	eventsTotals := topicEventsTotals(topics)
+	for _, topic := range topics {
+		s := statsByTopic[topic.TopicID]
+		maxPoints := quizPotentialPoints(eventsTotals[topic.TopicID])
+
+		statistics = append(statistics, scoresPerTopic{
+			TopicName:         topic.Name,
+			Points:            s.BestPoints,
+			MaxPoints:         maxPoints,
+			Percentage:        percentage(float64(s.BestPoints), maxPoints),
+			AveragePoints:     math.Round(s.AveragePoints*10) / 10,
+			AveragePercentage: percentage(s.AveragePoints, maxPoints),
+			Attempts:          s.AttemptsCount,
+			Percentile:        s.Percentile,
+			PlayersCount:      s.PlayersCount,
+		})
+	}
+
+	return statistics
+}
+
+// percentage calculates the percentage of points out of max points, capped at
+// 100%.
+func percentage(points float64, maxPoints int) int {
+	if maxPoints <= 0 {
+		return 0
+	}
+
+	return min(int(math.Round(points*100/float64(maxPoints))), 100)
+}
+
+// averageBestChart compares the average and best points of a user in every
+// topic played as a bar chart, in percent of the max points, so that topics
+// with different amounts of events are comparable.
+// (Tested in handler_test.go)
+func averageBestChart(statistics []scoresPerTopic, locale string) chart {
+
+	average := chartDataset{Label: translate(locale, "Durchschnitt"), Values: []interface{}{}}
+	best := chartDataset{Label: translate(locale, "Bestes Resultat"), Values: []interface{}{}}
+	c := chart{Type: "bar", Labels: []string{}}
+	for _, s := range statistics {
+		if s.Attempts == 0 {
+			continue
+		}
+		c.Labels = append(c.Labels, s.TopicName)
+		average.Values = append(average.Values, s.AveragePercentage)
+		best.Values = append(best.Values, s.Percentage)
+	}
+	c.Datasets = []chartDataset{average, best}
+
+	return c
+}
+
+// scoreHistoryChart converts the scores of a user, sorted by date, into a line
+// chart with the history of the points in every topic. Every score gets its
+// own label, whereas every topic has its own dataset with values only at the
+// labels of its scores.
+// (Tested in handler_test.go)
+func scoreHistoryChart(scores []x.Score, locale string) chart {
+
+	c := chart{Type: "line", Labels: []string{}, Datasets: []chartDataset{}}
+
+	indexes := make(map[int]int) // index in 'c.Datasets' of every topic
+	for n, score := range scores {
+		c.Labels = append(c.Labels, formatDate(locale, score.Date))
+
+		i, ok := indexes[score.TopicID]
+		if !ok {
+			i = len(c.Datasets)
+			indexes[score.TopicID] = i
+			c.Datasets = append(c.Datasets, chartDataset{
+				Label:  score.TopicName,
+				Values: make([]interface{}, n, len(scores)),
+			})
+		}
+
+		// Add the points to the dataset of the topic and leave the other
+		// datasets empty at this label
+		for j := range c.Datasets {
+			if j == i {
+				c.Datasets[j].Values = append(c.Datasets[j].Values, score.Points)
+			} else {
+				c.Datasets[j].Values = append(c.Datasets[j].Values, nil)
+			}
+		}
+	}
+
+	return c
+}
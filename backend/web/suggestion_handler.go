@@ -0,0 +1,414 @@
+// The web handler evolving around suggestions, with HTTP-handler functions
+// consisting of "GET"- and "POST"-methods. It utilizes session management and
+// database access.
+//
+// Users can suggest a correction of the year or date of an event (e.g. from
+// the review of a quiz) or a new event of a topic. Suggestions get collected
+// in a moderation queue, where admins can accept them, which applies the
+// change, or reject them with a reason, which is shown to the user.
+
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/csrf"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// Statuses of a suggestion
+const (
+	suggestionPending  = "pending"
+	suggestionAccepted = "accepted"
+	suggestionRejected = "rejected"
+)
+
+var (
+	// Parsed HTML-templates to be executed in their respective HTTP-handler
+	// functions when needed
+	suggestionsCreateTemplate, suggestionsListTemplate *template.Template
+)
+
+// init gets initialized with the package.
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
+func init() {
+	if _testing { // skip initialization of templates when running tests
+		return
+	}
+
+	suggestionsCreateTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"suggestions_create.html"))
+	suggestionsListTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"suggestions_list.html"))
+}
+
+// SuggestionHandler is the object for handlers to access sessions and
+// database.
+type SuggestionHandler struct {
+	store    x.Store
+	sessions *scs.SessionManager
+}
+
+// Create is a GET-method that is accessible to any user.
+//
+// It displays a form, in which a corrected year or date of an event can be
+// entered, if an event is specified in the URL query (e.g. '?event=3').
+// Otherwise values for a new event can be entered.
+func (h *SuggestionHandler) Create() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Topic x.Topic
+		Event x.Event // event to be corrected, empty for a new event
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get a topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Retrieve event to be corrected from URL query, which has to be an
+		// event of the topic
+		var event x.Event
+		if eventIDstr := req.URL.Query().Get("event"); eventIDstr != "" {
+			eventID, _ := strconv.Atoi(eventIDstr)
+			var ok bool
+			if event, ok = findEvent(topic.Events, eventID); !ok {
+				http.NotFound(res, req)
+				return
+			}
+		}
+
+		// Execute HTML-templates with data
+		if err = suggestionsCreateTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
+			Topic:       topic,
+			Event:       event,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// CreateStore is a POST-method that is accessible to any user after Create.
+//
+// It validates the form from Create and redirects to Create in case of an
+// invalid input with the corresponding error message. In case of valid form,
+// it stores the suggestion in the moderation queue and redirects to the
+// overview of the topic.
+func (h *SuggestionHandler) CreateStore() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicIDstr := chi.URLParam(req, "topicID")
+		topicID, err := strconv.Atoi(topicIDstr)
+		if err != nil {
+			log.Printf("error parsing topic ID %q of suggestion: %v", topicIDstr, err)
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Retrieve values from form
+		eventID, _ := strconv.Atoi(req.FormValue("event"))
+		form := SuggestionForm{
+			EventID:    eventID,
+			Name:       strings.TrimSpace(req.FormValue("name")),
+			YearOrDate: strings.TrimSpace(req.FormValue("year")),
+			Comment:    strings.TrimSpace(req.FormValue("comment")),
+		}
+
+		// Validate form
+		if !form.Validate() {
+			h.sessions.Put(req.Context(), "form", form)
+			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to get the topic, in order to make sure that
+		// the event to be corrected belongs to it
+		topic, err := h.store.GetTopic(topicID)
+		if errors.Is(err, sql.ErrNoRows) { // topic doesn't exist or is in the trash
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if _, ok := findEvent(topic.Events, form.EventID); form.EventID != 0 && !ok {
+			http.NotFound(res, req)
+			return
+		}
+
+		// Retrieve user from session
+		user := req.Context().Value("user").(x.User)
+
+		// Execute SQL statement to create a suggestion
+		if err = h.store.CreateSuggestion(&x.Suggestion{
+			TopicID:   topicID,
+			EventID:   form.EventID,
+			UserID:    user.UserID,
+			Name:      form.Name,
+			Year:      form.Year,
+			EventDate: form.Date,
+			Comment:   form.Comment,
+			Status:    suggestionPending,
+			Date:      time.Now(),
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success",
+			"Vielen Dank für Ihren Vorschlag! Er wird nun von einem Administrator geprüft.")
+
+		// Redirect to overview of topic
+		http.Redirect(res, req, "/topics/"+topicIDstr, http.StatusSeeOther)
+	}
+}
+
+// List is a GET-method that is accessible to any admin.
+//
+// It lists all suggestions with a certain status (pending by default), the
+// oldest first, with the ability to accept or reject pending suggestions.
+func (h *SuggestionHandler) List() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Suggestions []x.Suggestion
+		Status      string
+		Counts      map[string]int // amount of suggestions per status
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve status from URL query
+		status := req.URL.Query().Get("status")
+		if status != suggestionAccepted && status != suggestionRejected {
+			status = suggestionPending
+		}
+
+		// Execute SQL statement to get suggestions
+		suggestions, err := h.store.GetSuggestionsByStatus(status)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statements to get amount of suggestions per status
+		counts := make(map[string]int)
+		for _, s := range []string{suggestionPending, suggestionAccepted, suggestionRejected} {
+			if counts[s], err = h.store.CountSuggestionsByStatus(s); err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		// Execute HTML-templates with data
+		if err = suggestionsListTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
+			Suggestions: suggestions,
+			Status:      status,
+			Counts:      counts,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Accept is a POST-method that is accessible to any admin after List.
+//
+// It applies a suggestion, by either correcting the event or creating a new
+// event, and marks the suggestion as accepted within a single transaction, so
+// that a suggestion can't be applied twice. It redirects to List.
+func (h *SuggestionHandler) Accept() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve suggestion ID from URL parameters
+		suggestionID, _ := strconv.Atoi(chi.URLParam(req, "suggestionID"))
+
+		// Execute SQL statement to get a suggestion
+		suggestion, err := h.store.GetSuggestion(suggestionID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Check if suggestion has already been moderated
+		if suggestion.Status != suggestionPending {
+			h.sessions.Put(req.Context(), "flash_error", "Vorschlag wurde bereits bearbeitet.")
+			http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to get the topic of the suggestion, including
+		// its events
+		topic, err := h.store.GetTopic(suggestion.TopicID)
+		if errors.Is(err, sql.ErrNoRows) { // topic is in the trash
+			h.sessions.Put(req.Context(), "flash_error",
+				"Thema existiert nicht mehr. Der Vorschlag kann nur noch abgelehnt werden.")
+			http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Apply the suggestion to a new event or to the event to be corrected
+		var previous x.Event
+		event := x.Event{
+			TopicID: suggestion.TopicID,
+			Name:    suggestion.Name,
+			Year:    suggestion.Year,
+			Date:    suggestion.EventDate,
+		}
+		if suggestion.EventID != 0 {
+			// Check if the event to be corrected still exists
+			var ok bool
+			previous, ok = findEvent(topic.Events, suggestion.EventID)
+			if !ok {
+				h.sessions.Put(req.Context(), "flash_error",
+					"Ereignis existiert nicht mehr. Der Vorschlag kann nur noch abgelehnt werden.")
+				http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+				return
+			}
+
+			event = previous
+			event.Year = suggestion.Year
+			event.Date = suggestion.EventDate
+			if suggestion.Name != "" {
+				event.Name = suggestion.Name
+			}
+		}
+
+		// Execute SQL statement to mark suggestion as accepted and to create
+		// or correct the event at once, unless another admin was faster
+		suggestion.Status = suggestionAccepted
+		err = h.store.AcceptSuggestion(&suggestion, &event)
+		if errors.Is(err, sql.ErrNoRows) {
+			h.sessions.Put(req.Context(), "flash_error", "Vorschlag wurde bereits bearbeitet.")
+			http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record creation or update in audit log and new revision
+		if suggestion.EventID == 0 {
+			audit(h.store, req, auditCreate, auditEvent, event.EventID, nil, event)
+			revise(h.store, req, auditEvent, event.EventID, nil, event)
+		} else {
+			audit(h.store, req, auditUpdate, auditEvent, event.EventID, previous, event)
+			revise(h.store, req, auditEvent, event.EventID, previous, event)
+		}
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Vorschlag wurde erfolgreich übernommen.")
+
+		// Redirect to moderation queue
+		http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+	}
+}
+
+// Reject is a POST-method that is accessible to any admin after List.
+//
+// It marks a suggestion as rejected, including a reason to be shown to the
+// user, and redirects to List.
+func (h *SuggestionHandler) Reject() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve suggestion ID from URL parameters
+		suggestionID, _ := strconv.Atoi(chi.URLParam(req, "suggestionID"))
+
+		// Validate reason
+		reason := strings.TrimSpace(req.FormValue("reason"))
+		if reason == "" || len(reason) > 1000 {
+			h.sessions.Put(req.Context(), "flash_error",
+				"Begründung darf nicht leer sein und 1000 Buchstaben nicht überschreiten.")
+			http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to get a suggestion
+		suggestion, err := h.store.GetSuggestion(suggestionID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Check if suggestion has already been moderated
+		if suggestion.Status != suggestionPending {
+			h.sessions.Put(req.Context(), "flash_error", "Vorschlag wurde bereits bearbeitet.")
+			http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to mark suggestion as rejected
+		suggestion.Status = suggestionRejected
+		suggestion.Reason = reason
+		if err = h.store.UpdateSuggestion(&suggestion); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Vorschlag wurde abgelehnt.")
+
+		// Redirect to moderation queue
+		http.Redirect(res, req, "/suggestions", http.StatusSeeOther)
+	}
+}
+
+// findEvent finds an event by ID in an array of events.
+// (Tested in handler_test.go)
+func findEvent(events []x.Event, eventID int) (x.Event, bool) {
+
+	for _, event := range events {
+		if event.EventID == eventID {
+			return event, true
+		}
+	}
+
+	return x.Event{}, false
+}
@@ -5,9 +5,16 @@
 package web
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi"
@@ -19,7 +26,12 @@ import (
 var (
 	// Parsed HTML-templates to be executed in their respective HTTP-handler
 	// functions when needed
-	topicsListTemplate, topicsCreateTemplate, topicsEditTemplate, topicsShowTemplate *template.Template
+	topicsListTemplate, topicsCreateTemplate, topicsEditTemplate, topicsShowTemplate,
+	topicsImportTemplate *template.Template
+)
+
+const (
+	topicBundleVersion = 1 // version of the JSON-format of exported topics
 )
 
 // init gets initialized with the package.
@@ -31,34 +43,54 @@ func init() {
 		return
 	}
 
-	topicsListTemplate = template.Must(template.ParseFiles(layout, templatePath+"topics_list.html"))
-	topicsCreateTemplate = template.Must(template.ParseFiles(layout, templatePath+"topics_create.html"))
-	topicsEditTemplate = template.Must(template.ParseFiles(layout, templatePath+"topics_edit.html"))
-	topicsShowTemplate = template.Must(template.ParseFiles(layout, templatePath+"topics_show.html"))
+	topicsListTemplate = template.Must(newTemplate().
+		Funcs(template.FuncMap{ // Add custom HTML-template-function to get the thumbnail of an image
+			"thumbnail": thumbnailURL,
+		}).
+		ParseFiles(layout, templatePath+"topics_list.html"))
+	topicsCreateTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"topics_create.html"))
+	topicsEditTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"topics_edit.html",
+		templatePath+"revision_history.html", templatePath+"translations_form.html"))
+	topicsShowTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"topics_show.html"))
+	topicsImportTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"topics_import.html"))
 }
 
-// TopicHandler is the object for handlers to access sessions and database.
+// TopicHandler is the object for handlers to access sessions, database and
+// uploaded images.
 type TopicHandler struct {
 	store    x.Store
+	blobs    x.BlobStore
 	sessions *scs.SessionManager
 }
 
 // List is a GET-method that is accessible to anyone.
 //
-// It lists all topics. Users can only view them or show a specific topic,
-// while admins have the ability to create a new topic, as well as to edit and
-// delete an existing one.
+// It lists all topics as a tree of topics and their sub-topics, optionally
+// filtered by a tag and by a range of years (e.g. '?tag=2&from=1800&to=1900').
+// Users can only view them or show a specific topic, while admins have the
+// ability to create a new topic, as well as to edit and delete an existing
+// one.
 func (h *TopicHandler) List() http.HandlerFunc {
 
 	// Data to pass to HTML-templates
 	type data struct {
 		SessionData
 
-		Topics []x.Topic
+		Topics    []topicNode
+		Tags      []x.Tag         // all tags, in order to filter by them
+		TopicTags map[int][]x.Tag // tags per topic ID
+		Filter    topicFilter
 	}
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
+		// Retrieve filter from URL query
+		query := req.URL.Query()
+		filter := topicFilter{}
+		filter.TagID, _ = strconv.Atoi(query.Get("tag"))
+		filter.From, _ = strconv.Atoi(query.Get("from"))
+		filter.To, _ = strconv.Atoi(query.Get("to"))
+
 		// Execute SQL statement to get topics
 		topics, err := h.store.GetTopics()
 		if err != nil {
@@ -66,10 +98,42 @@ func (h *TopicHandler) List() http.HandlerFunc {
 			return
 		}
 
+		// Execute SQL statement to get the translations of topics into the
+		// language of the user
+		translations, err := topicTranslations(h.store, localeOf(req.Context()))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		localizeTopics(topics, translations)
+
+		// Execute SQL statements to get tags and tags of topics
+		tags, err := h.store.GetTags()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		topicTags, err := h.store.GetTagsOfTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Create tree of the filtered topics, whereas the amount of events of
+		// a parent topic includes sub-topics that were filtered out
+		tree := topicTree(filterTopics(topics, topicTags, filter))
+		eventsTotals := topicEventsTotals(topics)
+		for i := range tree {
+			tree[i].EventsTotal = eventsTotals[tree[i].TopicID]
+		}
+
 		// Execute HTML-templates with data
 		if err = topicsListTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
-			Topics:      topics,
+			Topics:      tree,
+			Tags:        tags,
+			TopicTags:   topicTags,
+			Filter:      filter,
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
@@ -77,34 +141,103 @@ func (h *TopicHandler) List() http.HandlerFunc {
 	}
 }
 
+// topicFilter holds the criteria to filter the list of topics by, whereas 0
+// means no restriction.
+type topicFilter struct {
+	TagID int
+	From  int // topics must end in or after this year
+	To    int // topics must start in or before this year
+}
+
+// filterTopics filters topics by a tag and by a range of years. A topic
+// matches a range of years if its time span overlaps with it.
+// (Tested in handler_test.go)
+func filterTopics(topics []x.Topic, topicTags map[int][]x.Tag, filter topicFilter) []x.Topic {
+	var filtered []x.Topic
+
+	for _, topic := range topics {
+		if filter.From != 0 && topic.EndYear < filter.From {
+			continue
+		}
+		if filter.To != 0 && topic.StartYear > filter.To {
+			continue
+		}
+		if filter.TagID != 0 && !hasTag(topicTags[topic.TopicID], filter.TagID) {
+			continue
+		}
+		filtered = append(filtered, topic)
+	}
+
+	return filtered
+}
+
+// hasTag checks whether a tag with a certain ID is part of an array of tags.
+func hasTag(tags []x.Tag, tagID int) bool {
+
+	for _, tag := range tags {
+		if tag.TagID == tagID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagNames joins the names of tags with commas, in order to be edited in the
+// form of a topic.
+func tagNames(tags []x.Tag) string {
+
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
 // Create is a GET-method that is accessible to any admin.
 //
-// It displays a form, in which values for a new topic can be entered.
+// It displays a form, in which values for a new topic can be entered. A parent
+// topic can be preselected (e.g. '?parent=3').
 func (h *TopicHandler) Create() http.HandlerFunc {
 
 	// Data to pass to HTML-templates
 	type data struct {
 		SessionData
 		CSRF template.HTML
+
+		AllTags  []x.Tag     // existing tags to choose from
+		Parents  []topicNode // topics to choose the parent topic from
+		ParentID int         // preselected parent topic
 	}
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if an admin is logged in
-		user := req.Context().Value("user")
-		if user == nil || !user.(x.User).Admin {
-			// If no user is logged in or logged in user isn't an admin,
-			// then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", "Unzureichende Berechtigung. "+
-				"Sie müssen als Admin eingeloggt sein, um ein neues Thema zu erstellen.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+		// Retrieve preselected parent topic from URL query
+		parentID, _ := strconv.Atoi(req.URL.Query().Get("parent"))
+
+		// Execute SQL statement to get existing tags
+		tags, err := h.store.GetTags()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get topics, in order to choose the parent
+		// topic
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
 		// Execute HTML-templates with data
-		if err := topicsCreateTemplate.Execute(res, data{
+		if err = topicsCreateTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
 			CSRF:        csrf.TemplateField(req),
+			AllTags:     tags,
+			Parents:     topicParentOptions(topics, 0),
+			ParentID:    parentID,
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
@@ -121,36 +254,82 @@ func (h *TopicHandler) CreateStore() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
+		// Retrieve uploaded image from form, if any
+		upload, uploadError := readImageUpload(res, req)
+
 		// Retrieve values from form
 		startYear, _ := strconv.Atoi(req.FormValue("start_year"))
 		endYear, _ := strconv.Atoi(req.FormValue("end_year"))
+		parentID, _ := strconv.Atoi(req.FormValue("parent_id"))
 		form := TopicForm{
 			Name:        req.FormValue("name"),
 			StartYear:   startYear,
 			EndYear:     endYear,
 			Description: req.FormValue("description"),
 			Image:       req.FormValue("image"),
+			Tags:        req.FormValue("tags"),
+			ParentID:    parentID,
+			upload:      upload,
+			uploadError: uploadError,
+		}
+
+		// Execute SQL statement to get topics, in order to validate the parent
+		// topic
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		// Validate form
-		if !form.Validate() {
+		valid := form.Validate()
+		if !validParent(topics, 0, form.ParentID) {
+			form.Errors["Parent"] = "Übergeordnetes Thema existiert nicht."
+			valid = false
+		}
+		if !valid {
 			h.sessions.Put(req.Context(), "form", form)
 			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
 			return
 		}
 
+		// Save uploaded image, which replaces the URL of the image
+		if form.upload != nil {
+			imageURL, err := saveImage(h.blobs, form.upload)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			form.Image = imageURL
+		}
+
 		// Execute SQL statement to create a topic
-		if err := h.store.CreateTopic(&x.Topic{
+		topic := x.Topic{
+			ParentID:    form.ParentID,
 			Name:        form.Name,
 			StartYear:   form.StartYear,
 			EndYear:     form.EndYear,
 			Description: form.Description,
 			Image:       form.Image,
-		}); err != nil {
+		}
+		if err = h.store.CreateTopic(&topic); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to add tags to the topic
+		if err = h.store.UpdateTopicTags(topic.TopicID, parseTags(form.Tags)); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Record creation in audit log and first revision
+		audit(h.store, req, auditCreate, auditTopic, topic.TopicID, nil, topic)
+		revise(h.store, req, auditTopic, topic.TopicID, nil, topic)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
 		// Adds flash message
 		h.sessions.Put(req.Context(), "flash_success", "Thema wurde erfolgreich erstellt.")
 
@@ -161,7 +340,7 @@ func (h *TopicHandler) CreateStore() http.HandlerFunc {
 
 // Delete is a POST-method that is accessible to any admin.
 //
-// It deletes a certain topic and redirects to List.
+// It moves a certain topic to the trash and redirects to List.
 func (h *TopicHandler) Delete() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
@@ -169,13 +348,27 @@ func (h *TopicHandler) Delete() http.HandlerFunc {
 		// Retrieve TopicID from URL parameters
 		topicID, _ := strconv.Atoi(chi.URLParam(req, "topicID"))
 
-		// Execute SQL statement to delete a topic
-		if err := h.store.DeleteTopic(topicID); err != nil {
+		// Execute SQL statement to get a topic, for the audit log
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
+
+		// Execute SQL statement to move a topic to the trash
+		if err = h.store.DeleteTopic(topicID); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record deletion in audit log, including the deleted events
+		audit(h.store, req, auditDelete, auditTopic, topicID, topic, nil)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
 		// Add flash message
-		h.sessions.Put(req.Context(), "flash_success", "Thema wurde erfolgreich gelöscht.")
+		h.sessions.Put(req.Context(), "flash_success", "Thema wurde in den Papierkorb verschoben.")
 
 		// Redirect to list of topics
 		http.Redirect(res, req, "/topics", http.StatusSeeOther)
@@ -193,23 +386,21 @@ func (h *TopicHandler) Edit() http.HandlerFunc {
 		SessionData
 		CSRF template.HTML
 
-		Topic  x.Topic
-		Events []x.Event
+		Topic     x.Topic
+		Events    []x.Event
+		Tags      string      // comma-separated names of the tags of the topic
+		AllTags   []x.Tag     // existing tags to choose from
+		Parents   []topicNode // topics to choose the parent topic from
+		Revisions []revisionView
+		RevertURL string // URL to revert to a revision, without the revision ID
+
+		Translations    []translationInput // translation per language other than German
+		TranslationForm TranslationForm    // invalid translation, if any
+		TranslateURL    string             // URL to save a translation
 	}
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if an admin is logged in
-		user := req.Context().Value("user")
-		if user == nil || !user.(x.User).Admin {
-			// If no user is logged in or logged in user isn't an admin,
-			// then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error",
-				"Unzureichende Berechtigung. Sie müssen als Admin eingeloggt sein, um ein Thema zu bearbeiten.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Retrieve topic ID from URL parameters
 		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
 		if err != nil {
@@ -224,11 +415,67 @@ func (h *TopicHandler) Edit() http.HandlerFunc {
 			return
 		}
 
+		// Execute SQL statement to get the translations of the topic
+		topic.Translations, err = h.store.GetTopicTranslations(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statements to get the tags of the topic and all
+		// existing tags
+		topicTags, err := h.store.GetTagsByTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		tags, err := h.store.GetTags()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get topics, in order to choose the parent
+		// topic
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get revisions of the topic, in order to
+		// display its history
+		revisions, err := h.store.GetRevisionsByTarget(auditTopic, topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		history, err := revisionHistory(revisions)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Separate an invalid translation from the form of the topic
+		sessionData := GetSessionData(h.sessions, req.Context())
+		translationForm, _ := sessionData.Form.(TranslationForm)
+		if translationForm.Errors != nil {
+			sessionData.Form = map[string]string{}
+		}
+
 		// Execute HTML-templates with data
 		if err = topicsEditTemplate.Execute(res, data{
-			SessionData: GetSessionData(h.sessions, req.Context()),
-			CSRF:        csrf.TemplateField(req),
-			Topic:       topic,
+			SessionData:     sessionData,
+			CSRF:            csrf.TemplateField(req),
+			Topic:           topic,
+			Tags:            tagNames(topicTags),
+			AllTags:         tags,
+			Parents:         topicParentOptions(topics, topicID),
+			Revisions:       history,
+			RevertURL:       fmt.Sprintf("/topics/%v/revisions/", topic.TopicID),
+			Translations:    translationInputs(topic.Translations),
+			TranslationForm: translationForm,
+			TranslateURL:    fmt.Sprintf("/topics/%v/translations", topic.TopicID),
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
@@ -245,41 +492,109 @@ func (h *TopicHandler) EditStore() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
+		// Retrieve uploaded image from form, if any
+		upload, uploadError := readImageUpload(res, req)
+
 		// Retrieve values from form
 		startYear, _ := strconv.Atoi(req.FormValue("start_year"))
 		endYear, _ := strconv.Atoi(req.FormValue("end_year"))
+		parentID, _ := strconv.Atoi(req.FormValue("parent_id"))
 		form := TopicForm{
 			Name:        req.FormValue("name"),
 			StartYear:   startYear,
 			EndYear:     endYear,
 			Description: req.FormValue("description"),
 			Image:       req.FormValue("image"),
+			Tags:        req.FormValue("tags"),
+			ParentID:    parentID,
+			upload:      upload,
+			uploadError: uploadError,
 		}
 
-		// Validate form
-		if !form.Validate() {
+		// Retrieve topic ID from URL
+		topicIDstr := chi.URLParam(req, "topicID")
+		topicID, _ := strconv.Atoi(topicIDstr)
+
+		// Execute SQL statement to get topics, in order to validate the parent
+		// topic
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Validate form. A topic can't be moved into itself or into one of
+		// its sub-topics.
+		valid := form.Validate()
+		if !validParent(topics, topicID, form.ParentID) {
+			form.Errors["Parent"] = "Ein Thema kann nicht sich selbst oder einem seiner Unterthemen " +
+				"untergeordnet werden."
+			valid = false
+		}
+		if !valid {
 			h.sessions.Put(req.Context(), "form", form)
 			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
 			return
 		}
 
-		// Retrieve topic ID from URL
-		topicIDstr := chi.URLParam(req, "topicID")
-		topicID, _ := strconv.Atoi(topicIDstr)
+		// Save uploaded image, which replaces the URL of the image
+		if form.upload != nil {
+			imageURL, err := saveImage(h.blobs, form.upload)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			form.Image = imageURL
+		}
+
+		// Execute SQL statement to get the topic before updating it
+		previous, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		// Execute SQL statement to update a topic
-		if err := h.store.UpdateTopic(&x.Topic{
+		topic := x.Topic{
 			TopicID:     topicID,
+			ParentID:    form.ParentID,
 			Name:        form.Name,
 			StartYear:   form.StartYear,
 			EndYear:     form.EndYear,
 			Description: form.Description,
 			Image:       form.Image,
-		}); err != nil {
+		}
+		if err = h.store.UpdateTopic(&topic); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Execute SQL statement to move the topic, if its parent topic changed
+		if topic.ParentID != previous.ParentID {
+			if err = h.store.MoveTopic(topicID, topic.ParentID); err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		// Execute SQL statement to replace the tags of the topic
+		if err = h.store.UpdateTopicTags(topicID, parseTags(form.Tags)); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record update in audit log (events aren't affected)
+		before := previous
+		before.Events, before.ScoresCount, before.EventsCount = nil, 0, 0
+		audit(h.store, req, auditUpdate, auditTopic, topicID, before, topic)
+
+		// Record new revision. A replaced image doesn't get deleted, since
+		// previous revisions still refer to it.
+		revise(h.store, req, auditTopic, topicID, before, topic)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
 		// Add flash message
 		h.sessions.Put(req.Context(), "flash_success", "Thema wurde erfolgreich bearbeitet.")
 
@@ -288,9 +603,140 @@ func (h *TopicHandler) EditStore() http.HandlerFunc {
 	}
 }
 
+// Revert is a POST-method that is accessible to any admin after Edit.
+//
+// It reverts a topic to a previous revision, which gets recorded as a new
+// revision, and redirects to Edit. The events and the parent topic of the
+// topic aren't affected.
+func (h *TopicHandler) Revert() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID and revision ID from URL parameters
+		topicID, _ := strconv.Atoi(chi.URLParam(req, "topicID"))
+		revisionID, _ := strconv.Atoi(chi.URLParam(req, "revisionID"))
+
+		// Execute SQL statement to get the revision
+		revision, err := h.store.GetRevision(revisionID)
+		if err != nil || revision.TargetType != auditTopic || revision.TargetID != topicID {
+			http.NotFound(res, req)
+			return
+		}
+
+		// Execute SQL statement to get the topic before reverting it
+		previous, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		previous.Events, previous.ScoresCount, previous.EventsCount = nil, 0, 0
+
+		// Restore the topic from the snapshot of the revision
+		var topic x.Topic
+		if err = json.Unmarshal([]byte(revision.Snapshot), &topic); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		topic.TopicID, topic.ParentID, topic.DeletedAt = topicID, previous.ParentID, nil
+
+		// Execute SQL statement to update a topic
+		if err = h.store.UpdateTopic(&topic); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record reversion in audit log and new revision
+		audit(h.store, req, auditRevert, auditTopic, topicID, previous, topic)
+		revise(h.store, req, auditTopic, topicID, previous, topic)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Thema wurde auf die Version vom "+
+			formatDateTime(localeOf(req.Context()), revision.Date)+" zurückgesetzt.")
+
+		// Redirect to Edit
+		http.Redirect(res, req, "/topics/"+strconv.Itoa(topicID)+"/edit", http.StatusSeeOther)
+	}
+}
+
+// Translate is a POST-method that is accessible to any admin after Edit.
+//
+// It validates the form of a translation from Edit and redirects to Edit in
+// case of an invalid input with the corresponding error message. In case of a
+// valid form, it stores the translation of the topic into another language in
+// the database, or deletes it if the name and description are empty, and
+// redirects to Edit.
+func (h *TopicHandler) Translate() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve values from form
+		form := TranslationForm{
+			Locale:      req.FormValue("locale"),
+			Name:        strings.TrimSpace(req.FormValue("name")),
+			Description: strings.TrimSpace(req.FormValue("description")),
+			maxName:     50,
+		}
+
+		// Validate form
+		if !form.Validate() {
+			h.sessions.Put(req.Context(), "form", form)
+			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+			return
+		}
+
+		// Retrieve topic ID from URL parameters
+		topicID, _ := strconv.Atoi(chi.URLParam(req, "topicID"))
+
+		// Execute SQL statement to get the translations of the topic before
+		// changing them
+		translations, err := h.store.GetTopicTranslations(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		previous := translationOf(translations, form.Locale)
+
+		// Execute SQL statement to delete the translation, in case of an empty
+		// form, or to save it
+		translation := x.Translation{
+			ID:          topicID,
+			Locale:      form.Locale,
+			Name:        form.Name,
+			Description: form.Description,
+		}
+		var current interface{} = translation
+		if form.Name == "" {
+			err = h.store.DeleteTopicTranslation(topicID, form.Locale)
+			current = nil
+		} else {
+			err = h.store.SaveTopicTranslation(&translation)
+		}
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record translation in audit log
+		audit(h.store, req, auditTranslate, auditTopic, topicID, previous, current)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Übersetzung wurde erfolgreich gespeichert.")
+
+		// Redirect to Edit
+		http.Redirect(res, req, "/topics/"+strconv.Itoa(topicID)+"/edit", http.StatusSeeOther)
+	}
+}
+
 // Show is a GET-method that is accessible to anyone.
 //
-// It displays details of the topic. Anyone can view the topic, while users
+// It displays details of the topic, including its parent topics as breadcrumbs,
+// its sub-topics and its best scores. Anyone can view the topic, while users
 // have the ability to play the quiz and admins have the ability to edit or
 // delete the topic.
 func (h *TopicHandler) Show() http.HandlerFunc {
@@ -300,7 +746,12 @@ func (h *TopicHandler) Show() http.HandlerFunc {
 		SessionData
 		CSRF template.HTML
 
-		Topic x.Topic
+		Topic       x.Topic
+		Tags        []x.Tag
+		Ancestors   []x.Topic   // parent topics, starting with the top-level topic
+		SubTopics   []topicNode // all descendants
+		EventsTotal int         // amount of events including all descendants
+		Leaderboard []x.Score   // best scores of the topic, only for logged in users
 	}
 
 	return func(res http.ResponseWriter, req *http.Request) {
@@ -319,14 +770,357 @@ func (h *TopicHandler) Show() http.HandlerFunc {
 			return
 		}
 
+		// Execute SQL statement to get the tags of the topic
+		tags, err := h.store.GetTagsByTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get topics, in order to display the
+		// hierarchy of the topic
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get the translations of topics into the
+		// language of the user
+		translations, err := topicTranslations(h.store, localeOf(req.Context()))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		localizeTopics(topics, translations)
+		topic.Name, topic.Description = localizeText(topic.Name, topic.Description, translations[topicID])
+
+		// Execute SQL statement to get the best scores of the topic, which
+		// are only visible to logged in users, just like the leaderboard
+		var leaderboard []x.Score
+		if _, ok := req.Context().Value("user").(x.User); ok {
+			leaderboard, err = h.store.GetLeaderboard(x.LeaderboardFilter{
+				Mode:    x.LeaderboardBest,
+				TopicID: topicID,
+				Limit:   topicLeaderboardSize,
+			})
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
 		// Execute HTML-templates with data
 		if err = topicsShowTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
 			CSRF:        csrf.TemplateField(req),
 			Topic:       topic,
+			Tags:        tags,
+			Ancestors:   topicAncestors(topics, topic),
+			SubTopics:   topicSubtree(topics, topicID),
+			EventsTotal: topicEventsTotals(topics)[topicID],
+			Leaderboard: leaderboard,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Export is a GET-method that is accessible to any admin.
+//
+// It downloads the topic including all of its events as a versioned JSON-file,
+// which can be imported again through Import.
+func (h *TopicHandler) Export() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get a topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Encode topic as JSON-file
+		content, err := json.MarshalIndent(newTopicBundle(topic), "", "  ")
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Send JSON-file as download
+		res.Header().Set("Content-Type", "application/json; charset=utf-8")
+		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+			bundleFilename(topic.Name)))
+		_, _ = res.Write(content)
+	}
+}
+
+// Import is a GET-method that is accessible to any admin.
+//
+// It displays a form, in which a JSON-file of a previously exported topic can
+// be uploaded.
+func (h *TopicHandler) Import() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Execute HTML-templates with data
+		if err := topicsImportTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 }
+
+// ImportSubmit is a POST-method that is accessible to any admin after Import.
+//
+// It validates the uploaded JSON-file and creates a new topic including all
+// of its events. If a topic with the same name already exists, a number gets
+// appended to the name of the new topic.
+func (h *TopicHandler) ImportSubmit() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve file or pasted content from form
+		content, _, err := readImportContent(res, req)
+		if err != nil {
+			h.sessions.Put(req.Context(), "flash_error", err.Error())
+			http.Redirect(res, req, "/topics/import", http.StatusSeeOther)
+			return
+		}
+
+		// Parse and validate the topic and its events
+		topic, err := parseTopicBundle(content)
+		if err != nil {
+			h.sessions.Put(req.Context(), "flash_error", err.Error())
+			http.Redirect(res, req, "/topics/import", http.StatusSeeOther)
+			return
+		}
+
+		// Execute SQL statement to get topics
+		topics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Avoid a name conflict with an existing topic
+		originalName := topic.Name
+		topic.Name = uniqueTopicName(topic.Name, topics)
+
+		// Execute SQL statement to create a topic including its events
+		if err = h.store.CreateTopicWithEvents(&topic); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record import in audit log and first revision
+		audit(h.store, req, auditImport, auditTopic, topic.TopicID, nil, topic)
+		revise(h.store, req, auditTopic, topic.TopicID, nil, topic)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash messages
+		h.sessions.Put(req.Context(), "flash_success", fmt.Sprintf(
+			"Thema wurde erfolgreich importiert, inklusive %v Ereignissen.", len(topic.Events)))
+		if topic.Name != originalName {
+			h.sessions.Put(req.Context(), "flash_info", fmt.Sprintf("Da bereits ein Thema '%v' existiert, "+
+				"wurde das importierte Thema in '%v' umbenannt.", originalName, topic.Name))
+		}
+
+		// Redirect to the new topic
+		http.Redirect(res, req, "/topics/"+strconv.Itoa(topic.TopicID), http.StatusSeeOther)
+	}
+}
+
+// topicBundle represents the portable JSON-format of a topic including all of
+// its events, which can be exported and imported between environments.
+type topicBundle struct {
+	Version  int                `json:"version"`
+	Exported time.Time          `json:"exported"`
+	Topic    topicBundleTopic   `json:"topic"`
+	Events   []topicBundleEvent `json:"events"`
+}
+
+// topicBundleTopic represents the metadata of a topic in a topicBundle.
+type topicBundleTopic struct {
+	Name        string `json:"name"`
+	StartYear   int    `json:"start_year"`
+	EndYear     int    `json:"end_year"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+}
+
+// topicBundleEvent represents an event in a topicBundle. The date is
+// formatted as 'yyyy-mm-dd'.
+type topicBundleEvent struct {
+	Name        string `json:"name"`
+	Year        int    `json:"year"`
+	Date        string `json:"date"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+// newTopicBundle converts a topic including its events to a topicBundle.
+func newTopicBundle(topic x.Topic) topicBundle {
+
+	bundle := topicBundle{
+		Version:  topicBundleVersion,
+		Exported: time.Now().UTC().Truncate(time.Second),
+		Topic: topicBundleTopic{
+			Name:        topic.Name,
+			StartYear:   topic.StartYear,
+			EndYear:     topic.EndYear,
+			Description: topic.Description,
+			Image:       topic.Image,
+		},
+		Events: []topicBundleEvent{},
+	}
+
+	for _, event := range topic.Events {
+		bundle.Events = append(bundle.Events, topicBundleEvent{
+			Name:        event.Name,
+			Year:        event.Year,
+			Date:        event.Date.Format("2006-01-02"),
+			Description: event.Description,
+			Source:      event.Source,
+			Image:       event.Image,
+		})
+	}
+
+	return bundle
+}
+
+// parseTopicBundle parses the content of a JSON-file into a topic including
+// its events. The topic and each event get validated the same way as when
+// creating them through the forms.
+// (Tested in handler_test.go)
+func parseTopicBundle(content []byte) (x.Topic, error) {
+
+	// Decode JSON-file
+	var bundle topicBundle
+	if err := json.Unmarshal(content, &bundle); err != nil {
+		return x.Topic{}, errors.New("Die Datei ist kein gültiges exportiertes Thema.")
+	}
+	if bundle.Version != topicBundleVersion {
+		return x.Topic{}, fmt.Errorf("Die Version %v der Datei wird nicht unterstützt (erwartet: %v).",
+			bundle.Version, topicBundleVersion)
+	}
+
+	// Validate topic
+	form := TopicForm{
+		Name:        strings.TrimSpace(bundle.Topic.Name),
+		StartYear:   bundle.Topic.StartYear,
+		EndYear:     bundle.Topic.EndYear,
+		Description: bundle.Topic.Description,
+		Image:       bundle.Topic.Image,
+	}
+	if !form.Validate() {
+		for _, msg := range form.Errors {
+			return x.Topic{}, errors.New("Ungültiges Thema: " + msg)
+		}
+	}
+
+	topic := x.Topic{
+		Name:        form.Name,
+		StartYear:   form.StartYear,
+		EndYear:     form.EndYear,
+		Description: form.Description,
+		Image:       form.Image,
+	}
+
+	// Validate events
+	for num, event := range bundle.Events {
+		eventForm := EventForm{
+			Name:        strings.TrimSpace(event.Name),
+			YearOrDate:  strconv.Itoa(event.Year),
+			Description: event.Description,
+			Source:      event.Source,
+			Image:       event.Image,
+		}
+		// Use the exact date, unless the event only has a year (which is
+		// stored as January 1st)
+		date, err := time.Parse("2006-01-02", event.Date)
+		if err == nil && date.Year() == event.Year && date.YearDay() != 1 {
+			eventForm.YearOrDate = date.Format("02.01.2006")
+		}
+		if !eventForm.Validate() {
+			for _, msg := range eventForm.Errors {
+				return x.Topic{}, fmt.Errorf("Ungültiges Ereignis Nr. %v ('%v'): %v", num+1, event.Name, msg)
+			}
+		}
+		topic.Events = append(topic.Events, x.Event{
+			Name:        eventForm.Name,
+			Year:        eventForm.Year,
+			Date:        eventForm.Date,
+			Description: eventForm.Description,
+			Source:      eventForm.Source,
+			Image:       eventForm.Image,
+		})
+	}
+
+	return topic, nil
+}
+
+// uniqueTopicName returns a name, which isn't taken by any of the topics yet,
+// by appending a number to the name if necessary, without exceeding the
+// maximum length of a topic's name.
+// Example: 'Mittelalter' => 'Mittelalter (2)'
+// (Tested in handler_test.go)
+func uniqueTopicName(name string, topics []x.Topic) string {
+
+	taken := map[string]bool{}
+	for _, topic := range topics {
+		taken[strings.ToLower(topic.Name)] = true
+	}
+
+	if !taken[strings.ToLower(name)] {
+		return name
+	}
+
+	for num := 2; ; num++ {
+		suffix := fmt.Sprintf(" (%v)", num)
+		base := name
+		for len(base)+len(suffix) > 50 { // remove whole characters only, since umlauts consist of 2 bytes
+			_, size := utf8.DecodeLastRuneInString(base)
+			base = base[:len(base)-size]
+		}
+		base = strings.TrimSpace(base)
+		if !taken[strings.ToLower(base+suffix)] {
+			return base + suffix
+		}
+	}
+}
+
+// bundleFilename returns the filename of an exported topic, consisting of
+// only lowercase letters, numbers and '-'.
+// Example: 'Zweiter Weltkrieg' => 'zweiter-weltkrieg.json'
+func bundleFilename(name string) string {
+
+	filename := strings.Trim(regexp.MustCompile("[^a-z0-9]+").
+		ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if filename == "" {
+		filename = "thema"
+	}
+
+	return filename + ".json"
+}
@@ -0,0 +1,271 @@
+// A branch of the topic handler (for a better overview), which contains HTTP-
+// handlers that generate material for studying a topic offline, such as a
+// deck of flashcards for Anki and a printable worksheet. Both count as a
+// practice session in the activity of the user.
+
+package web
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	worksheetEventsDefault = 10 // default amount of events in the timeline exercise of a worksheet
+	worksheetEventsMax     = 26 // max amount of events in the timeline exercise of a worksheet (letters A-Z)
+)
+
+var (
+	// Parsed HTML-templates to be executed in their respective HTTP-handler
+	// functions when needed
+	topicsWorksheetTemplate *template.Template
+)
+
+// init gets initialized with the package.
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
+func init() {
+	if _testing { // skip initialization of templates when running tests
+		return
+	}
+
+	// The worksheet is meant to be printed, thus it doesn't use the layout
+	topicsWorksheetTemplate = template.Must(template.New("topics_worksheet.html").
+		Funcs(templateFuncs).
+		Funcs(template.FuncMap{
+			"increment": func(num int) int {
+				return num + 1
+			},
+			"letter": worksheetLetter,
+		}).
+		ParseFiles(templatePath + "topics_worksheet.html"))
+}
+
+// Anki is a GET-method that is accessible to any user.
+//
+// It downloads all events of a topic as a deck of flashcards in the plain
+// text format of Anki, where the front of each card contains the name of an
+// event and the back contains its year or date.
+func (h *TopicHandler) Anki() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get a topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Create deck of flashcards
+		deck, err := createAnkiDeck(topic)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Add practice session to the user's activity of the day, which isn't
+		// essential for studying, so that a failure only gets logged
+		if err = addPractice(h.store, h.sessions, req, topicID); err != nil {
+			log.Printf("error adding practice of topic %v to activity: %v", topicID, err)
+		}
+
+		// Send deck as download
+		res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+			strings.TrimSuffix(bundleFilename(topic.Name), ".json")+"-anki.txt"))
+		_, _ = res.Write(deck)
+	}
+}
+
+// Worksheet is a GET-method that is accessible to any user.
+//
+// It displays a printable worksheet with a timeline exercise, in which random
+// events of the topic have to be put in chronological order, and a separate
+// answer key on the next page. The amount of events can be chosen via the URL
+// query 'events'.
+func (h *TopicHandler) Worksheet() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		Topic     x.Topic
+		Questions []phase3Question // shuffled events for the exercise
+		Answers   []worksheetAnswer
+		Date      string
+		Locale    string
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, err := strconv.Atoi(chi.URLParam(req, "topicID"))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Execute SQL statement to get a topic
+		topic, err := h.store.GetTopic(topicID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Localize the topic and its events to the language of the user
+		topic, err = withTranslations(h.store, topic, localeOf(req.Context()))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Retrieve amount of events from URL query
+		amount, err := strconv.Atoi(req.URL.Query().Get("events"))
+		if err != nil || amount <= 0 {
+			amount = worksheetEventsDefault
+		}
+		amount = min(amount, worksheetEventsMax)
+
+		// Create timeline exercise, the same way as in phase 3 of a quiz
+		questions, events := createTimelineQuestions(topic.Events, amount)
+
+		// Add practice session to the user's activity of the day, which isn't
+		// essential for studying, so that a failure only gets logged
+		if err = addPractice(h.store, h.sessions, req, topicID); err != nil {
+			log.Printf("error adding practice of topic %v to activity: %v", topicID, err)
+		}
+
+		// Execute HTML-templates with data
+		if err = topicsWorksheetTemplate.Execute(res, data{
+			Topic:     topic,
+			Questions: questions,
+			Answers:   createWorksheetAnswers(questions, events),
+			Date:      formatDate(localeOf(req.Context()), time.Now()),
+			Locale:    localeOf(req.Context()),
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// addPractice adds a practice session, such as downloading flashcards or
+// printing a worksheet, to the activity of the logged in user of the day. A
+// practice counts at most once per topic and day, which is remembered in the
+// session, so that reloading the page doesn't inflate the activity.
+func addPractice(store x.Store, sessions *scs.SessionManager, req *http.Request, topicID int) error {
+
+	user, ok := req.Context().Value("user").(x.User)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	key := fmt.Sprintf("practice_%v", topicID)
+	if sessions.GetString(req.Context(), key) == now.Format("2006-01-02") {
+		return nil
+	}
+
+	if err := store.AddActivity(&x.Activity{
+		UserID:    user.UserID,
+		Day:       now,
+		Practices: 1,
+	}); err != nil {
+		return err
+	}
+	sessions.Put(req.Context(), key, now.Format("2006-01-02"))
+
+	return nil
+}
+
+// createAnkiDeck generates a deck of flashcards of all events of a topic in
+// the plain text format of Anki (tab-separated, with header lines).
+// Example line: 'Mauerfall	09.11.1989	Kalter_Krieg'
+// (Tested in handler_test.go)
+func createAnkiDeck(topic x.Topic) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	// Header lines to configure the import in Anki
+	tag := strings.ReplaceAll(strings.TrimSpace(topic.Name), " ", "_")
+	buffer.WriteString("#separator:tab\n")
+	buffer.WriteString("#html:false\n")
+	buffer.WriteString("#notetype:Basic\n")
+	buffer.WriteString("#deck:Jahreszahlen::" + strings.ReplaceAll(topic.Name, "\n", " ") + "\n")
+	buffer.WriteString("#tags column:3\n")
+
+	// One card per event
+	writer := csv.NewWriter(&buffer)
+	writer.Comma = '\t'
+	for _, event := range topic.Events {
+		if err := writer.Write([]string{event.Name, eventYearOrDate(event), tag}); err != nil {
+			return nil, fmt.Errorf("error writing anki deck: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("error writing anki deck: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// eventYearOrDate returns the year of an event, or its exact date formatted as
+// 'dd.mm.yyyy' if the event has a date other than January 1st.
+func eventYearOrDate(event x.Event) string {
+
+	if event.Date.Year() == event.Year && event.Date.YearDay() != 1 {
+		return event.Date.Format("02.01.2006")
+	}
+
+	return strconv.Itoa(event.Year)
+}
+
+// worksheetAnswer represents 1 row of the answer key of a worksheet.
+type worksheetAnswer struct {
+	Letter     string // letter of the event in the exercise
+	EventName  string
+	YearOrDate string
+}
+
+// createWorksheetAnswers generates the answer key of a worksheet, which
+// contains the events in chronological order, each with the letter it has in
+// the shuffled exercise.
+// (Tested in handler_test.go)
+func createWorksheetAnswers(questions []phase3Question, events []x.Event) []worksheetAnswer {
+
+	answers := make([]worksheetAnswer, len(questions))
+	for i, question := range questions {
+		answers[question.Order] = worksheetAnswer{
+			Letter:     worksheetLetter(i),
+			EventName:  question.EventName,
+			YearOrDate: eventYearOrDate(events[question.Order]),
+		}
+	}
+
+	return answers
+}
+
+// worksheetLetter returns the letter of the nth event of a worksheet.
+// Example: 0 => 'A', 25 => 'Z'
+func worksheetLetter(num int) string {
+	return string(rune('A' + num%26))
+}
@@ -0,0 +1,264 @@
+// Responsible for the hierarchy of topics, in which a topic can optionally be
+// part of a parent topic (e.g. "Schlacht um Stalingrad" of "Zweiter
+// Weltkrieg").
+//
+// A parent topic aggregates the events of all its descendants, which is why
+// its quiz draws from all of them. Topics, whose parent topic is missing
+// (e.g. in the trash or filtered out), are treated as top-level topics.
+
+package web
+
+import (
+	"strings"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// topicNode is a topic within the tree of topics.
+type topicNode struct {
+	x.Topic
+	Depth       int // 0 for a top-level topic
+	EventsTotal int // amount of events of the topic and all its descendants
+	HasChildren bool
+}
+
+// Indent returns the indentation of the topic in the tree in 'rem', to be
+// used in HTML-templates.
+func (node topicNode) Indent() int {
+	return min(node.Depth, 4) * 2
+}
+
+// Prefix returns the indentation of the topic in the tree as text, to be used
+// in the options of a select.
+func (node topicNode) Prefix() string {
+	return strings.Repeat("— ", node.Depth)
+}
+
+// topicTree flattens the hierarchy of topics depth-first, so that every topic
+// is directly followed by its descendants. The order of the topics among
+// their siblings is retained.
+// (Tested in handler_test.go)
+func topicTree(topics []x.Topic) []topicNode {
+
+	children := topicChildren(topics)
+	totals := topicEventsTotals(topics)
+
+	var nodes []topicNode
+	visited := map[int]bool{}
+
+	var visit func(topic x.Topic, depth int)
+	visit = func(topic x.Topic, depth int) {
+		if visited[topic.TopicID] {
+			return
+		}
+		visited[topic.TopicID] = true
+
+		nodes = append(nodes, topicNode{
+			Topic:       topic,
+			Depth:       depth,
+			EventsTotal: totals[topic.TopicID],
+			HasChildren: len(children[topic.TopicID]) > 0,
+		})
+		for _, child := range children[topic.TopicID] {
+			visit(child, depth+1)
+		}
+	}
+
+	for _, topic := range topics {
+		if !hasTopic(topics, topic.ParentID) {
+			visit(topic, 0)
+		}
+	}
+
+	// Topics caught in a cycle of parents can't be reached from any top-level
+	// topic, which is why they get appended as top-level topics
+	for _, topic := range topics {
+		visit(topic, 0)
+	}
+
+	return nodes
+}
+
+// topicSubtree gets the tree of all descendants of a topic, whereas its
+// direct sub-topics have a depth of 0.
+// (Tested in handler_test.go)
+func topicSubtree(topics []x.Topic, topicID int) []topicNode {
+
+	isDescendant := map[int]bool{}
+	for _, id := range topicDescendantIDs(topics, topicID) {
+		isDescendant[id] = true
+	}
+
+	// Retain the original order of the topics among their siblings
+	var descendants []x.Topic
+	for _, topic := range topics {
+		if isDescendant[topic.TopicID] {
+			descendants = append(descendants, topic)
+		}
+	}
+
+	return topicTree(descendants)
+}
+
+// topicChildren maps the ID of every topic to its direct sub-topics.
+func topicChildren(topics []x.Topic) map[int][]x.Topic {
+
+	children := map[int][]x.Topic{}
+	for _, topic := range topics {
+		if topic.ParentID != 0 && topic.ParentID != topic.TopicID {
+			children[topic.ParentID] = append(children[topic.ParentID], topic)
+		}
+	}
+
+	return children
+}
+
+// topicEventsTotals maps the ID of every topic to the amount of events of the
+// topic and all its descendants.
+// (Tested in handler_test.go)
+func topicEventsTotals(topics []x.Topic) map[int]int {
+
+	totals := map[int]int{}
+	for _, topic := range topics {
+		totals[topic.TopicID] = topic.EventsCount
+		for _, descendantID := range topicDescendantIDs(topics, topic.TopicID) {
+			totals[topic.TopicID] += topicByID(topics, descendantID).EventsCount
+		}
+	}
+
+	return totals
+}
+
+// topicDescendantIDs gets the IDs of all sub-topics of a topic, including the
+// sub-topics of its sub-topics.
+// (Tested in handler_test.go)
+func topicDescendantIDs(topics []x.Topic, topicID int) []int {
+
+	children := topicChildren(topics)
+
+	var ids []int
+	visited := map[int]bool{topicID: true}
+	queue := []int{topicID}
+	for len(queue) > 0 {
+		for _, child := range children[queue[0]] {
+			if !visited[child.TopicID] {
+				visited[child.TopicID] = true
+				ids = append(ids, child.TopicID)
+				queue = append(queue, child.TopicID)
+			}
+		}
+		queue = queue[1:]
+	}
+
+	return ids
+}
+
+// topicAncestors gets all parent topics of a topic, starting with the
+// top-level topic, in order to display them as breadcrumbs.
+// (Tested in handler_test.go)
+func topicAncestors(topics []x.Topic, topic x.Topic) []x.Topic {
+	var ancestors []x.Topic
+
+	visited := map[int]bool{topic.TopicID: true}
+	for parentID := topic.ParentID; parentID != 0 && !visited[parentID]; {
+		parent := topicByID(topics, parentID)
+		if parent.TopicID == 0 { // parent topic is missing
+			break
+		}
+		visited[parentID] = true
+		ancestors = append([]x.Topic{parent}, ancestors...)
+		parentID = parent.ParentID
+	}
+
+	return ancestors
+}
+
+// topicParentOptions gets all topics, which a topic can be moved into, which
+// excludes the topic itself and its descendants to prevent a cycle. A topic ID
+// of 0 (for a new topic) allows all topics.
+func topicParentOptions(topics []x.Topic, topicID int) []topicNode {
+
+	excluded := map[int]bool{}
+	if topicID != 0 {
+		excluded[topicID] = true
+		for _, id := range topicDescendantIDs(topics, topicID) {
+			excluded[id] = true
+		}
+	}
+
+	var options []topicNode
+	for _, node := range topicTree(topics) {
+		if !excluded[node.TopicID] {
+			options = append(options, node)
+		}
+	}
+
+	return options
+}
+
+// validParent checks whether a topic can be moved into a certain parent topic,
+// which must exist and mustn't be the topic itself or one of its descendants.
+// A parent ID of 0 (top-level topic) is always valid.
+// (Tested in handler_test.go)
+func validParent(topics []x.Topic, topicID int, parentID int) bool {
+
+	if parentID == 0 {
+		return true
+	}
+	if parentID == topicID || !hasTopic(topics, parentID) {
+		return false
+	}
+	for _, id := range topicDescendantIDs(topics, topicID) {
+		if id == parentID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// topicByID finds a topic by its ID, or returns an empty topic if none
+// matches.
+func topicByID(topics []x.Topic, topicID int) x.Topic {
+
+	for _, topic := range topics {
+		if topic.TopicID == topicID {
+			return topic
+		}
+	}
+
+	return x.Topic{}
+}
+
+// hasTopic checks whether a topic with a certain ID is part of an array of
+// topics.
+func hasTopic(topics []x.Topic, topicID int) bool {
+	return topicID != 0 && topicByID(topics, topicID).TopicID != 0
+}
+
+// withDescendantEvents replaces the events of a topic with the events of the
+// topic and all its descendants, so that quizzes on a parent topic draw from
+// all of them.
+func withDescendantEvents(store x.Store, topic x.Topic) (x.Topic, error) {
+
+	// Execute SQL statement to get all topics
+	topics, err := store.GetTopics()
+	if err != nil {
+		return x.Topic{}, err
+	}
+
+	descendantIDs := topicDescendantIDs(topics, topic.TopicID)
+	if len(descendantIDs) == 0 {
+		return topic, nil
+	}
+
+	// Execute SQL statement to get the events of the topic and its
+	// descendants
+	events, err := store.GetEventsByTopics(append([]int{topic.TopicID}, descendantIDs...))
+	if err != nil {
+		return x.Topic{}, err
+	}
+	topic.Events, topic.EventsCount = events, len(events)
+
+	return topic, nil
+}
@@ -0,0 +1,158 @@
+// Responsible for the translations of topics and events into other languages
+// than German, for bilingual schools.
+//
+// Topics and events get displayed in the negotiated language of a request. If
+// there is no translation into that language, the German name and description
+// are displayed instead. The same applies to a missing description of an
+// existing translation.
+
+package web
+
+import (
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+// translationInput is the translation of a topic or event into a language, to
+// be edited in a form.
+type translationInput struct {
+	x.Translation
+	Language string // name of the language in the language itself (e.g. "English")
+}
+
+// translationInputs creates a translation to be edited for every locale other
+// than German, whereas locales without a translation get an empty one.
+// (Tested in handler_test.go)
+func translationInputs(translations []x.Translation) []translationInput {
+	var inputs []translationInput
+
+	for _, locale := range locales {
+		if locale == defaultLocale {
+			continue
+		}
+
+		input := translationInput{
+			Translation: x.Translation{Locale: locale},
+			Language:    languageNames[locale],
+		}
+		for _, translation := range translations {
+			if translation.Locale == locale {
+				input.Translation = translation
+			}
+		}
+		inputs = append(inputs, input)
+	}
+
+	return inputs
+}
+
+// localizeTopics replaces the names and descriptions of topics with their
+// translations, falling back to German for missing translations.
+// (Tested in handler_test.go)
+func localizeTopics(topics []x.Topic, translations map[int]x.Translation) {
+	for i := range topics {
+		topics[i].Name, topics[i].Description = localizeText(topics[i].Name, topics[i].Description,
+			translations[topics[i].TopicID])
+	}
+}
+
+// localizeEvents replaces the names and descriptions of events with their
+// translations, falling back to German for missing translations.
+func localizeEvents(events []x.Event, translations map[int]x.Translation) {
+	for i := range events {
+		events[i].Name, events[i].Description = localizeText(events[i].Name, events[i].Description,
+			translations[events[i].EventID])
+	}
+}
+
+// localizeText chooses the name and description of a translation over the
+// German ones, as long as they aren't empty.
+func localizeText(name string, description string, translation x.Translation) (string, string) {
+
+	if translation.Name != "" {
+		name = translation.Name
+	}
+	if translation.Description != "" {
+		description = translation.Description
+	}
+
+	return name, description
+}
+
+// topicTranslations gets the translations of all topics into a locale, or none
+// in case of German.
+func topicTranslations(store x.TranslationStore, locale string) (map[int]x.Translation, error) {
+
+	if locale == defaultLocale {
+		return map[int]x.Translation{}, nil
+	}
+
+	// Execute SQL statement to get the translations of topics
+	return store.GetTopicTranslationsByLocale(locale)
+}
+
+// withTranslations localizes a topic and its events, which may belong to
+// sub-topics, to a locale.
+func withTranslations(store x.TranslationStore, topic x.Topic, locale string) (x.Topic, error) {
+
+	if locale == defaultLocale {
+		return topic, nil
+	}
+
+	// Execute SQL statement to get the translations of topics
+	translations, err := store.GetTopicTranslationsByLocale(locale)
+	if err != nil {
+		return x.Topic{}, err
+	}
+	topic.Name, topic.Description = localizeText(topic.Name, topic.Description, translations[topic.TopicID])
+
+	// Execute SQL statement to get the translations of the events
+	if topic.Events, err = withEventTranslations(store, topic.Events, locale); err != nil {
+		return x.Topic{}, err
+	}
+
+	return topic, nil
+}
+
+// withEventTranslations localizes events, which may belong to different
+// topics, to a locale. The events get copied, so that the original events stay
+// untouched.
+func withEventTranslations(store x.TranslationStore, events []x.Event, locale string) ([]x.Event, error) {
+
+	if locale == defaultLocale || len(events) == 0 {
+		return events, nil
+	}
+
+	// Execute SQL statement to get the translations of the events of the
+	// topics the events belong to
+	var topicIDs []int
+	seen := make(map[int]bool)
+	for _, event := range events {
+		if !seen[event.TopicID] {
+			seen[event.TopicID] = true
+			topicIDs = append(topicIDs, event.TopicID)
+		}
+	}
+	eventTranslations, err := store.GetEventTranslationsByTopics(topicIDs, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy events, so that the original events stay untouched
+	events = append([]x.Event(nil), events...)
+	localizeEvents(events, eventTranslations)
+
+	return events, nil
+}
+
+// translationOf finds the translation into a locale among translations, in
+// order to record it in the audit log. It returns nil, if there is none.
+func translationOf(translations []x.Translation, locale string) interface{} {
+
+	for _, translation := range translations {
+		if translation.Locale == locale {
+			return translation
+		}
+	}
+
+	return nil
+}
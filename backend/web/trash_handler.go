@@ -0,0 +1,338 @@
+// The web handler evolving around the trash, with HTTP-handler functions
+// consisting of "GET"- and "POST"-methods. It utilizes session management and
+// database access.
+//
+// Deleted topics, events and users get moved to the trash, from where admins
+// can restore them within the retention period. Afterwards they get purged
+// permanently by a background job.
+
+package web
+
+import (
+	"html/template"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/csrf"
+
+	x "github.com/mqrc81/IDPA-Jahreszahlen/backend"
+)
+
+const (
+	trashRetention     = 30 * 24 * time.Hour // duration until items in the trash get purged
+	trashPurgeInterval = time.Hour           // interval of the background job purging the trash
+)
+
+var (
+	// Parsed HTML-templates to be executed in their respective HTTP-handler
+	// functions when needed
+	trashListTemplate *template.Template
+)
+
+// init gets initialized with the package.
+//
+// All HTML-templates get parsed once to be executed when needed. This is way
+// more efficient than parsing the HTML-templates with every request.
+func init() {
+	if _testing { // skip initialization of templates when running tests
+		return
+	}
+
+	trashListTemplate = template.Must(newTemplate().
+		Funcs(template.FuncMap{
+			"daysLeft": func(deletedAt *time.Time) int {
+				return trashDaysLeft(deletedAt, time.Now())
+			},
+		}).
+		ParseFiles(layout, templatePath+"trash_list.html"))
+}
+
+// TrashHandler is the object for handlers to access sessions, database and
+// uploaded files.
+type TrashHandler struct {
+	store    x.Store
+	blobs    x.BlobStore
+	sessions *scs.SessionManager
+}
+
+// List is a GET-method that is accessible to any admin.
+//
+// It lists all topics, events and users in the trash, sorted by date of
+// deletion descending, each with the remaining days until being purged.
+func (h *TrashHandler) List() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Topics     []x.Topic
+		Events     []x.Event
+		Users      []x.User
+		TopicNames map[int]string // names of the topics of the events
+		Retention  int            // retention period in days
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Execute SQL statement to get topics in the trash
+		topics, err := h.store.GetDeletedTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get events in the trash
+		events, err := h.store.GetDeletedEvents()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get users in the trash
+		users, err := h.store.GetDeletedUsers()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get topics, in order to display the names
+		// of the topics of the events
+		activeTopics, err := h.store.GetTopics()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		topicNames := make(map[int]string)
+		for _, topic := range append(activeTopics, topics...) {
+			topicNames[topic.TopicID] = topic.Name
+		}
+
+		// Execute HTML-templates with data
+		if err = trashListTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
+			Topics:      topics,
+			Events:      events,
+			Users:       users,
+			TopicNames:  topicNames,
+			Retention:   int(trashRetention.Hours() / 24),
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// RestoreTopic is a POST-method that is accessible to any admin after List.
+//
+// It restores a topic from the trash and redirects to List.
+func (h *TrashHandler) RestoreTopic() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve topic ID from URL parameters
+		topicID, _ := strconv.Atoi(chi.URLParam(req, "topicID"))
+
+		// Execute SQL statement to restore a topic
+		if err := h.store.RestoreTopic(topicID); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record restoration in audit log
+		audit(h.store, req, auditRestore, auditTopic, topicID, nil, nil)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Thema wurde erfolgreich wiederhergestellt.")
+
+		// Redirect to trash
+		http.Redirect(res, req, "/trash", http.StatusSeeOther)
+	}
+}
+
+// RestoreEvent is a POST-method that is accessible to any admin after List.
+//
+// It restores an event from the trash and redirects to List.
+func (h *TrashHandler) RestoreEvent() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve event ID from URL parameters
+		eventID, _ := strconv.Atoi(chi.URLParam(req, "eventID"))
+
+		// Execute SQL statement to restore an event
+		if err := h.store.RestoreEvent(eventID); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record restoration in audit log
+		audit(h.store, req, auditRestore, auditEvent, eventID, nil, nil)
+
+		// Outdate the index of the autocompletion
+		suggestIndex.invalidate()
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Ereignis wurde erfolgreich wiederhergestellt.")
+
+		// Redirect to trash
+		http.Redirect(res, req, "/trash", http.StatusSeeOther)
+	}
+}
+
+// RestoreUser is a POST-method that is accessible to any admin after List.
+//
+// It restores a user from the trash and redirects to List.
+func (h *TrashHandler) RestoreUser() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve user ID from URL parameters
+		userID, _ := strconv.Atoi(chi.URLParam(req, "userID"))
+
+		// Execute SQL statement to restore a user
+		if err := h.store.RestoreUser(userID); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record restoration in audit log
+		audit(h.store, req, auditRestore, auditUser, userID, nil, nil)
+
+		// Add flash message
+		h.sessions.Put(req.Context(), "flash_success", "Benutzer wurde erfolgreich wiederhergestellt.")
+
+		// Redirect to trash
+		http.Redirect(res, req, "/trash", http.StatusSeeOther)
+	}
+}
+
+// RunTrashPurge purges the trash periodically, in order to permanently delete
+// topics, events and users after the retention period. It is meant to be run
+// in its own goroutine for as long as the application runs.
+func RunTrashPurge(store x.Store, blobs x.BlobStore) {
+
+	for {
+		if err := purgeTrash(store, blobs, time.Now().Add(-trashRetention)); err != nil {
+			log.Printf("error purging trash: %v", err)
+		}
+		time.Sleep(trashPurgeInterval)
+	}
+}
+
+// purgeTrash permanently deletes all topics, events and users, which were
+// moved to the trash before a certain point in time, including their uploaded
+// images, which aren't used elsewhere. Every purge gets recorded in the audit
+// log without a user.
+func purgeTrash(store x.Store, blobs x.BlobStore, before time.Time) error {
+
+	// Execute SQL statement to get topics in the trash
+	topics, err := store.GetDeletedTopics()
+	if err != nil {
+		return err
+	}
+
+	for _, topic := range topics {
+		if topic.DeletedAt.After(before) {
+			continue
+		}
+
+		// Execute SQL statement to purge a topic
+		if err = store.PurgeTopic(topic.TopicID); err != nil {
+			return err
+		}
+		purged(store, auditTopic, topic.TopicID, topic)
+
+		// Delete uploaded images of topic and its events, unless they're
+		// used elsewhere
+		deleteImage(store, blobs, topic.Image)
+		for _, event := range topic.Events {
+			deleteImage(store, blobs, event.Image)
+		}
+	}
+
+	// Execute SQL statement to get events in the trash
+	events, err := store.GetDeletedEvents()
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if event.DeletedAt.After(before) {
+			continue
+		}
+
+		// Execute SQL statement to purge an event
+		if err = store.PurgeEvent(event.EventID); err != nil {
+			return err
+		}
+		purged(store, auditEvent, event.EventID, event)
+
+		// Delete uploaded image of event, unless it's used elsewhere
+		deleteImage(store, blobs, event.Image)
+	}
+
+	// Execute SQL statement to get users in the trash
+	users, err := store.GetDeletedUsers()
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user.DeletedAt.After(before) {
+			continue
+		}
+
+		// Execute SQL statements to purge a user and the user's tokens
+		if err = store.DeleteTokensByUser(user.UserID); err != nil {
+			return err
+		}
+		if err = store.PurgeUser(user.UserID); err != nil {
+			return err
+		}
+		purged(store, auditUser, user.UserID, user)
+	}
+
+	return nil
+}
+
+// purged records a purge in the audit log. Since purges happen in the
+// background, there is neither a user nor a request.
+func purged(store x.AuditStore, targetType string, targetID int, target interface{}) {
+
+	if err := store.CreateAuditEntry(&x.AuditEntry{
+		Action:     auditPurge,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     auditSnapshot(target),
+		Date:       time.Now(),
+	}); err != nil {
+		log.Printf("error recording purge of %v %v in audit log: %v", targetType, targetID, err)
+	}
+}
+
+// trashDaysLeft calculates the remaining days until an item in the trash gets
+// purged, rounded up.
+// (Tested in handler_test.go)
+func trashDaysLeft(deletedAt *time.Time, now time.Time) int {
+
+	if deletedAt == nil {
+		return 0
+	}
+
+	left := deletedAt.Add(trashRetention).Sub(now)
+	if left <= 0 {
+		return 0
+	}
+
+	return int(math.Ceil(left.Hours() / 24))
+}
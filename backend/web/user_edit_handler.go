@@ -1,5 +1,6 @@
 // A branch of the user handler (for a better overview), which contains HTTP-
-// handlers that deal with editing a user's username, email and password.
+// handlers that deal with editing a user's username, email, password and
+// school class.
 
 package web
 
@@ -16,7 +17,8 @@ import (
 var (
 	// Parsed HTML-templates to be executed in their respective HTTP-handler
 	// functions when needed
-	usersEditUsernameTemplate, usersEditEmailTemplate, usersEditPasswordTemplate *template.Template
+	usersEditUsernameTemplate, usersEditEmailTemplate, usersEditPasswordTemplate,
+	usersEditClassTemplate *template.Template
 )
 
 // init gets initialized with the package.
@@ -28,9 +30,10 @@ func init() {
 		return
 	}
 
-	usersEditUsernameTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_edit_username.html"))
-	usersEditEmailTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_edit_email.html"))
-	usersEditPasswordTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_edit_password.html"))
+	usersEditUsernameTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_edit_username.html"))
+	usersEditEmailTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_edit_email.html"))
+	usersEditPasswordTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_edit_password.html"))
+	usersEditClassTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_edit_class.html"))
 }
 
 // EditUsername is a GET-method that is accessible to any user.
@@ -47,16 +50,6 @@ func (h *UserHandler) EditUsername() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error",
-				"Unzureichende Berechtigung. Loggen Sie sich zuerst ein, um Ihr Benutzernamen zu ändern.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Execute HTML-templates with data
 		if err := usersEditUsernameTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
@@ -84,11 +77,13 @@ func (h *UserHandler) EditUsernameSubmit() http.HandlerFunc {
 			Password:    req.FormValue("password"),
 		}
 
-		// Check if username is taken
-		_, err := h.store.GetUserByUsername(form.NewUsername)
-		// If error is nil, a user with that username was found, which means
-		// the username is already taken.
-		form.UsernameTaken = err == nil
+		// Check if username is taken, including by users in the trash
+		taken, err := h.store.IsUsernameTaken(form.NewUsername)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		form.UsernameTaken = taken
 
 		// Retrieve user from session
 		user := req.Context().Value("user").(x.User)
@@ -137,16 +132,6 @@ func (h *UserHandler) EditEmail() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error",
-				"Unzureichende Berechtigung. Loggen Sie sich zuerst ein, um Ihre Email zu ändern.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Execute HTML-templates with data
 		if err := usersEditEmailTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
@@ -174,11 +159,13 @@ func (h *UserHandler) EditEmailSubmit() http.HandlerFunc {
 			Password: req.FormValue("password"),
 		}
 
-		// Check if email is taken
-		_, err := h.store.GetUserByEmail(form.NewEmail)
-		// If error is nil, a user with that username was found, which means
-		// the email is already taken.
-		form.EmailTaken = err == nil
+		// Check if email is taken, including by users in the trash
+		taken, err := h.store.IsEmailTaken(form.NewEmail)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		form.EmailTaken = taken
 
 		// Retrieve user from session
 		user := req.Context().Value("user").(x.User)
@@ -228,16 +215,6 @@ func (h *UserHandler) EditPassword() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error",
-				"Unzureichende Berechtigung. Loggen Sie sich zuerst ein, um Ihr Passwort zu ändern.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Execute HTML-templates with data
 		if err := usersEditPasswordTemplate.Execute(res, data{
 			SessionData: GetSessionData(h.sessions, req.Context()),
@@ -303,3 +280,78 @@ func (h *UserHandler) EditPasswordSubmit() http.HandlerFunc {
 		http.Redirect(res, req, "/users/profile", http.StatusSeeOther)
 	}
 }
+
+// EditClass is a GET-method that is accessible to any user.
+//
+// It displays a form in which the school class of the user can be entered, in
+// order to compare scores within the class on the leaderboard.
+func (h *UserHandler) EditClass() http.HandlerFunc {
+
+	// Data to pass to HTML-templates
+	type data struct {
+		SessionData
+		CSRF template.HTML
+
+		Classes []string // existing classes to choose from
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Execute SQL statement to get existing classes
+		classes, err := h.store.GetClasses()
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute HTML-templates with data
+		if err = usersEditClassTemplate.Execute(res, data{
+			SessionData: GetSessionData(h.sessions, req.Context()),
+			CSRF:        csrf.TemplateField(req),
+			Classes:     classes,
+		}); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// EditClassSubmit is a POST-method that is accessible to any user after
+// EditClass.
+//
+// It validates the form from EditClass and redirects to EditClass in case of
+// an invalid input with the corresponding error message. In case of a valid
+// form, it stores the class of the user in the database and redirects to
+// Profile.
+func (h *UserHandler) EditClassSubmit() http.HandlerFunc {
+
+	return func(res http.ResponseWriter, req *http.Request) {
+
+		// Retrieve values from form
+		form := EditClassForm{
+			Class: formatInput(req.FormValue("class")),
+		}
+
+		// Validate form
+		if !form.Validate() {
+			h.sessions.Put(req.Context(), "form", form)
+			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
+			return
+		}
+
+		// Retrieve user from session
+		user := req.Context().Value("user").(x.User)
+
+		// Execute SQL statement to update the class of the user
+		if err := h.store.UpdateUserClass(user.UserID, form.Class); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Add flash message to session
+		h.sessions.Put(req.Context(), "flash_success", "Ihre Klasse wurde erfolgreich geändert.")
+
+		// Redirect to user's profile
+		http.Redirect(res, req, "/users/profile", http.StatusSeeOther)
+	}
+}
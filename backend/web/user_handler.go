@@ -48,12 +48,12 @@ func init() {
 		return
 	}
 
-	usersRegisterTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_register.html"))
-	usersLoginTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_login.html"))
-	usersProfileTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_profile.html"))
-	usersListTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_list.html"))
-	usersForgotPasswordTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_forgot_password.html"))
-	usersResetPasswordTemplate = template.Must(template.ParseFiles(layout, templatePath+"users_reset_password.html"))
+	usersRegisterTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_register.html"))
+	usersLoginTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_login.html"))
+	usersProfileTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_profile.html"))
+	usersListTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_list.html"))
+	usersForgotPasswordTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_forgot_password.html"))
+	usersResetPasswordTemplate = template.Must(newTemplate().ParseFiles(layout, templatePath+"users_reset_password.html"))
 }
 
 // UserHandler is the object for handlers to access sessions and database.
@@ -116,20 +116,17 @@ func (h *UserHandler) RegisterSubmit() http.HandlerFunc {
 			EmailTaken:    false,
 		}
 
-		// Check if username is taken
-		_, err := h.store.GetUserByUsername(form.Username)
-		if err == nil {
-			// If error is nil, a user with that username was found, which
-			// means the username is already taken
-			form.UsernameTaken = true
+		// Check if username is taken, including by users in the trash
+		var err error
+		if form.UsernameTaken, err = h.store.IsUsernameTaken(form.Username); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
-		// Check if email is taken
-		_, err = h.store.GetUserByEmail(form.Email)
-		if err == nil {
-			// If error is nil, a user with that email was found, which means
-			// the email is already taken
-			form.EmailTaken = true
+		// Check if email is taken, including by users in the trash
+		if form.EmailTaken, err = h.store.IsEmailTaken(form.Email); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		// Validate form
@@ -302,15 +299,6 @@ func (h *UserHandler) Logout() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if a user is logged in
-		user := req.Context().Value("user")
-		if user == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error", "Sie sind gar nicht eingeloggt.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Remove user ID from session
 		h.sessions.Remove(req.Context(), "user_id")
 
@@ -324,8 +312,9 @@ func (h *UserHandler) Logout() http.HandlerFunc {
 
 // Profile is a GET-Method that is accessible to any user.
 //
-// It displays a user's username and statistics, with the ability to change
-// username or password.
+// It displays a user's username and statistics, including the history of the
+// user's rating, the achievements and a heatmap of the daily activity with
+// streaks, with the ability to change username or password.
 func (h *UserHandler) Profile() http.HandlerFunc {
 
 	// Data to pass to HTML-templates
@@ -335,20 +324,24 @@ func (h *UserHandler) Profile() http.HandlerFunc {
 
 		User           x.User
 		ScoresPerTopic []scoresPerTopic
+		ScoresCount    int    // amount of quizzes played
+		AverageBest    string // average and best points per topic, encoded as JSON
+		ScoreHistory   string // history of the points per topic, encoded as JSON
+		Suggestions    []x.Suggestion
+		Rating         int        // current overall rating, 0 if there's none yet
+		TopicRatings   []x.Rating // current rating in every topic played
+		RatingChart    string     // history of the overall rating, encoded as JSON
+		Achievements   []achievementStatus
+		Heatmap        [][]heatmapDay // activity of the past year, as weeks of days
+		CurrentStreak  int            // consecutive days with activity up to today
+		LongestStreak  int            // highest amount of consecutive days with activity
+		ActiveDays     int            // days with activity of the past year
 	}
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Get user logged in
-		userInf := req.Context().Value("user")
-		if userInf == nil {
-			// If no user is logged in, then redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error",
-				"Unzureichende Berechtigung. Loggen Sie sich zuerst ein, um Ihr Profil zu betrachten.")
-			http.Redirect(res, req, "/users/login", http.StatusSeeOther)
-			return
-		}
-		user := userInf.(x.User)
+		// Retrieve user from session
+		user := req.Context().Value("user").(x.User)
 
 		// Execute SQL statement to get topics
 		topics, err := h.store.GetTopics()
@@ -357,38 +350,91 @@ func (h *UserHandler) Profile() http.HandlerFunc {
 			return
 		}
 
-		// Create chart with scores per topic
-		var scoresChart []scoresPerTopic
-		for _, topic := range topics {
-			// Execute SQL statement to get scores
-			scores, err := h.store.GetScoresByTopicAndUser(topic.TopicID, user.UserID)
-			if err != nil {
-				http.Error(res, err.Error(), http.StatusInternalServerError)
-				return
-			}
+		// Execute SQL statement to get the user's statistics in every topic
+		// played, which get combined with all topics
+		stats, err := h.store.GetTopicStatsByUser(user.UserID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		statistics := topicStatistics(topics, stats)
+		averageBest, err := encodeChart(averageBestChart(statistics, localeOf(req.Context())))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-			// Get user's best points for this topic and max possible points
-			var bestPoints int
-			if len(scores) > 0 {
-				bestPoints = scores[0].Points
-			}
-			maxPoints := phase1Questions*phase1Points + phase2Questions*phase2Points + min(topic.EventsCount,
-				phase3Questions)*phase3Points
+		// Execute SQL statement to get the history of the user's scores
+		scores, err := h.store.GetScoresByUser(user.UserID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		scoreHistory, err := encodeChart(scoreHistoryChart(scores, localeOf(req.Context())))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-			scoresChart = append(scoresChart, scoresPerTopic{
-				TopicName:  topic.Name,
-				Points:     bestPoints,
-				MaxPoints:  maxPoints,
-				Percentage: bestPoints * 100 / maxPoints,
-			})
+		// Execute SQL statement to get user's suggestions, in order to display
+		// whether they were accepted or rejected
+		suggestions, err := h.store.GetSuggestionsByUser(user.UserID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get the history of the user's ratings
+		ratings, err := h.store.GetRatingsByUser(user.UserID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		overallRatings, topicRatings := currentRatings(ratings)
+		var rating int
+		if len(overallRatings) > 0 {
+			rating = overallRatings[len(overallRatings)-1].Rating
+		}
+		ratingHistory, err := encodeChart(ratingChart(overallRatings, localeOf(req.Context())))
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get the user's unlocked achievements
+		unlocked, err := h.store.GetAchievementsByUser(user.UserID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Execute SQL statement to get the user's daily activity
+		activities, err := h.store.GetActivitiesByUser(user.UserID)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
 		}
+		days := activeDays(activities)
+		heatmap := activityHeatmap(activities, time.Now())
 
 		// Execute HTML-templates with data
 		if err = usersProfileTemplate.Execute(res, data{
 			SessionData:    GetSessionData(h.sessions, req.Context()),
 			CSRF:           csrf.TemplateField(req),
 			User:           user,
-			ScoresPerTopic: scoresChart,
+			ScoresPerTopic: statistics,
+			ScoresCount:    len(scores),
+			AverageBest:    averageBest,
+			ScoreHistory:   scoreHistory,
+			Suggestions:    suggestions,
+			Rating:         rating,
+			TopicRatings:   topicRatings,
+			RatingChart:    ratingHistory,
+			Achievements:   achievementStatuses(unlocked),
+			Heatmap:        heatmap,
+			CurrentStreak:  currentStreak(days, time.Now()),
+			LongestStreak:  longestStreak(days),
+			ActiveDays:     heatmapActiveDays(heatmap),
 		}); err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
@@ -396,15 +442,6 @@ func (h *UserHandler) Profile() http.HandlerFunc {
 	}
 }
 
-// scoresPerTopic represents 1 row of the chart with user's points for each
-// topic.
-type scoresPerTopic struct {
-	TopicName  string
-	Points     int
-	MaxPoints  int
-	Percentage int
-}
-
 // List is a GET-method that is accessible to any admin.
 //
 // It lists all users with the ability to delete a user, to promote a user to
@@ -426,17 +463,6 @@ func (h *UserHandler) List() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		// Check if an admin is logged in
-		user := req.Context().Value("user")
-		if user == nil || !user.(x.User).Admin {
-			// If no user is logged in or user logged in isn't an admin, then
-			// redirect back with flash message
-			h.sessions.Put(req.Context(), "flash_error",
-				"Unzureichende Berechtigung. Sie müssen als Admin eingeloggt sein, um alle Benutzer aufzulisten.")
-			http.Redirect(res, req, url(req.Referer()), http.StatusSeeOther)
-			return
-		}
-
 		// Execute SQL statement to get users
 		users, err := h.store.GetUsers()
 		if err != nil {
@@ -478,7 +504,7 @@ func (h *UserHandler) List() http.HandlerFunc {
 
 // Delete is a POST-method that is accessible to any admin.
 //
-// It deletes the user and redirects to List.
+// It moves the user to the trash and redirects to List.
 func (h *UserHandler) Delete() http.HandlerFunc {
 
 	return func(res http.ResponseWriter, req *http.Request) {
@@ -486,13 +512,22 @@ func (h *UserHandler) Delete() http.HandlerFunc {
 		// Retrieve user ID from URL parameters
 		userID, _ := strconv.Atoi(chi.URLParam(req, "userID"))
 
-		// Execute SQL statement to delete a user
-		err := h.store.DeleteUser(userID)
+		// Execute SQL statement to get the user, for the audit log
+		user, err := h.store.GetUser(userID)
 		if err != nil {
 			http.Error(res, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Execute SQL statement to move a user to the trash
+		if err = h.store.DeleteUser(userID); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Record deletion in audit log
+		audit(h.store, req, auditDelete, auditUser, userID, user, nil)
+
 		// Redirect to list of users
 		http.Redirect(res, req, "/users", http.StatusSeeOther)
 	}
@@ -516,6 +551,7 @@ func (h *UserHandler) Promote() http.HandlerFunc {
 		}
 
 		// Make user an admin
+		before := user
 		user.Admin = true
 
 		// Execute SQL statement to update user
@@ -524,6 +560,9 @@ func (h *UserHandler) Promote() http.HandlerFunc {
 			return
 		}
 
+		// Record promotion in audit log
+		audit(h.store, req, auditPromote, auditUser, userID, before, user)
+
 		// Redirect to list of users
 		http.Redirect(res, req, "/users", http.StatusSeeOther)
 	}
@@ -24,6 +24,23 @@ func min(nums ...int) int {
 	return minNumber
 }
 
+// max returns the largest out of all the numbers.
+func max(nums ...int) int {
+
+	if len(nums) == 0 {
+		return 0
+	}
+
+	maxNumber := nums[0]
+	for _, num := range nums {
+		if num > maxNumber {
+			maxNumber = num
+		}
+	}
+
+	return maxNumber
+}
+
 // abs returns the absolute value of a number.
 func abs(num int) int {
 
@@ -36,5 +36,91 @@ function addToResults(element) {
     document.getElementById('results').append(element);
 }
 
+// Used in the navigation bar. It fetches suggestions for the search-query
+// while typing and displays them as links below the search input.
+(function () {
+
+    // Declare variables
+    let input, dropdown, timeout, labels;
+    input = document.getElementById("search_input");
+    dropdown = document.getElementById("search_suggestions");
+    labels = {topic: "Thema", event: "Ereignis", page: "Seite"};
+    if (!input || !dropdown) {
+        return;
+    }
+
+    input.addEventListener("input", function () {
+        clearTimeout(timeout);
+
+        // Wait until the user stops typing
+        timeout = setTimeout(function () {
+            if (input.value.trim().length < 2) {
+                dropdown.classList.remove("show");
+                return;
+            }
+
+            fetch("/search/suggestions?q=" + encodeURIComponent(input.value), {
+                headers: {"Accept": "application/json"}
+            })
+                .then(response => response.json())
+                .then(data => {
+                    dropdown.innerHTML = "";
+                    data.suggestions.forEach(suggestion => {
+                        let link, subtitle;
+                        link = document.createElement("a");
+                        link.className = "dropdown-item";
+                        link.href = suggestion.url;
+                        link.textContent = suggestion.title;
+                        subtitle = document.createElement("small");
+                        subtitle.className = "text-gray-500 ml-2";
+                        subtitle.textContent = labels[suggestion.type] +
+                            (suggestion.subtitle ? " · " + suggestion.subtitle : "");
+                        link.append(subtitle);
+                        dropdown.append(link);
+                    });
+                    dropdown.classList.toggle("show", data.suggestions.length > 0);
+                });
+        }, 200);
+    });
+
+    // Hide suggestions when leaving the search input, after a click on a
+    // suggestion was registered
+    input.addEventListener("blur", function () {
+        setTimeout(function () {
+            dropdown.classList.remove("show");
+        }, 200);
+    });
+})();
+
+// Used in the profile. It draws a chart for every canvas with a
+// 'data-chart'-attribute, which contains the type, labels and datasets as
+// JSON. Gaps in the values of a dataset get bridged.
+(function () {
+    const colors = ["#4e73df", "#1cc88a", "#36b9cc", "#f6c23e", "#e74a3b", "#858796"];
+    document.querySelectorAll("canvas[data-chart]").forEach(canvas => {
+        let chart = JSON.parse(canvas.dataset.chart);
+        let single = chart.datasets.length === 1;
+        new Chart(canvas, {
+            type: chart.type,
+            data: {
+                labels: chart.labels,
+                datasets: chart.datasets.map((dataset, i) => ({
+                    label: dataset.label,
+                    data: dataset.values,
+                    borderColor: colors[i % colors.length],
+                    backgroundColor: chart.type === "bar" ? colors[i % colors.length] : "rgba(78, 115, 223, 0.05)",
+                    fill: single,
+                    lineTension: 0.3,
+                    spanGaps: true
+                }))
+            },
+            options: {
+                maintainAspectRatio: false,
+                legend: {display: !single}
+            }
+        });
+    });
+})();
+
 // Closes the flash message
 $('.alert').alert();